@@ -11,14 +11,15 @@ import (
 
 
 type Config struct {
-	ServiceName   string         `yaml:"serviceName"`
-	Port          int            `yaml:"port"`
-	EnableSwagger bool           `yaml:"enableSwagger"`
-	Database      DatabaseConfig `yaml:"database"`
-	Kafka         KafkaConfig    `yaml:"kafka"`
-	Topics        TopicsConfig   `yaml:"topics"`
-	Redis         RedisConfig    `yaml:"redis"`
-	Minio         MinioConfig    `yaml:"minio"`
+	ServiceName   string           `yaml:"serviceName"`
+	Port          int              `yaml:"port"`
+	EnableSwagger bool             `yaml:"enableSwagger"`
+	Database      DatabaseConfig   `yaml:"database"`
+	Kafka         KafkaConfig      `yaml:"kafka"`
+	Topics        TopicsConfig     `yaml:"topics"`
+	Redis         RedisConfig      `yaml:"redis"`
+	Minio         MinioConfig      `yaml:"minio"`
+	Validation    ValidationConfig `yaml:"validation"`
 }
 
 
@@ -150,6 +151,207 @@ func (c *Config) applyEnv() {
 		c.Minio.Bucket = env
 	}
 
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_RULES")); env != "" {
+		c.Validation.Rules = splitList(env)
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_REQUIRED_PARTS")); env != "" {
+		c.Validation.RequiredParts = splitList(env)
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_MAX_DOCUMENT_BYTES")); env != "" {
+		if maxBytes, err := strconv.ParseInt(env, 10, 64); err == nil {
+			c.Validation.MaxDocumentBytes = maxBytes
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_CYRILLIC_ENABLED")); env != "" {
+		if enabled, err := strconv.ParseBool(env); err == nil {
+			c.Validation.Cyrillic.Enabled = &enabled
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_CYRILLIC_MIN_PERCENT")); env != "" {
+		if percent, err := strconv.ParseFloat(env, 64); err == nil {
+			c.Validation.Cyrillic.MinPercent = percent
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_LANGUAGE_ENABLED")); env != "" {
+		if enabled, err := strconv.ParseBool(env); err == nil {
+			c.Validation.Language.Enabled = &enabled
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_LANGUAGE_PRIMARY")); env != "" {
+		c.Validation.Language.Primary = env
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_LANGUAGE_MIN_PERCENT")); env != "" {
+		if percent, err := strconv.ParseFloat(env, 64); err == nil {
+			c.Validation.Language.MinPercent = percent
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_DATES_ENABLED")); env != "" {
+		if enabled, err := strconv.ParseBool(env); err == nil {
+			c.Validation.Dates.Enabled = &enabled
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_DATES_MAX_SPAN")); env != "" {
+		if span, err := ParseCalendarSpan(env); err == nil {
+			c.Validation.Dates.MaxSpan = span
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_DATES_TWO_DIGIT_YEAR_PIVOT")); env != "" {
+		if pivot, err := strconv.Atoi(env); err == nil {
+			c.Validation.Dates.TwoDigitYearPivot = &pivot
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_DATES_LOCALE")); env != "" {
+		c.Validation.Dates.Locale = env
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_DATES_AMBIGUOUS")); env != "" {
+		c.Validation.Dates.Ambiguous = env
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_DATES_WINDOW")); env != "" {
+		if span, err := ParseCalendarSpan(env); err == nil {
+			c.Validation.Dates.Window = span
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_DATES_NO_FUTURE")); env != "" {
+		if enabled, err := strconv.ParseBool(env); err == nil {
+			c.Validation.Dates.NoFuture = &enabled
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_DATES_MATCH_DOCUMENT_DATE")); env != "" {
+		if enabled, err := strconv.ParseBool(env); err == nil {
+			c.Validation.Dates.MatchDocumentDate = &enabled
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_DATES_ORDERED_RANGES")); env != "" {
+		if enabled, err := strconv.ParseBool(env); err == nil {
+			c.Validation.Dates.OrderedRanges = &enabled
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_ACTIVE_CONTENT_ENABLED")); env != "" {
+		if enabled, err := strconv.ParseBool(env); err == nil {
+			c.Validation.ActiveContent.Enabled = &enabled
+		}
+	}
+	// VALIDATION_ACTIVE_CONTENT_POLICIES: "macro=reject,external_relationship=warn".
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_ACTIVE_CONTENT_POLICIES")); env != "" {
+		if c.Validation.ActiveContent.Policies == nil {
+			c.Validation.ActiveContent.Policies = make(map[string]string)
+		}
+		for _, item := range splitList(env) {
+			if kind, policy, ok := strings.Cut(item, "="); ok {
+				c.Validation.ActiveContent.Policies[strings.TrimSpace(kind)] = strings.TrimSpace(policy)
+			}
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_PII_ENABLED")); env != "" {
+		if enabled, err := strconv.ParseBool(env); err == nil {
+			c.Validation.PII.Enabled = &enabled
+		}
+	}
+	// VALIDATION_PII_POLICIES: "passport=reject,inn=require".
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_PII_POLICIES")); env != "" {
+		if c.Validation.PII.Policies == nil {
+			c.Validation.PII.Policies = make(map[string]string)
+		}
+		for _, item := range splitList(env) {
+			if kind, policy, ok := strings.Cut(item, "="); ok {
+				c.Validation.PII.Policies[strings.TrimSpace(kind)] = strings.TrimSpace(policy)
+			}
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_REQUISITES_ENABLED")); env != "" {
+		if enabled, err := strconv.ParseBool(env); err == nil {
+			c.Validation.Requisites.Enabled = &enabled
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_REQUISITES_REQUIRE_PARTY")); env != "" {
+		if enabled, err := strconv.ParseBool(env); err == nil {
+			c.Validation.Requisites.RequireParty = &enabled
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_PHRASES_ENABLED")); env != "" {
+		if enabled, err := strconv.ParseBool(env); err == nil {
+			c.Validation.Phrases.Enabled = &enabled
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_PHRASES_REQUIRED")); env != "" {
+		c.Validation.Phrases.Required = splitList(env)
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_PHRASES_FORBIDDEN")); env != "" {
+		c.Validation.Phrases.Forbidden = splitList(env)
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_PHRASES_STEMMING")); env != "" {
+		if enabled, err := strconv.ParseBool(env); err == nil {
+			c.Validation.Phrases.Stemming = &enabled
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_REVIEW_ENABLED")); env != "" {
+		if enabled, err := strconv.ParseBool(env); err == nil {
+			c.Validation.Review.Enabled = &enabled
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_REVIEW_REVISIONS")); env != "" {
+		c.Validation.Review.Revisions = env
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_REVIEW_COMMENTS")); env != "" {
+		c.Validation.Review.Comments = env
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_METADATA_ENABLED")); env != "" {
+		if enabled, err := strconv.ParseBool(env); err == nil {
+			c.Validation.Metadata.Enabled = &enabled
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_METADATA_MAX_AFTER_CREATED")); env != "" {
+		if span, err := ParseCalendarSpan(env); err == nil {
+			c.Validation.Metadata.MaxAfterCreated = span
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_METADATA_AUTHORS")); env != "" {
+		c.Validation.Metadata.Authors = splitList(env)
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_METADATA_COMPANIES")); env != "" {
+		c.Validation.Metadata.Companies = splitList(env)
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_MAX_OBJECT_BYTES")); env != "" {
+		if maxBytes, err := strconv.ParseInt(env, 10, 64); err == nil {
+			c.Validation.Limits.MaxObjectBytes = maxBytes
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_MAX_ENTRY_BYTES")); env != "" {
+		if maxBytes, err := strconv.ParseInt(env, 10, 64); err == nil {
+			c.Validation.Limits.MaxEntryBytes = maxBytes
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_MAX_UNCOMPRESSED_BYTES")); env != "" {
+		if maxBytes, err := strconv.ParseInt(env, 10, 64); err == nil {
+			c.Validation.Limits.MaxUncompressedBytes = maxBytes
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_MAX_COMPRESSION_RATIO")); env != "" {
+		if ratio, err := strconv.ParseFloat(env, 64); err == nil {
+			c.Validation.Limits.MaxCompressionRatio = ratio
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_MAX_ENTRIES")); env != "" {
+		if entries, err := strconv.Atoi(env); err == nil {
+			c.Validation.Limits.MaxEntries = entries
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_MAX_XML_DEPTH")); env != "" {
+		if depth, err := strconv.Atoi(env); err == nil {
+			c.Validation.Limits.MaxXMLDepth = depth
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_MAX_PDF_OPERATORS")); env != "" {
+		if operators, err := strconv.Atoi(env); err == nil {
+			c.Validation.Limits.MaxPDFOperators = operators
+		}
+	}
+	if env := strings.TrimSpace(os.Getenv("VALIDATION_MAX_PDF_TEXT_BYTES")); env != "" {
+		if maxBytes, err := strconv.ParseInt(env, 10, 64); err == nil {
+			c.Validation.Limits.MaxPDFTextBytes = maxBytes
+		}
+	}
+
 
 	if env := strings.TrimSpace(os.Getenv("DB_SHARDS")); env != "" {
 		parts := strings.Split(env, ",")
@@ -164,4 +366,13 @@ func (c *Config) applyEnv() {
 	}
 }
 
-
+func splitList(env string) []string {
+	parts := strings.Split(env, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
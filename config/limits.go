@@ -0,0 +1,61 @@
+package config
+
+const (
+	DefaultMaxObjectBytes       int64   = 64 << 20
+	DefaultMaxEntryBytes        int64   = 64 << 20
+	DefaultMaxUncompressedBytes int64   = 256 << 20
+	DefaultMaxCompressionRatio  float64 = 100
+	DefaultMaxEntries                   = 10000
+	DefaultMaxXMLDepth                  = 256
+	DefaultMaxPDFOperators              = 10000000
+	DefaultMaxPDFTextBytes      int64   = 64 << 20
+)
+
+// LimitsConfig ограничивает ресурсы на разбор одного документа и действует
+// для всех профилей. Нулевые значения заменяются значениями по умолчанию.
+type LimitsConfig struct {
+	// MaxObjectBytes — размер объекта целиком, в том числе при скачивании из MinIO.
+	MaxObjectBytes int64 `yaml:"maxObjectBytes"`
+	// MaxEntryBytes — распакованный размер одной записи архива или потока PDF.
+	MaxEntryBytes int64 `yaml:"maxEntryBytes"`
+	// MaxUncompressedBytes — суммарный объём распакованных данных за документ, включая потоки PDF.
+	MaxUncompressedBytes int64 `yaml:"maxUncompressedBytes"`
+	// MaxCompressionRatio — допустимое отношение распакованного размера записи или потока PDF к сжатому.
+	MaxCompressionRatio float64 `yaml:"maxCompressionRatio"`
+	MaxEntries          int     `yaml:"maxEntries"`
+	// MaxXMLDepth — глубина вложенности элементов в частях XML, а в PDF —
+	// массивов и словарей в потоках содержимого и форм XObject друг в друга.
+	MaxXMLDepth int `yaml:"maxXMLDepth"`
+	// MaxPDFOperators — число операторов содержимого PDF за документ с учётом
+	// повторных вызовов форм; MaxPDFTextBytes — объём извлечённого из PDF текста.
+	MaxPDFOperators int   `yaml:"maxPDFOperators"`
+	MaxPDFTextBytes int64 `yaml:"maxPDFTextBytes"`
+}
+
+func (l LimitsConfig) WithDefaults() LimitsConfig {
+	if l.MaxObjectBytes <= 0 {
+		l.MaxObjectBytes = DefaultMaxObjectBytes
+	}
+	if l.MaxEntryBytes <= 0 {
+		l.MaxEntryBytes = DefaultMaxEntryBytes
+	}
+	if l.MaxUncompressedBytes <= 0 {
+		l.MaxUncompressedBytes = DefaultMaxUncompressedBytes
+	}
+	if l.MaxCompressionRatio <= 0 {
+		l.MaxCompressionRatio = DefaultMaxCompressionRatio
+	}
+	if l.MaxEntries <= 0 {
+		l.MaxEntries = DefaultMaxEntries
+	}
+	if l.MaxXMLDepth <= 0 {
+		l.MaxXMLDepth = DefaultMaxXMLDepth
+	}
+	if l.MaxPDFOperators <= 0 {
+		l.MaxPDFOperators = DefaultMaxPDFOperators
+	}
+	if l.MaxPDFTextBytes <= 0 {
+		l.MaxPDFTextBytes = DefaultMaxPDFTextBytes
+	}
+	return l
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CalendarSpan — календарный интервал: годы и месяцы прибавляются через
+// time.AddDate, поэтому високосные годы и разная длина месяцев учитываются.
+type CalendarSpan struct {
+	Years  int
+	Months int
+	Days   int
+}
+
+// ParseCalendarSpan разбирает запись вида "3y", "18m", "2y6m", "1y2m15d".
+func ParseCalendarSpan(raw string) (CalendarSpan, error) {
+	var span CalendarSpan
+	s := strings.ToLower(strings.TrimSpace(raw))
+	if s == "" {
+		return span, fmt.Errorf("пустой интервал")
+	}
+
+	num := ""
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			num += string(r)
+		case r == 'y' || r == 'm' || r == 'd':
+			if num == "" {
+				return CalendarSpan{}, fmt.Errorf("недопустимый интервал %q", raw)
+			}
+			n, err := strconv.Atoi(num)
+			if err != nil {
+				return CalendarSpan{}, fmt.Errorf("недопустимый интервал %q: %w", raw, err)
+			}
+			switch r {
+			case 'y':
+				span.Years += n
+			case 'm':
+				span.Months += n
+			case 'd':
+				span.Days += n
+			}
+			num = ""
+		default:
+			return CalendarSpan{}, fmt.Errorf("недопустимый интервал %q", raw)
+		}
+	}
+	if num != "" {
+		return CalendarSpan{}, fmt.Errorf("у интервала %q не указана единица (y, m, d)", raw)
+	}
+	return span, nil
+}
+
+func (s *CalendarSpan) UnmarshalYAML(value *yaml.Node) error {
+	parsed, err := ParseCalendarSpan(value.Value)
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+func (s CalendarSpan) IsZero() bool {
+	return s.Years == 0 && s.Months == 0 && s.Days == 0
+}
+
+func (s CalendarSpan) AddTo(t time.Time) time.Time {
+	return t.AddDate(s.Years, s.Months, s.Days)
+}
+
+func (s CalendarSpan) String() string {
+	var b strings.Builder
+	if s.Years != 0 {
+		fmt.Fprintf(&b, "%dy", s.Years)
+	}
+	if s.Months != 0 {
+		fmt.Fprintf(&b, "%dm", s.Months)
+	}
+	if s.Days != 0 || b.Len() == 0 {
+		fmt.Fprintf(&b, "%dd", s.Days)
+	}
+	return b.String()
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseCalendarSpan(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want CalendarSpan
+	}{
+		{raw: "3y", want: CalendarSpan{Years: 3}},
+		{raw: "18m", want: CalendarSpan{Months: 18}},
+		{raw: "2Y6M", want: CalendarSpan{Years: 2, Months: 6}},
+		{raw: "1y2m15d", want: CalendarSpan{Years: 1, Months: 2, Days: 15}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseCalendarSpan(tt.raw)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, raw := range []string{"", "3", "y", "3w", "-1y"} {
+		_, err := ParseCalendarSpan(raw)
+		require.Error(t, err, raw)
+	}
+}
+
+func TestCalendarSpan_YAMLAndAddTo(t *testing.T) {
+	var cfg DateRuleConfig
+	require.NoError(t, yaml.Unmarshal([]byte("maxSpan: 1y1m"), &cfg))
+	require.Equal(t, CalendarSpan{Years: 1, Months: 1}, cfg.MaxSpan)
+
+	start := time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC), cfg.MaxSpan.AddTo(start))
+}
@@ -0,0 +1,312 @@
+package config
+
+const DefaultProfileName = "default"
+
+// ValidationConfig — профиль по умолчанию (поля верхнего уровня) и именованные
+// профили. Именованный профиль наследует от профиля по умолчанию всё, что в нём не задано.
+type ValidationConfig struct {
+	ProfileConfig `yaml:",inline"`
+	Profiles      map[string]ProfileConfig `yaml:"profiles"`
+	// Tenants сопоставляет tenant_id из события с именем профиля.
+	Tenants map[string]string `yaml:"tenants"`
+	Limits  LimitsConfig      `yaml:"limits"`
+}
+
+// ProfileConfig задаёт набор и порядок правил; пустой список означает правила по умолчанию.
+type ProfileConfig struct {
+	Rules []string `yaml:"rules"`
+	// RequiredParts — обязательные записи DOCX; у других форматов свои встроенные списки.
+	RequiredParts    []string            `yaml:"requiredParts"`
+	MaxDocumentBytes int64               `yaml:"maxDocumentBytes"`
+	Cyrillic         CyrillicRuleConfig  `yaml:"cyrillic"`
+	Language         LanguageRuleConfig  `yaml:"language"`
+	Dates            DateRuleConfig      `yaml:"dates"`
+	ActiveContent    ActiveContentConfig `yaml:"activeContent"`
+	Metadata         MetadataRuleConfig  `yaml:"metadata"`
+	PII              PIIConfig           `yaml:"pii"`
+	Requisites       RequisitesConfig    `yaml:"requisites"`
+	Phrases          PhrasesConfig       `yaml:"phrases"`
+	Review           ReviewConfig        `yaml:"review"`
+}
+
+// Enabled равный nil означает, что правило включено. Parts ограничивает
+// источники текста (body, header, footer, footnotes, endnotes, comments); пусто — все.
+type CyrillicRuleConfig struct {
+	Enabled    *bool    `yaml:"enabled"`
+	MinPercent float64  `yaml:"minPercent"`
+	Parts      []string `yaml:"parts"`
+}
+
+// LanguageRuleConfig — правило language: Primary — требуемый язык (ru, uk, be,
+// bg, sr, en, ru-Latn), MinPercent — его наименьшая доля среди букв текста.
+// Без Primary правило не включается.
+type LanguageRuleConfig struct {
+	Enabled    *bool    `yaml:"enabled"`
+	Primary    string   `yaml:"primary"`
+	MinPercent float64  `yaml:"minPercent"`
+	Parts      []string `yaml:"parts"`
+}
+
+// TwoDigitYearPivot разделяет двузначные годы: меньшие относятся к 2000-м,
+// остальные к 1900-м; не задан — 50, 0 — все двузначные годы в 1900-х. Locale задаёт порядок
+// дня и месяца в датах через косую черту: ru и en-GB — ДД/ММ, en-US — ММ/ДД.
+// Ambiguous — как date_span учитывает даты, читаемые обоими способами.
+//
+// Остальные поля — ограничения правила date_constraints, которое включается,
+// если задано хотя бы одно: Window — все даты не дальше этого интервала от
+// момента обработки, NoFuture — нет дат позже сегодняшней, MatchDocumentDate —
+// в тексте есть дата из document_date события, OrderedRanges — в парах
+// «от … до …» и «с … по …» первая дата не позже второй.
+type DateRuleConfig struct {
+	Enabled           *bool        `yaml:"enabled"`
+	MaxSpan           CalendarSpan `yaml:"maxSpan"`
+	Parts             []string     `yaml:"parts"`
+	TwoDigitYearPivot *int         `yaml:"twoDigitYearPivot"`
+	Locale            string       `yaml:"locale"`
+	Ambiguous         string       `yaml:"ambiguous"`
+	Window            CalendarSpan `yaml:"window"`
+	NoFuture          *bool        `yaml:"noFuture"`
+	MatchDocumentDate *bool        `yaml:"matchDocumentDate"`
+	OrderedRanges     *bool        `yaml:"orderedRanges"`
+}
+
+// Учёт неоднозначных дат вроде 03/04/2024: по локали, не учитывать,
+// учитывать оба прочтения или отклонять документ.
+const (
+	AmbiguousDatesLocale = "locale"
+	AmbiguousDatesIgnore = "ignore"
+	AmbiguousDatesBoth   = "both"
+	AmbiguousDatesReject = "reject"
+)
+
+// Политики для находок правил: пропустить, предупредить или отклонить документ.
+// PolicyRequire отклоняет документ, в котором находок этого вида нет;
+// PolicyForbid — синоним PolicyReject в pii.policies.
+const (
+	PolicyAllow   = "allow"
+	PolicyWarn    = "warn"
+	PolicyReject  = "reject"
+	PolicyRequire = "require"
+	PolicyForbid  = "forbid"
+)
+
+// ActiveContentConfig задаёт политику для видов активного содержимого: macro,
+// ole_object, activex, external_relationship, external_hyperlink, remote_template, dde.
+// Не указанные виды получают встроенную политику.
+type ActiveContentConfig struct {
+	Enabled  *bool             `yaml:"enabled"`
+	Policies map[string]string `yaml:"policies"`
+}
+
+// PIIConfig задаёт политику для видов персональных данных в тексте: passport,
+// snils, inn, ogrn, card, phone, email. Допустимы allow, warn, reject (или forbid)
+// и require; не указанные виды только попадают в отчёт.
+type PIIConfig struct {
+	Enabled  *bool             `yaml:"enabled"`
+	Policies map[string]string `yaml:"policies"`
+	Parts    []string          `yaml:"parts"`
+}
+
+// RequisitesConfig — правило requisites: ИНН, КПП, ОГРН, БИК, расчётные и
+// корреспондентские счета, подписанные в тексте («ИНН», «р/с», ...), проверяются
+// по контрольным числам. RequireParty требует хотя бы один полный блок реквизитов
+// стороны: ИНН, БИК, расчётный и корреспондентский счета, для организации ещё КПП.
+// Правило включается только явно, enabled: true.
+type RequisitesConfig struct {
+	Enabled      *bool    `yaml:"enabled"`
+	RequireParty *bool    `yaml:"requireParty"`
+	Parts        []string `yaml:"parts"`
+}
+
+// PhrasesConfig — правило phrases: Required должны встретиться в тексте, Forbidden —
+// нет. Сравнение без учёта регистра, «ё» равна «е»; со Stemming слова фразы и
+// текста сравниваются по основам, так что «Подписи сторон» найдётся и в «подписями сторон».
+// Без фраз правило не включается.
+type PhrasesConfig struct {
+	Enabled   *bool    `yaml:"enabled"`
+	Required  []string `yaml:"required"`
+	Forbidden []string `yaml:"forbidden"`
+	Stemming  *bool    `yaml:"stemming"`
+	Parts     []string `yaml:"parts"`
+}
+
+// ReviewConfig — правило review: политика (allow, warn, reject) для не принятых
+// правок режима рецензирования и для неразрешённых примечаний DOCX. Пусто — warn.
+type ReviewConfig struct {
+	Enabled   *bool  `yaml:"enabled"`
+	Revisions string `yaml:"revisions"`
+	Comments  string `yaml:"comments"`
+}
+
+// MetadataRuleConfig — проверки свойств документа. Enabled включает проверку дат
+// создания и изменения (nil — выключена); MaxAfterCreated — насколько дата в
+// тексте может быть позже даты создания. Authors и Companies — допустимые автор
+// и организация; пустой список ничего не ограничивает.
+type MetadataRuleConfig struct {
+	Enabled         *bool        `yaml:"enabled"`
+	MaxAfterCreated CalendarSpan `yaml:"maxAfterCreated"`
+	Authors         []string     `yaml:"authors"`
+	Companies       []string     `yaml:"companies"`
+}
+
+// Profile возвращает итоговые настройки профиля с учётом наследования.
+func (v ValidationConfig) Profile(name string) (ProfileConfig, bool) {
+	if name == "" || name == DefaultProfileName {
+		return v.ProfileConfig, true
+	}
+	override, ok := v.Profiles[name]
+	if !ok {
+		return ProfileConfig{}, false
+	}
+	return v.ProfileConfig.merge(override), true
+}
+
+// ProfileNames возвращает имена всех профилей, включая профиль по умолчанию.
+func (v ValidationConfig) ProfileNames() []string {
+	names := []string{DefaultProfileName}
+	for name := range v.Profiles {
+		if name != DefaultProfileName {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (p ProfileConfig) merge(o ProfileConfig) ProfileConfig {
+	out := p
+	if len(o.Rules) > 0 {
+		out.Rules = o.Rules
+	}
+	if len(o.RequiredParts) > 0 {
+		out.RequiredParts = o.RequiredParts
+	}
+	if o.MaxDocumentBytes != 0 {
+		out.MaxDocumentBytes = o.MaxDocumentBytes
+	}
+	if o.Cyrillic.Enabled != nil {
+		out.Cyrillic.Enabled = o.Cyrillic.Enabled
+	}
+	if o.Cyrillic.MinPercent != 0 {
+		out.Cyrillic.MinPercent = o.Cyrillic.MinPercent
+	}
+	if len(o.Cyrillic.Parts) > 0 {
+		out.Cyrillic.Parts = o.Cyrillic.Parts
+	}
+	if o.Language.Enabled != nil {
+		out.Language.Enabled = o.Language.Enabled
+	}
+	if o.Language.Primary != "" {
+		out.Language.Primary = o.Language.Primary
+	}
+	if o.Language.MinPercent != 0 {
+		out.Language.MinPercent = o.Language.MinPercent
+	}
+	if len(o.Language.Parts) > 0 {
+		out.Language.Parts = o.Language.Parts
+	}
+	if o.Dates.Enabled != nil {
+		out.Dates.Enabled = o.Dates.Enabled
+	}
+	if !o.Dates.MaxSpan.IsZero() {
+		out.Dates.MaxSpan = o.Dates.MaxSpan
+	}
+	if len(o.Dates.Parts) > 0 {
+		out.Dates.Parts = o.Dates.Parts
+	}
+	if o.Dates.TwoDigitYearPivot != nil {
+		out.Dates.TwoDigitYearPivot = o.Dates.TwoDigitYearPivot
+	}
+	if o.Dates.Locale != "" {
+		out.Dates.Locale = o.Dates.Locale
+	}
+	if o.Dates.Ambiguous != "" {
+		out.Dates.Ambiguous = o.Dates.Ambiguous
+	}
+	if !o.Dates.Window.IsZero() {
+		out.Dates.Window = o.Dates.Window
+	}
+	if o.Dates.NoFuture != nil {
+		out.Dates.NoFuture = o.Dates.NoFuture
+	}
+	if o.Dates.MatchDocumentDate != nil {
+		out.Dates.MatchDocumentDate = o.Dates.MatchDocumentDate
+	}
+	if o.Dates.OrderedRanges != nil {
+		out.Dates.OrderedRanges = o.Dates.OrderedRanges
+	}
+	if o.ActiveContent.Enabled != nil {
+		out.ActiveContent.Enabled = o.ActiveContent.Enabled
+	}
+	if len(o.ActiveContent.Policies) > 0 {
+		policies := make(map[string]string, len(p.ActiveContent.Policies)+len(o.ActiveContent.Policies))
+		for kind, policy := range p.ActiveContent.Policies {
+			policies[kind] = policy
+		}
+		for kind, policy := range o.ActiveContent.Policies {
+			policies[kind] = policy
+		}
+		out.ActiveContent.Policies = policies
+	}
+	if o.PII.Enabled != nil {
+		out.PII.Enabled = o.PII.Enabled
+	}
+	if len(o.PII.Policies) > 0 {
+		policies := make(map[string]string, len(p.PII.Policies)+len(o.PII.Policies))
+		for kind, policy := range p.PII.Policies {
+			policies[kind] = policy
+		}
+		for kind, policy := range o.PII.Policies {
+			policies[kind] = policy
+		}
+		out.PII.Policies = policies
+	}
+	if len(o.PII.Parts) > 0 {
+		out.PII.Parts = o.PII.Parts
+	}
+	if o.Requisites.Enabled != nil {
+		out.Requisites.Enabled = o.Requisites.Enabled
+	}
+	if o.Requisites.RequireParty != nil {
+		out.Requisites.RequireParty = o.Requisites.RequireParty
+	}
+	if len(o.Requisites.Parts) > 0 {
+		out.Requisites.Parts = o.Requisites.Parts
+	}
+	if o.Phrases.Enabled != nil {
+		out.Phrases.Enabled = o.Phrases.Enabled
+	}
+	if len(o.Phrases.Required) > 0 {
+		out.Phrases.Required = o.Phrases.Required
+	}
+	if len(o.Phrases.Forbidden) > 0 {
+		out.Phrases.Forbidden = o.Phrases.Forbidden
+	}
+	if o.Phrases.Stemming != nil {
+		out.Phrases.Stemming = o.Phrases.Stemming
+	}
+	if len(o.Phrases.Parts) > 0 {
+		out.Phrases.Parts = o.Phrases.Parts
+	}
+	if o.Review.Enabled != nil {
+		out.Review.Enabled = o.Review.Enabled
+	}
+	if o.Review.Revisions != "" {
+		out.Review.Revisions = o.Review.Revisions
+	}
+	if o.Review.Comments != "" {
+		out.Review.Comments = o.Review.Comments
+	}
+	if o.Metadata.Enabled != nil {
+		out.Metadata.Enabled = o.Metadata.Enabled
+	}
+	if !o.Metadata.MaxAfterCreated.IsZero() {
+		out.Metadata.MaxAfterCreated = o.Metadata.MaxAfterCreated
+	}
+	if len(o.Metadata.Authors) > 0 {
+		out.Metadata.Authors = o.Metadata.Authors
+	}
+	if len(o.Metadata.Companies) > 0 {
+		out.Metadata.Companies = o.Metadata.Companies
+	}
+	return out
+}
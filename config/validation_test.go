@@ -0,0 +1,82 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidationConfig_ProfileInheritance(t *testing.T) {
+	raw := `
+rules: [required_parts, cyrillic_ratio, date_span]
+cyrillic:
+  minPercent: 90
+dates:
+  maxSpan: 3y
+profiles:
+  bilingual:
+    cyrillic:
+      minPercent: 70
+  archive:
+    rules: [required_parts]
+    dates:
+      enabled: false
+tenants:
+  tenant-42: bilingual
+`
+	var cfg ValidationConfig
+	require.NoError(t, yaml.Unmarshal([]byte(raw), &cfg))
+
+	def, ok := cfg.Profile("")
+	require.True(t, ok)
+	require.Equal(t, 90.0, def.Cyrillic.MinPercent)
+
+	bilingual, ok := cfg.Profile("bilingual")
+	require.True(t, ok)
+	require.Equal(t, 70.0, bilingual.Cyrillic.MinPercent)
+	require.Equal(t, CalendarSpan{Years: 3}, bilingual.Dates.MaxSpan)
+	require.Equal(t, []string{"required_parts", "cyrillic_ratio", "date_span"}, bilingual.Rules)
+
+	archive, ok := cfg.Profile("archive")
+	require.True(t, ok)
+	require.Equal(t, []string{"required_parts"}, archive.Rules)
+	require.False(t, *archive.Dates.Enabled)
+
+	_, ok = cfg.Profile("unknown")
+	require.False(t, ok)
+	require.Equal(t, "bilingual", cfg.Tenants["tenant-42"])
+}
+
+func TestLimitsConfig_WithDefaults(t *testing.T) {
+	var cfg ValidationConfig
+	require.NoError(t, yaml.Unmarshal([]byte("limits:\n  maxEntries: 50\n  maxCompressionRatio: 20\n"), &cfg))
+
+	limits := cfg.Limits.WithDefaults()
+	require.Equal(t, 50, limits.MaxEntries)
+	require.Equal(t, 20.0, limits.MaxCompressionRatio)
+	require.Equal(t, DefaultMaxEntryBytes, limits.MaxEntryBytes)
+	require.Equal(t, DefaultMaxXMLDepth, limits.MaxXMLDepth)
+	require.Equal(t, DefaultMaxPDFOperators, limits.MaxPDFOperators)
+}
+
+func TestValidationConfig_ActiveContentPoliciesMerge(t *testing.T) {
+	raw := `
+activeContent:
+  policies:
+    macro: reject
+    dde: reject
+profiles:
+  lenient:
+    activeContent:
+      policies:
+        macro: warn
+`
+	var cfg ValidationConfig
+	require.NoError(t, yaml.Unmarshal([]byte(raw), &cfg))
+
+	lenient, ok := cfg.Profile("lenient")
+	require.True(t, ok)
+	require.Equal(t, map[string]string{"macro": PolicyWarn, "dde": PolicyReject}, lenient.ActiveContent.Policies)
+	require.Equal(t, PolicyReject, cfg.ActiveContent.Policies["macro"])
+}
@@ -0,0 +1,132 @@
+package filevalidatorapi
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/qnhqn1/file-validator/internal/domain"
+	"github.com/qnhqn1/file-validator/internal/services/validator"
+)
+
+const (
+	uploadFormField   = "file"
+	documentIDField   = "document_id"
+	storeQueryParam   = "store"
+	profileQueryParam = "profile"
+	tenantQueryParam  = "tenant_id"
+	dateQueryParam    = "document_date"
+	multipartMemLimit = 8 << 20
+	// multipartOverhead — запас на заголовки и поля multipart-формы сверх самого документа.
+	multipartOverhead = 64 << 10
+)
+
+type validateResponse struct {
+	DocumentID string `json:"document_id,omitempty"`
+	Status     string `json:"status"`
+	Stored     bool   `json:"stored"`
+	Error      string `json:"error,omitempty"`
+
+	Report *validator.ValidationReport `json:"report,omitempty"`
+}
+
+func (a *API) validate(w http.ResponseWriter, r *http.Request) {
+	store := true
+	if raw := strings.TrimSpace(r.URL.Query().Get(storeQueryParam)); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, validateResponse{Status: "error", Error: "invalid_store_param"})
+			return
+		}
+		store = parsed
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, a.maxUploadBytes+multipartOverhead)
+	payload, docID, err := readUpload(r)
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) || int64(len(payload)) > a.maxUploadBytes {
+		writeJSON(w, http.StatusRequestEntityTooLarge, validateResponse{Status: "error", Error: "payload_too_large"})
+		return
+	}
+	if err != nil {
+		log.Printf("file-validator: ошибка чтения загрузки: %v", err)
+		writeJSON(w, http.StatusBadRequest, validateResponse{Status: "error", Error: "invalid_upload"})
+		return
+	}
+	if len(payload) == 0 {
+		writeJSON(w, http.StatusBadRequest, validateResponse{Status: "error", Error: "empty_payload"})
+		return
+	}
+	if store && docID == "" {
+		writeJSON(w, http.StatusBadRequest, validateResponse{Status: "error", Error: "missing_document_id"})
+		return
+	}
+
+	req := validator.Request{
+		Key:      docID,
+		Payload:  payload,
+		Profile:  strings.TrimSpace(r.URL.Query().Get(profileQueryParam)),
+		TenantID: strings.TrimSpace(r.URL.Query().Get(tenantQueryParam)),
+	}
+	if raw := strings.TrimSpace(r.URL.Query().Get(dateQueryParam)); raw != "" {
+		if req.DocumentDate, err = validator.ParseDocumentDate(raw); err != nil {
+			writeJSON(w, http.StatusBadRequest, validateResponse{Status: "error", Error: "invalid_document_date"})
+			return
+		}
+	}
+	var report *validator.ValidationReport
+	if store {
+		report, err = a.service.ValidateAndStore(r.Context(), req)
+	} else {
+		report, err = a.service.Validate(r.Context(), req)
+	}
+
+	resp := validateResponse{DocumentID: docID, Status: "valid", Stored: store && err == nil, Report: report}
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, resp)
+	case errors.Is(err, domain.ErrValidationFailed) && report != nil && report.Verdict == validator.VerdictResourceLimitExceeded:
+		resp.Status = report.Verdict
+		resp.Error = err.Error()
+		writeJSON(w, http.StatusRequestEntityTooLarge, resp)
+	case errors.Is(err, domain.ErrValidationFailed):
+		resp.Status = "invalid"
+		resp.Error = err.Error()
+		writeJSON(w, http.StatusUnprocessableEntity, resp)
+	default:
+		log.Printf("file-validator: ошибка синхронной валидации id=%s: %v", docID, err)
+		resp.Status = "error"
+		resp.Error = "store_failed"
+		writeJSON(w, http.StatusInternalServerError, resp)
+	}
+}
+
+// readUpload принимает как multipart-форму с полем file, так и сырое тело запроса.
+func readUpload(r *http.Request) ([]byte, string, error) {
+	docID := strings.TrimSpace(r.URL.Query().Get(documentIDField))
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		payload, err := io.ReadAll(r.Body)
+		return payload, docID, err
+	}
+
+	if err := r.ParseMultipartForm(multipartMemLimit); err != nil {
+		return nil, docID, err
+	}
+	if docID == "" {
+		docID = strings.TrimSpace(r.FormValue(documentIDField))
+	}
+	file, _, err := r.FormFile(uploadFormField)
+	if err != nil {
+		return nil, docID, err
+	}
+	defer file.Close()
+
+	payload, err := io.ReadAll(file)
+	return payload, docID, err
+}
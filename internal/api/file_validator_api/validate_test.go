@@ -0,0 +1,202 @@
+package filevalidatorapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qnhqn1/file-validator/internal/domain"
+	"github.com/qnhqn1/file-validator/internal/metrics"
+	"github.com/qnhqn1/file-validator/internal/services/validator"
+)
+
+type stubService struct {
+	validateErr error
+	verdict     string
+	storeErr    error
+	storedKey   string
+	profile     string
+	date        time.Time
+	validated   int
+}
+
+func (s *stubService) report() *validator.ValidationReport {
+	if s.verdict != "" {
+		return &validator.ValidationReport{Verdict: s.verdict, Format: "docx"}
+	}
+	if s.validateErr != nil {
+		return &validator.ValidationReport{Verdict: validator.VerdictInvalid, Format: "docx"}
+	}
+	return &validator.ValidationReport{Verdict: validator.VerdictValid, Format: "docx"}
+}
+
+func (s *stubService) Validate(_ context.Context, req validator.Request) (*validator.ValidationReport, error) {
+	s.validated++
+	s.profile = req.Profile
+	s.date = req.DocumentDate
+	return s.report(), s.validateErr
+}
+
+func (s *stubService) ValidateAndStore(_ context.Context, req validator.Request) (*validator.ValidationReport, error) {
+	if s.validateErr != nil {
+		return s.report(), s.validateErr
+	}
+	s.storedKey = req.Key
+	return s.report(), s.storeErr
+}
+
+const testMaxUploadBytes = 1 << 10
+
+func newTestAPI(t *testing.T, svc *stubService) http.Handler {
+	collector, err := metrics.New()
+	require.NoError(t, err)
+	return New(svc, "file-validator", testMaxUploadBytes, collector).Router()
+}
+
+func decodeResponse(t *testing.T, rec *httptest.ResponseRecorder) validateResponse {
+	var resp validateResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	return resp
+}
+
+func TestValidate_DryRunRawBody(t *testing.T) {
+	svc := &stubService{}
+	router := newTestAPI(t, svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/validate?store=false&profile=contracts", bytes.NewReader([]byte("payload")))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "contracts", svc.profile)
+	resp := decodeResponse(t, rec)
+	require.Equal(t, "valid", resp.Status)
+	require.False(t, resp.Stored)
+	require.Equal(t, 1, svc.validated)
+	require.Empty(t, svc.storedKey)
+}
+
+func TestValidate_MultipartStores(t *testing.T) {
+	svc := &stubService{}
+	router := newTestAPI(t, svc)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	require.NoError(t, mw.WriteField("document_id", "doc-1"))
+	fw, err := mw.CreateFormFile("file", "doc.docx")
+	require.NoError(t, err)
+	_, _ = fw.Write([]byte("payload"))
+	require.NoError(t, mw.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/validate", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	resp := decodeResponse(t, rec)
+	require.True(t, resp.Stored)
+	require.Equal(t, "doc-1", svc.storedKey)
+}
+
+func TestValidate_PayloadTooLarge(t *testing.T) {
+	svc := &stubService{}
+	router := newTestAPI(t, svc)
+
+	// Лимит берётся из конфигурации: документ на байт больше не принимается ни
+	// сырым телом, ни в multipart-форме, где на заголовки формы есть запас.
+	req := httptest.NewRequest(http.MethodPost, "/v1/validate?store=false", bytes.NewReader(make([]byte, testMaxUploadBytes+1)))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	require.Equal(t, "payload_too_large", decodeResponse(t, rec).Error)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", "doc.docx")
+	require.NoError(t, err)
+	_, _ = fw.Write(make([]byte, testMaxUploadBytes+1))
+	require.NoError(t, mw.Close())
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/validate?store=false", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	require.Zero(t, svc.validated)
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/validate?store=false", bytes.NewReader(make([]byte, testMaxUploadBytes)))
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestValidate_InvalidDocument(t *testing.T) {
+	svc := &stubService{validateErr: fmt.Errorf("%w: плохой документ", domain.ErrValidationFailed)}
+	router := newTestAPI(t, svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/validate?store=false", bytes.NewReader([]byte("payload")))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	resp := decodeResponse(t, rec)
+	require.Equal(t, "invalid", resp.Status)
+	require.Contains(t, resp.Error, "плохой документ")
+	require.NotNil(t, resp.Report)
+	require.Equal(t, validator.VerdictInvalid, resp.Report.Verdict)
+}
+
+func TestValidate_ResourceLimitExceeded(t *testing.T) {
+	svc := &stubService{
+		validateErr: fmt.Errorf("%w: архив распаковывается слишком сильно", domain.ErrValidationFailed),
+		verdict:     validator.VerdictResourceLimitExceeded,
+	}
+	router := newTestAPI(t, svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/validate?store=false", bytes.NewReader([]byte("payload")))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	resp := decodeResponse(t, rec)
+	require.Equal(t, validator.VerdictResourceLimitExceeded, resp.Status)
+	require.Equal(t, validator.VerdictResourceLimitExceeded, resp.Report.Verdict)
+}
+
+func TestValidate_StoreRequiresDocumentID(t *testing.T) {
+	router := newTestAPI(t, &stubService{})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/validate", bytes.NewReader([]byte("payload")))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Equal(t, "missing_document_id", decodeResponse(t, rec).Error)
+}
+
+func TestValidate_DocumentDate(t *testing.T) {
+	svc := &stubService{}
+	router := newTestAPI(t, svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/validate?store=false&document_date=2024-03-01", bytes.NewReader([]byte("payload")))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), svc.date)
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/validate?store=false&document_date=01.03.2024", bytes.NewReader([]byte("payload")))
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Equal(t, "invalid_document_date", decodeResponse(t, rec).Error)
+	require.Equal(t, 1, svc.validated)
+}
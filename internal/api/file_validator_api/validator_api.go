@@ -1,6 +1,7 @@
 package filevalidatorapi
 
 import (
+	"encoding/json"
 	"net/http"
 	"sync"
 
@@ -13,21 +14,24 @@ import (
 
 
 type API struct {
-	service       validator.Service
-	serviceName   string
-	enableSwagger bool
-	collector     *metrics.Collector
-	once          sync.Once
-	swaggerSpec   []byte
+	service     validator.Service
+	serviceName string
+	// maxUploadBytes — наибольший размер документа, тот же, что у потребителя Kafka.
+	maxUploadBytes int64
+	enableSwagger  bool
+	collector      *metrics.Collector
+	once           sync.Once
+	swaggerSpec    []byte
 }
 
 
-func New(service validator.Service, serviceName string, collector *metrics.Collector) *API {
+func New(service validator.Service, serviceName string, maxUploadBytes int64, collector *metrics.Collector) *API {
 	return &API{
-		service:       service,
-		serviceName:   serviceName,
-		enableSwagger: true, // assuming enableSwagger is true
-		collector:     collector,
+		service:        service,
+		serviceName:    serviceName,
+		maxUploadBytes: maxUploadBytes,
+		enableSwagger:  true, // assuming enableSwagger is true
+		collector:      collector,
 	}
 }
 
@@ -36,6 +40,7 @@ func (a *API) Router() http.Handler {
 	router := chi.NewRouter()
 	router.Get("/health", a.health)
 	router.Get("/metrics", a.collector.Handler())
+	router.Post("/v1/validate", a.validate)
 	if a.enableSwagger {
 		router.Get("/swagger", a.swaggerUI)
 		router.Get("/swagger/validator.swagger.json", a.swaggerSpecHandler)
@@ -85,7 +90,7 @@ func (a *API) swaggerSpecHandler(w http.ResponseWriter, r *http.Request) {
 func writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-
+	_ = json.NewEncoder(w).Encode(data)
 }
 
 
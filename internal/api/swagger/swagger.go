@@ -2,7 +2,7 @@ package swagger
 
 import _ "embed" // required to enable //go:embed for embedding the swagger JSON
 
-
+//go:embed validator.swagger.json
 var validatorSpec []byte
 
 
@@ -28,8 +28,11 @@ func Run(ctx context.Context, cfg *config.Config) error {
 		return fmt.Errorf("инициализация метрик: %w", err)
 	}
 
-	service := bootstrap.InitValidatorService(storage, cache)
-	api := bootstrap.InitValidatorAPI(service, cfg.ServiceName, collector)
+	service, err := bootstrap.InitValidatorService(cfg, storage, cache)
+	if err != nil {
+		return fmt.Errorf("инициализация правил валидации: %w", err)
+	}
+	api := bootstrap.InitValidatorAPI(service, cfg.ServiceName, cfg.Validation.Limits.WithDefaults().MaxObjectBytes, collector)
 	producers := bootstrap.InitProducers(cfg)
 	consumers := bootstrap.InitConsumers(cfg, service, collector, producers)
 
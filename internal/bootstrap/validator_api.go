@@ -7,8 +7,8 @@ import (
 )
 
 
-func InitValidatorAPI(service validator.Service, serviceName string, collector *metrics.Collector) *filevalidatorapi.API {
-	return filevalidatorapi.New(service, serviceName, collector)
+func InitValidatorAPI(service validator.Service, serviceName string, maxUploadBytes int64, collector *metrics.Collector) *filevalidatorapi.API {
+	return filevalidatorapi.New(service, serviceName, maxUploadBytes, collector)
 }
 
 
@@ -1,14 +1,17 @@
 package bootstrap
 
 import (
+	"github.com/qnhqn1/file-validator/config"
 	"github.com/qnhqn1/file-validator/internal/cache"
 	"github.com/qnhqn1/file-validator/internal/services/validator"
 	"github.com/qnhqn1/file-validator/internal/storage/pgstorage"
 )
 
 
-func InitValidatorService(storage *pgstorage.Storage, cache cache.Cache) validator.Service {
-	return validator.New(storage, cache)
+func InitValidatorService(cfg *config.Config, storage *pgstorage.Storage, cache cache.Cache) (validator.Service, error) {
+	engine, err := validator.NewEngine(cfg.Validation)
+	if err != nil {
+		return nil, err
+	}
+	return validator.New(storage, cache, engine), nil
 }
-
-
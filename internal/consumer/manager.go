@@ -3,15 +3,18 @@ package consumer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
 	"path"
+	"time"
 
 	"github.com/segmentio/kafka-go"
 	"github.com/qnhqn1/file-validator/config"
+	"github.com/qnhqn1/file-validator/internal/domain"
 	"github.com/qnhqn1/file-validator/internal/metrics"
 	"github.com/qnhqn1/file-validator/internal/producer"
 	"github.com/qnhqn1/file-validator/internal/services/validator"
@@ -71,6 +74,9 @@ func (m *Manager) Run(ctx context.Context) error {
 		reqID, _ := ev["request_id"].(string)
 		objName, _ := ev["object_name"].(string)
 		docID, _ := ev["document_id"].(string)
+		profile, _ := ev["profile"].(string)
+		tenantID, _ := ev["tenant_id"].(string)
+		rawDate, _ := ev["document_date"].(string)
 		if objName == "" {
 			log.Printf("file-validator: отсутствует object_name в payload: %v", ev)
 			m.collector.RecordError(ctx, metrics.CategoryInvalidFile)
@@ -84,6 +90,20 @@ func (m *Manager) Run(ctx context.Context) error {
 			_ = m.reader.CommitMessages(ctx, msg)
 			continue
 		}
+		var documentDate time.Time
+		if rawDate != "" {
+			if documentDate, err = validator.ParseDocumentDate(rawDate); err != nil {
+				log.Printf("file-validator: %v", err)
+				m.collector.RecordError(ctx, metrics.CategoryInvalidFile)
+				if reqID != "" {
+					resp := map[string]interface{}{"request_id": reqID, "status": "invalid", "error": "invalid_document_date"}
+					b, _ := json.Marshal(resp)
+					_ = m.producers.SendValidated(ctx, msg.Key, b)
+				}
+				_ = m.reader.CommitMessages(ctx, msg)
+				continue
+			}
+		}
 
 
 		u, err := url.Parse(m.cfg.Minio.Endpoint)
@@ -126,8 +146,25 @@ func (m *Manager) Run(ctx context.Context) error {
 			_ = m.reader.CommitMessages(ctx, msg)
 			continue
 		}
-		data, err := io.ReadAll(respHTTP.Body)
+		// Объект читается не больше лимита: размер из заголовка проверяется сразу,
+		// но сервер может его не прислать, поэтому чтение тоже ограничено.
+		maxObjectBytes := m.cfg.Validation.Limits.WithDefaults().MaxObjectBytes
+		var data []byte
+		if respHTTP.ContentLength <= maxObjectBytes {
+			data, err = io.ReadAll(io.LimitReader(respHTTP.Body, maxObjectBytes+1))
+		}
 		_ = respHTTP.Body.Close()
+		if err == nil && (respHTTP.ContentLength > maxObjectBytes || int64(len(data)) > maxObjectBytes) {
+			log.Printf("file-validator: объект %s превышает лимит %d байт", objName, maxObjectBytes)
+			m.collector.RecordError(ctx, metrics.CategoryResourceLimit)
+			if reqID != "" {
+				resp := map[string]interface{}{"request_id": reqID, "status": validator.VerdictResourceLimitExceeded, "error": "object_too_large"}
+				b, _ := json.Marshal(resp)
+				_ = m.producers.SendValidated(ctx, msg.Key, b)
+			}
+			_ = m.reader.CommitMessages(ctx, msg)
+			continue
+		}
 		if err != nil {
 			log.Printf("file-validator: ошибка чтения объекта: %v", err)
 			m.collector.RecordError(ctx, metrics.CategoryCorruptFile)
@@ -141,12 +178,25 @@ func (m *Manager) Run(ctx context.Context) error {
 		}
 
 
-		if err := m.svc.ValidateAndStore(ctx, docID, data); err != nil {
+		report, err := m.svc.ValidateAndStore(ctx, validator.Request{
+			Key:          docID,
+			Payload:      data,
+			Profile:      profile,
+			TenantID:     tenantID,
+			DocumentDate: documentDate,
+		})
+		if err != nil {
 			log.Printf("file-validator: валидация/сохранение не удались для id=%s: %v", docID, err)
-			m.collector.RecordError(ctx, metrics.CategoryInvalidFile)
+			m.collector.RecordError(ctx, errorCategory(report, err))
 
 			if reqID != "" {
 				resp := map[string]interface{}{"request_id": reqID, "status": "invalid", "error": err.Error()}
+				if report != nil {
+					resp["report"] = report
+					if report.Verdict == validator.VerdictResourceLimitExceeded {
+						resp["status"] = report.Verdict
+					}
+				}
 				b, _ := json.Marshal(resp)
 				_ = m.producers.SendValidated(ctx, msg.Key, b)
 			}
@@ -158,7 +208,7 @@ func (m *Manager) Run(ctx context.Context) error {
 
 
 		if reqID != "" {
-			resp := map[string]interface{}{"request_id": reqID, "status": "valid"}
+			resp := map[string]interface{}{"request_id": reqID, "status": "valid", "report": report}
 			b, _ := json.Marshal(resp)
 			if err := m.producers.SendValidated(ctx, msg.Key, b); err != nil {
 				log.Printf("file-validator: ошибка отправки ответа: %v", err)
@@ -173,6 +223,24 @@ func (m *Manager) Run(ctx context.Context) error {
 }
 
 
+// errorCategory сопоставляет первый проваленный код отчёта с категорией метрик.
+func errorCategory(report *validator.ValidationReport, err error) string {
+	if !errors.Is(err, domain.ErrValidationFailed) || report == nil {
+		return metrics.CategoryUnknown
+	}
+	switch report.FirstFailureCode() {
+	case validator.CodeResourceLimitExceeded:
+		return metrics.CategoryResourceLimit
+	case validator.CodeMissingPart:
+		return metrics.CategoryMissingParts
+	case validator.CodeInvalidArchive, validator.CodeInvalidPackage, validator.CodeInconsistentArchive, validator.CodeInvalidXML, validator.CodeInvalidPDF:
+		return metrics.CategoryCorruptFile
+	default:
+		return metrics.CategoryInvalidFile
+	}
+}
+
+
 func (m *Manager) Close() {
 	if m.reader != nil {
 		_ = m.reader.Close()
@@ -14,10 +14,11 @@ import (
 
 
 const (
-	CategoryInvalidFile  = "invalid_file"
-	CategoryMissingParts = "missing_parts"
-	CategoryCorruptFile  = "corrupt_file"
-	CategoryUnknown      = "unknown"
+	CategoryInvalidFile   = "invalid_file"
+	CategoryMissingParts  = "missing_parts"
+	CategoryCorruptFile   = "corrupt_file"
+	CategoryResourceLimit = "resource_limit"
+	CategoryUnknown       = "unknown"
 )
 
 
@@ -67,10 +68,11 @@ func New() (*Collector, error) {
 	}
 
 	categories := map[string]int64{
-		CategoryInvalidFile:  0,
-		CategoryMissingParts: 0,
-		CategoryCorruptFile:  0,
-		CategoryUnknown:      0,
+		CategoryInvalidFile:   0,
+		CategoryMissingParts:  0,
+		CategoryCorruptFile:   0,
+		CategoryResourceLimit: 0,
+		CategoryUnknown:       0,
 	}
 
 	return &Collector{
@@ -0,0 +1,143 @@
+package validator
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Контрольные суммы российских идентификаторов. Аргумент — строка из одних
+// ASCII-цифр; длина проверяется здесь же.
+
+func digitsOf(s string) []int {
+	d := make([]int, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return nil
+		}
+		d[i] = int(s[i] - '0')
+	}
+	return d
+}
+
+func weightedControl(d []int, weights []int, mod int) int {
+	sum := 0
+	for i, w := range weights {
+		sum += d[i] * w
+	}
+	return sum % mod % 10
+}
+
+// validINN проверяет ИНН организации (10 цифр) или физического лица (12 цифр).
+func validINN(s string) bool {
+	d := digitsOf(s)
+	switch len(d) {
+	case 10:
+		return weightedControl(d, []int{2, 4, 10, 3, 5, 9, 4, 6, 8}, 11) == d[9]
+	case 12:
+		return weightedControl(d, []int{7, 2, 4, 10, 3, 5, 9, 4, 6, 8}, 11) == d[10] &&
+			weightedControl(d, []int{3, 7, 2, 4, 10, 3, 5, 9, 4, 6, 8}, 11) == d[11]
+	}
+	return false
+}
+
+// validSNILS проверяет СНИЛС без разделителей. Контрольное число определено
+// только для номеров больше 001-001-998.
+func validSNILS(s string) bool {
+	d := digitsOf(s)
+	if len(d) != 11 || s[:9] <= "001001998" {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += d[i] * (9 - i)
+	}
+	control := sum % 101
+	if control == 100 {
+		control = 0
+	}
+	return control == d[9]*10+d[10]
+}
+
+// validOGRN проверяет ОГРН (13 цифр) или ОГРНИП (15 цифр): последняя цифра —
+// остаток от деления остальной части на 11 или 13, взятый по модулю 10.
+func validOGRN(s string) bool {
+	d := digitsOf(s)
+	var mod int
+	switch len(d) {
+	case 13:
+		mod = 11
+	case 15:
+		mod = 13
+	default:
+		return false
+	}
+	rem := 0
+	for _, digit := range d[:len(d)-1] {
+		rem = (rem*10 + digit) % mod
+	}
+	return rem%10 == d[len(d)-1]
+}
+
+// validKPP проверяет формат КПП: код налогового органа, причина постановки
+// на учёт (цифры или заглавные латинские буквы) и порядковый номер.
+func validKPP(s string) bool {
+	return kppFormat.MatchString(s)
+}
+
+var kppFormat = regexp.MustCompile(`^\d{4}[0-9A-Z]{2}\d{3}$`)
+
+// validBIK проверяет БИК: девять цифр, код страны 04.
+func validBIK(s string) bool {
+	return len(digitsOf(s)) == 9 && strings.HasPrefix(s, "04")
+}
+
+// validAccount проверяет контрольный ключ расчётного счёта по БИК банка:
+// к счёту приписываются три последние цифры БИК.
+func validAccount(account, bik string) bool {
+	return len(digitsOf(account)) == 20 && validBIK(bik) && accountKey(bik[6:]+account)
+}
+
+// validCorrAccount проверяет корреспондентский счёт: к нему приписываются
+// «0» и пятая-шестая цифры БИК (код подразделения Банка России).
+func validCorrAccount(account, bik string) bool {
+	return len(digitsOf(account)) == 20 && validBIK(bik) && accountKey("0"+bik[4:6]+account)
+}
+
+func accountKey(s string) bool {
+	weights := [3]int{7, 1, 3}
+	sum := 0
+	for i, digit := range digitsOf(s) {
+		sum += digit * weights[i%3]
+	}
+	return sum%10 == 0
+}
+
+// validLuhn проверяет номер банковской карты по алгоритму Луна.
+func validLuhn(s string) bool {
+	d := digitsOf(s)
+	if len(d) < 12 {
+		return false
+	}
+	sum := 0
+	for i := len(d) - 1; i >= 0; i-- {
+		v := d[i]
+		if (len(d)-1-i)%2 == 1 {
+			if v *= 2; v > 9 {
+				v -= 9
+			}
+		}
+		sum += v
+	}
+	return sum%10 == 0
+}
+
+// onlyDigits убирает из строки всё, кроме цифр.
+func onlyDigits(s string) string {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			b = append(b, s[i])
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,37 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksums(t *testing.T) {
+	require.True(t, validINN("7707083893"))
+	require.True(t, validINN("500100732259"))
+	require.False(t, validINN("7707083894"))
+	require.False(t, validINN("500100732250"))
+	require.False(t, validINN("77070838"))
+
+	require.True(t, validSNILS("11223344595"))
+	require.False(t, validSNILS("11223344596"))
+	require.False(t, validSNILS("00100199800"))
+
+	require.True(t, validOGRN("1027700132195"))
+	require.True(t, validOGRN("304500116000157"))
+	require.False(t, validOGRN("1027700132196"))
+
+	require.True(t, validLuhn("4111111111111111"))
+	require.False(t, validLuhn("4111111111111112"))
+	require.False(t, validLuhn("41111a1111111111"))
+}
+
+func TestChecksums_Accounts(t *testing.T) {
+	require.True(t, validAccount("40702810200000000001", "044525225"))
+	require.False(t, validAccount("40702810300000000001", "044525225"))
+	require.True(t, validCorrAccount("30101810400000000225", "044525225"))
+	require.False(t, validCorrAccount("30101810400000000225", "044030653"))
+	require.True(t, validKPP("7736AB001"))
+	require.False(t, validKPP("77360100"))
+	require.False(t, validBIK("144525225"))
+}
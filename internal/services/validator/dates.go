@@ -0,0 +1,321 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+const defaultTwoDigitYearPivot = 50
+
+// clockSkew — допуск для дат из будущего: часовой пояс в документе часто
+// не указан, а часы на машине автора могут спешить.
+const clockSkew = 24 * time.Hour
+
+// clock подменяется в тестах.
+var clock = time.Now
+
+// maxReportedDates ограничивает число дат в details: в длинном документе их сотни.
+const maxReportedDates = 20
+
+// dateMatch — дата, найденная в тексте. Offset — позиция начала совпадения в
+// символах текста. У неоднозначной даты Date — прочтение по локали, Alternative — другое.
+type dateMatch struct {
+	Date        time.Time
+	Text        string
+	Offset      int
+	Ambiguous   bool
+	Alternative time.Time
+	// start и end — границы совпадения в байтах, для разбора соседнего текста.
+	start, end int
+}
+
+func dateDetails(matches []dateMatch) []map[string]interface{} {
+	if len(matches) > maxReportedDates {
+		matches = matches[:maxReportedDates]
+	}
+	out := make([]map[string]interface{}, len(matches))
+	for i, m := range matches {
+		out[i] = map[string]interface{}{
+			"text":   m.Text,
+			"date":   m.Date.Format("2006-01-02"),
+			"offset": m.Offset,
+		}
+		if m.Ambiguous {
+			out[i]["alternative"] = m.Alternative.Format("2006-01-02")
+		}
+	}
+	return out
+}
+
+// Формы названий месяцев во всех падежах и сокращения. Основы на мягкий знак
+// склоняются как «январь», на согласный — как «март».
+var (
+	ruMonthForms = monthForms(
+		[]string{"январ", "феврал", "март", "апрел", "ма", "июн", "июл", "август", "сентябр", "октябр", "ноябр", "декабр"},
+		func(stem string) []string {
+			switch stem {
+			case "март", "август":
+				return []string{"", "а", "у", "е", "ом"}
+			case "ма":
+				return []string{"й", "я", "ю", "е", "ем", "ём"}
+			}
+			return []string{"ь", "я", "ю", "е", "ем", "ём"}
+		},
+		map[string]time.Month{
+			"янв": time.January, "фев": time.February, "февр": time.February, "мар": time.March,
+			"апр": time.April, "июн": time.June, "июл": time.July, "авг": time.August,
+			"сен": time.September, "сент": time.September, "окт": time.October,
+			"ноя": time.November, "нояб": time.November, "дек": time.December,
+		})
+	enMonthForms = monthForms(
+		[]string{"january", "february", "march", "april", "may", "june", "july", "august", "september", "october", "november", "december"},
+		func(string) []string { return []string{""} },
+		map[string]time.Month{
+			"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+			"jun": time.June, "jul": time.July, "aug": time.August, "sep": time.September,
+			"sept": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+		})
+)
+
+func monthForms(stems []string, endings func(string) []string, abbreviations map[string]time.Month) map[string]time.Month {
+	forms := make(map[string]time.Month, len(stems)*6+len(abbreviations))
+	for i, stem := range stems {
+		for _, ending := range endings(stem) {
+			forms[stem+ending] = time.Month(i + 1)
+		}
+	}
+	for abbr, month := range abbreviations {
+		forms[abbr] = month
+	}
+	return forms
+}
+
+// monthAlternation собирает группу для регулярного выражения; длинные формы
+// идут первыми, чтобы «марта» не разбиралось как сокращение «мар».
+func monthAlternation(forms map[string]time.Month) string {
+	names := make([]string, 0, len(forms))
+	for name := range forms {
+		names = append(names, regexp.QuoteMeta(name))
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) > len(names[j])
+		}
+		return names[i] < names[j]
+	})
+	return "(" + strings.Join(names, "|") + ")"
+}
+
+// datePattern разбирает подгруппы совпадения в дату; Text и Offset заполняет find.
+// standalone требует, чтобы совпадение не было частью более длинной цепочки
+// чисел через точку, как номер версии 2.10.12.03.
+type datePattern struct {
+	re         *regexp.Regexp
+	parse      func(e dateExtractor, groups []string) (dateMatch, bool)
+	standalone bool
+}
+
+// exact — разбор без вариантов прочтения.
+func exact(t time.Time, ok bool) (dateMatch, bool) {
+	return dateMatch{Date: t}, ok
+}
+
+var datePatterns = []datePattern{
+	{ // ДД.ММ.ГГГГ, в том числе 1.2.2024
+		re: regexp.MustCompile(`\b(\d{1,2})\.(\d{1,2})\.(\d{4})\b`),
+		parse: func(_ dateExtractor, g []string) (dateMatch, bool) {
+			return exact(makeDate(g[3], g[2], g[1]))
+		},
+	},
+	{ // ГГГГ-ММ-ДД
+		re: regexp.MustCompile(`\b(\d{4})-(\d{1,2})-(\d{1,2})\b`),
+		parse: func(_ dateExtractor, g []string) (dateMatch, bool) {
+			return exact(makeDate(g[1], g[2], g[3]))
+		},
+	},
+	{ // ДД/ММ/ГГГГ или ММ/ДД/ГГГГ — по локали
+		re: regexp.MustCompile(`\b(\d{1,2})/(\d{1,2})/(\d{4})\b`),
+		parse: func(e dateExtractor, g []string) (dateMatch, bool) {
+			return e.slashDate(g[1], g[2], g[3])
+		},
+	},
+	{ // ДД.ММ.ГГ — только с двузначными днём и месяцем, иначе совпадают номера версий
+		re: regexp.MustCompile(`\b(\d{2})\.(\d{2})\.(\d{2})\b`),
+		parse: func(e dateExtractor, g []string) (dateMatch, bool) {
+			if !plausibleDayMonth(g[1], g[2]) {
+				return dateMatch{}, false
+			}
+			return exact(makeDate(e.year(g[3]), g[2], g[1]))
+		},
+		standalone: true,
+	},
+	{ // 15 марта 2024 г., «01» января 2023 года, 1 янв. 24 г.
+		re: regexp.MustCompile(`(?i)[«"“„]?\b(\d{1,2})[»"”“]?\s+` + monthAlternation(ruMonthForms) + `\.?\s+(\d{4}|\d{2})\b(\s*г(?:ода|оду|\.)?)?`),
+		parse: func(e dateExtractor, g []string) (dateMatch, bool) {
+			// Двузначный год после названия месяца — только с «г.»: «15 марта 24 сотрудника» не дата.
+			if len(g[3]) == 2 && g[4] == "" {
+				return dateMatch{}, false
+			}
+			return exact(makeDate(e.year(g[3]), strconv.Itoa(int(ruMonthForms[strings.ToLower(g[2])])), g[1]))
+		},
+	},
+	{ // 15 March 2024, 1st Jan 2024
+		re: regexp.MustCompile(`(?i)\b(\d{1,2})(?:st|nd|rd|th)?\s+` + monthAlternation(enMonthForms) + `\.?,?\s+(\d{4})\b`),
+		parse: func(e dateExtractor, g []string) (dateMatch, bool) {
+			return exact(makeDate(e.year(g[3]), strconv.Itoa(int(enMonthForms[strings.ToLower(g[2])])), g[1]))
+		},
+	},
+	{ // March 15, 2024
+		re: regexp.MustCompile(`(?i)\b` + monthAlternation(enMonthForms) + `\.?\s+(\d{1,2})(?:st|nd|rd|th)?,?\s+(\d{4})\b`),
+		parse: func(e dateExtractor, g []string) (dateMatch, bool) {
+			return exact(makeDate(e.year(g[3]), strconv.Itoa(int(enMonthForms[strings.ToLower(g[1])])), g[2]))
+		},
+	},
+}
+
+// dateExtractor находит даты в тексте по всем шаблонам.
+type dateExtractor struct {
+	pivot      int
+	monthFirst bool
+}
+
+func newDateExtractor(cfg config.DateRuleConfig) (dateExtractor, error) {
+	pivot := defaultTwoDigitYearPivot
+	if cfg.TwoDigitYearPivot != nil {
+		pivot = *cfg.TwoDigitYearPivot
+	}
+	if pivot < 0 || pivot > 100 {
+		return dateExtractor{}, fmt.Errorf("twoDigitYearPivot должен быть в диапазоне [0, 100], получено %d", pivot)
+	}
+	e := dateExtractor{pivot: pivot}
+	switch cfg.Locale {
+	case "", "ru", "en-GB":
+	case "en-US":
+		e.monthFirst = true
+	default:
+		return dateExtractor{}, fmt.Errorf("неизвестная локаль дат %q, допустимы ru, en-GB, en-US", cfg.Locale)
+	}
+	return e, nil
+}
+
+// slashDate читает дату через косую черту в порядке локали. Если в этом порядке
+// даты не существует, а в обратном существует (25/12 в en-US), берётся обратный;
+// если существуют обе и они различаются, дата неоднозначна.
+func (e dateExtractor) slashDate(first, second, year string) (dateMatch, bool) {
+	day, month := first, second
+	if e.monthFirst {
+		day, month = second, first
+	}
+	primary, okPrimary := makeDate(year, month, day)
+	swapped, okSwapped := makeDate(year, day, month)
+	switch {
+	case okPrimary && okSwapped && !primary.Equal(swapped):
+		return dateMatch{Date: primary, Ambiguous: true, Alternative: swapped}, true
+	case okPrimary:
+		return dateMatch{Date: primary}, true
+	case okSwapped:
+		return dateMatch{Date: swapped}, true
+	}
+	return dateMatch{}, false
+}
+
+// year переводит год из текста в полный: двузначный — относительно pivot.
+func (e dateExtractor) year(raw string) string {
+	if len(raw) != 2 {
+		return raw
+	}
+	n, _ := strconv.Atoi(raw)
+	if n < e.pivot {
+		return strconv.Itoa(2000 + n)
+	}
+	return strconv.Itoa(1900 + n)
+}
+
+// find возвращает даты в порядке следования в тексте. Пересекающиеся совпадения
+// разных шаблонов не дублируются: остаётся начавшееся раньше.
+func (e dateExtractor) find(text string) []dateMatch {
+	type span struct {
+		start, end int
+		match      dateMatch
+	}
+	var spans []span
+	for _, pattern := range datePatterns {
+		for _, idx := range pattern.re.FindAllStringSubmatchIndex(text, -1) {
+			groups := make([]string, len(idx)/2)
+			for i := range groups {
+				if idx[2*i] >= 0 {
+					groups[i] = text[idx[2*i]:idx[2*i+1]]
+				}
+			}
+			if pattern.standalone && !standalone(text, idx[0], idx[1]) {
+				continue
+			}
+			if m, ok := pattern.parse(e, groups); ok {
+				spans = append(spans, span{start: idx[0], end: idx[1], match: m})
+			}
+		}
+	}
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	var matches []dateMatch
+	lastEnd, runeOffset, byteOffset := 0, 0, 0
+	for _, s := range spans {
+		if s.start < lastEnd {
+			continue
+		}
+		runeOffset += utf8.RuneCountInString(text[byteOffset:s.start])
+		byteOffset = s.start
+		m := s.match
+		m.Text, m.Offset = text[s.start:s.end], runeOffset
+		m.start, m.end = s.start, s.end
+		matches = append(matches, m)
+		lastEnd = s.end
+	}
+	return matches
+}
+
+// standalone сообщает, что совпадение не продолжает число через точку или
+// дефис и не продолжается им: «v2.10.12.03», «10.12.03.1» — не даты.
+func standalone(text string, start, end int) bool {
+	if start > 0 {
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		if before == '.' || before == '-' || unicode.IsLetter(before) || unicode.IsDigit(before) {
+			return false
+		}
+	}
+	if end+1 < len(text) && (text[end] == '.' || text[end] == '-') && text[end+1] >= '0' && text[end+1] <= '9' {
+		return false
+	}
+	return true
+}
+
+// plausibleDayMonth отсекает двузначные «дни» и «месяцы» вне календаря до
+// разбора года: 00.13.05 не дата ни в каком порядке.
+func plausibleDayMonth(day, month string) bool {
+	d, _ := strconv.Atoi(day)
+	m, _ := strconv.Atoi(month)
+	return d >= 1 && d <= 31 && m >= 1 && m <= 12
+}
+
+// makeDate собирает дату из компонентов и отклоняет несуществующие, например 31.02.
+func makeDate(year, month, day string) (time.Time, bool) {
+	y, errY := strconv.Atoi(year)
+	m, errM := strconv.Atoi(month)
+	d, errD := strconv.Atoi(day)
+	if errY != nil || errM != nil || errD != nil || m < 1 || m > 12 || d < 1 {
+		return time.Time{}, false
+	}
+	t := time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+	if t.Day() != d {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,173 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+func testExtractor(t *testing.T, cfg config.DateRuleConfig) dateExtractor {
+	t.Helper()
+	e, err := newDateExtractor(cfg)
+	require.NoError(t, err)
+	return e
+}
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDateExtractor_MonthNames(t *testing.T) {
+	e := testExtractor(t, config.DateRuleConfig{})
+	cases := map[string]time.Time{
+		"15 марта 2024 г.":           day(2024, time.March, 15),
+		"«01» января 2023 года":      day(2023, time.January, 1),
+		`"5" МАЯ 2022 г.`:            day(2022, time.May, 5),
+		"1 сент. 2021":               day(2021, time.September, 1),
+		"к 3 августу 2020":           day(2020, time.August, 3),
+		"31 декабрём 2019":           day(2019, time.December, 31),
+		"1 янв. 24 г.":               day(2024, time.January, 1),
+		"7 февраля 99 года":          day(1999, time.February, 7),
+		"signed on 15 March 2024":    day(2024, time.March, 15),
+		"dated 1st Jan. 2024":        day(2024, time.January, 1),
+		"effective March 15th, 2024": day(2024, time.March, 15),
+		"до 31.12.24":                day(2024, time.December, 31),
+	}
+	for text, want := range cases {
+		matches := e.find(text)
+		require.Len(t, matches, 1, text)
+		require.Equal(t, want, matches[0].Date, text)
+	}
+}
+
+func TestDateExtractor_Rejects(t *testing.T) {
+	e := testExtractor(t, config.DateRuleConfig{})
+	for _, text := range []string{
+		"30 февраля 2024",
+		"15 марта 24 сотрудника",
+		"версия 1.2.10",
+		"115 марта 2024",
+		"15 мартышек 2024",
+		"March 2024",
+	} {
+		require.Empty(t, e.find(text), text)
+	}
+}
+
+func TestDateExtractor_Positions(t *testing.T) {
+	e := testExtractor(t, config.DateRuleConfig{})
+	matches := e.find("Договор от «01» января 2023 года, действует до 31.12.2025 и 2026-01-15")
+	require.Len(t, matches, 3)
+	require.Equal(t, "«01» января 2023 года", matches[0].Text)
+	require.Equal(t, 11, matches[0].Offset)
+	require.Equal(t, "31.12.2025", matches[1].Text)
+	require.Equal(t, 47, matches[1].Offset)
+	require.Equal(t, "2026-01-15", matches[2].Text)
+	require.Equal(t, 60, matches[2].Offset)
+}
+
+func TestDateExtractor_Pivot(t *testing.T) {
+	pivot := func(n int) config.DateRuleConfig { return config.DateRuleConfig{TwoDigitYearPivot: &n} }
+
+	e := testExtractor(t, pivot(30))
+	require.Equal(t, day(2029, time.January, 1), e.find("01.01.29")[0].Date)
+	require.Equal(t, day(1930, time.January, 1), e.find("01.01.30")[0].Date)
+
+	// 0 задаётся явно и относит все двузначные годы к 1900-м; не задан — 50.
+	require.Equal(t, day(1901, time.January, 1), testExtractor(t, pivot(0)).find("01.01.01")[0].Date)
+	require.Equal(t, day(2049, time.January, 1), testExtractor(t, config.DateRuleConfig{}).find("01.01.49")[0].Date)
+
+	_, err := newDateExtractor(pivot(101))
+	require.Error(t, err)
+	_, err = newDateExtractor(pivot(-1))
+	require.Error(t, err)
+}
+
+func TestDateExtractor_TwoDigitYearNotVersion(t *testing.T) {
+	e := testExtractor(t, config.DateRuleConfig{})
+	for _, text := range []string{"версия 2.10.12.03", "сборка 10.12.03.1", "v10.12.03", "1.2.03", "10.12.03-5", "00.13.05"} {
+		require.Empty(t, e.find(text), text)
+	}
+	matches := e.find("от 10.12.03, (15.01.24)")
+	require.Len(t, matches, 2)
+	require.Equal(t, day(2003, time.December, 10), matches[0].Date)
+	require.Equal(t, day(2024, time.January, 15), matches[1].Date)
+}
+
+func TestDateSpan_MonthNamesCount(t *testing.T) {
+	engine, err := profileEngine(config.ProfileConfig{Rules: []string{"date_span"}})
+	require.NoError(t, err)
+	report := engine.Run(Request{Payload: createDOCXWithText("Договор от «01» января 2023 г. действует до 15 марта 2024 г.")})
+	require.True(t, report.Valid())
+	res, _ := report.Result("date_span")
+	require.Equal(t, 2, res.Details["dates_found"])
+	require.Equal(t, "2023-01-01", res.Details["min_date"])
+}
+
+func TestDateExtractor_NonPaddedComponents(t *testing.T) {
+	e := testExtractor(t, config.DateRuleConfig{})
+	matches := e.find("с 1.2.2024 по 2024-3-5")
+	require.Len(t, matches, 2)
+	require.Equal(t, day(2024, time.February, 1), matches[0].Date)
+	require.Equal(t, day(2024, time.March, 5), matches[1].Date)
+}
+
+func TestDateExtractor_SlashOrderByLocale(t *testing.T) {
+	ru := testExtractor(t, config.DateRuleConfig{})
+	us := testExtractor(t, config.DateRuleConfig{Locale: "en-US"})
+
+	m := ru.find("03/04/2024")[0]
+	require.Equal(t, day(2024, time.April, 3), m.Date)
+	require.True(t, m.Ambiguous)
+	require.Equal(t, day(2024, time.March, 4), m.Alternative)
+
+	m = us.find("03/04/2024")[0]
+	require.Equal(t, day(2024, time.March, 4), m.Date)
+	require.True(t, m.Ambiguous)
+
+	// Единственное допустимое прочтение не считается неоднозначным.
+	m = us.find("25/12/2024")[0]
+	require.Equal(t, day(2024, time.December, 25), m.Date)
+	require.False(t, m.Ambiguous)
+	require.False(t, ru.find("05/05/2024")[0].Ambiguous)
+	require.Empty(t, ru.find("13/13/2024"))
+
+	_, err := newDateExtractor(config.DateRuleConfig{Locale: "de"})
+	require.Error(t, err)
+}
+
+func TestDateSpan_AmbiguousPolicies(t *testing.T) {
+	// По локали 01/02/2020 — 1 февраля, обратное прочтение на месяц раньше.
+	payload := createDOCXWithText("Даты: 01/02/2020 и 15.01.2023")
+	run := func(policy string) RuleResult {
+		engine, err := profileEngine(config.ProfileConfig{
+			Rules: []string{"date_span"},
+			Dates: config.DateRuleConfig{Ambiguous: policy},
+		})
+		require.NoError(t, err)
+		res, _ := engine.Run(Request{Payload: payload}).Result("date_span")
+		return res
+	}
+
+	res := run("")
+	require.Equal(t, StatusPassed, res.Status)
+	require.Equal(t, []map[string]interface{}{{"text": "01/02/2020", "date": "2020-02-01", "offset": 6, "alternative": "2020-01-02"}}, res.Details["ambiguous_dates"])
+
+	res = run(config.AmbiguousDatesBoth)
+	require.Equal(t, CodeDateSpanExceeded, res.Code)
+	require.Equal(t, "2020-01-02", res.Details["min_date"])
+
+	res = run(config.AmbiguousDatesIgnore)
+	require.Equal(t, StatusPassed, res.Status)
+	require.Equal(t, "2023-01-15", res.Details["min_date"])
+
+	res = run(config.AmbiguousDatesReject)
+	require.Equal(t, CodeAmbiguousDate, res.Code)
+	require.Contains(t, res.Message, "01.02.2020 или 02.01.2020")
+
+	_, err := profileEngine(config.ProfileConfig{Dates: config.DateRuleConfig{Ambiguous: "guess"}})
+	require.Error(t, err)
+}
@@ -0,0 +1,264 @@
+package validator
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"time"
+)
+
+const mainPartName = "word/document.xml"
+
+// Источники текста: основное тело и вспомогательные части, на которые оно ссылается.
+const (
+	SourceBody      = "body"
+	SourceHeader    = "header"
+	SourceFooter    = "footer"
+	SourceFootnotes = "footnotes"
+	SourceEndnotes  = "endnotes"
+	SourceComments  = "comments"
+	// SourceNotes — заметки докладчика к слайдам PPTX.
+	SourceNotes = "notes"
+)
+
+// auxiliarySources задаёт порядок вспомогательных частей в тексте документа.
+var auxiliarySources = []string{SourceHeader, SourceFooter, SourceFootnotes, SourceEndnotes, SourceComments}
+
+func isKnownSource(name string) bool {
+	if name == SourceBody || name == SourceNotes {
+		return true
+	}
+	for _, s := range auxiliarySources {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+var errPartMissing = errors.New("часть документа отсутствует")
+
+// Document — разобранная модель, которую получают правила. Разбор не прерывается
+// на структурных дефектах: их оценивают сами правила.
+type Document struct {
+	Format   string
+	Size     int64
+	Archive  *zip.Reader
+	MainPart string
+	// MainPartErr — ошибка чтения основной части, errPartMissing если её нет.
+	MainPartErr error
+	// PartErrors — вспомогательные части, которые не удалось разобрать; их текст не учитывается.
+	PartErrors map[string]error
+	Paragraphs []Paragraph
+	Tables     []Table
+	// Revisions — не принятые правки во всех частях; текст абзацев уже с принятыми правками.
+	Revisions []Revision
+	// Comments — примечания DOCX с отметкой, разрешены ли они.
+	Comments []Comment
+	// Text — абзацы всех частей, разделённые переводом строки.
+	Text     string
+	Metadata Metadata
+	// DocumentDate — дата из запроса на проверку, см. Request.DocumentDate.
+	DocumentDate time.Time
+	// parts — записи пакета, читаемые с учётом лимитов; правилам следует читать через него.
+	parts fs.FS
+	// raw — исходные байты пакета для проверок, которым не хватает archive/zip.
+	raw []byte
+	// entries — имена записей пакета, строятся при первом вызове HasEntry.
+	entries map[string]bool
+}
+
+// HasEntry сообщает, есть ли в пакете запись с таким именем. Правила вызывают
+// его для каждой связи, поэтому имена собираются в множество один раз.
+func (d *Document) HasEntry(name string) bool {
+	if d.Archive == nil {
+		return false
+	}
+	if d.entries == nil {
+		d.entries = make(map[string]bool, len(d.Archive.File))
+		for _, file := range d.Archive.File {
+			d.entries[file.Name] = true
+		}
+	}
+	return d.entries[name]
+}
+
+// TextFrom собирает текст только из перечисленных источников; пустой список — все источники.
+func (d *Document) TextFrom(sources []string) string {
+	if len(sources) == 0 {
+		return d.Text
+	}
+	return joinParagraphs(d.ParagraphsFrom(sources))
+}
+
+// ParagraphsFrom отбирает абзацы перечисленных источников; пустой список — все абзацы.
+func (d *Document) ParagraphsFrom(sources []string) []Paragraph {
+	if len(sources) == 0 {
+		return d.Paragraphs
+	}
+	allowed := make(map[string]bool, len(sources))
+	for _, s := range sources {
+		allowed[s] = true
+	}
+	var selected []Paragraph
+	for _, para := range d.Paragraphs {
+		if allowed[para.Source] {
+			selected = append(selected, para)
+		}
+	}
+	return selected
+}
+
+func parseDocument(data []byte, budget *readBudget) (*Document, error) {
+	reader, err := openPackage(data, budget)
+	if err != nil {
+		return nil, fmt.Errorf("не является допустимым ZIP: %w", err)
+	}
+
+	main := officeDocumentPart(reader)
+	if main == "" {
+		main = mainPartName
+	}
+	doc := &Document{Format: FormatDOCX, Size: int64(len(data)), Archive: reader.Reader, parts: reader, raw: data, MainPart: main}
+	doc.readOOXMLMetadata(reader)
+	body, err := readWordPart(reader, main, SourceBody)
+	if err == nil && !isWordElement(body.Root, "document") {
+		err = fmt.Errorf("%s: неожиданный корневой элемент %s, ожидался w:document", path.Base(main), body.Root.Local)
+	}
+	if err != nil {
+		doc.MainPartErr = err
+		return doc, nil
+	}
+	doc.Paragraphs = body.Paragraphs
+	doc.Tables = body.Tables
+	doc.Revisions = body.Revisions
+
+	doc.readAuxiliaryParts(reader)
+	doc.Text = joinParagraphs(doc.Paragraphs)
+	return doc, nil
+}
+
+// readAuxiliaryParts находит колонтитулы, сноски и примечания по связям основной части.
+func (d *Document) readAuxiliaryParts(reader fs.FS) {
+	rels, err := readRelationships(reader, relsPathFor(d.MainPart))
+	if err != nil {
+		return
+	}
+
+	for _, source := range auxiliarySources {
+		for _, rel := range rels {
+			if rel.External() || rel.Kind() != source {
+				continue
+			}
+			name := relationshipEntry(d.MainPart, rel.Target)
+			body, err := readWordPart(reader, name, source)
+			if err != nil {
+				d.addPartError(name, err)
+				continue
+			}
+			d.appendBody(body)
+		}
+	}
+	for _, rel := range rels {
+		if !rel.External() && rel.Kind() == "commentsExtended" {
+			d.resolveComments(reader, relationshipEntry(d.MainPart, rel.Target))
+		}
+	}
+}
+
+// appendBody добавляет абзацы и таблицы части, сдвигая номера таблиц.
+func (d *Document) appendBody(body *wordBody) {
+	offset := len(d.Tables)
+	for i := range body.Paragraphs {
+		if body.Paragraphs[i].Table != outsideOfTables {
+			body.Paragraphs[i].Table += offset
+		}
+	}
+	d.Paragraphs = append(d.Paragraphs, body.Paragraphs...)
+	d.Tables = append(d.Tables, body.Tables...)
+	d.Revisions = append(d.Revisions, body.Revisions...)
+	d.Comments = append(d.Comments, body.Comments...)
+}
+
+// commentsExtended — w15:commentsEx: отметка «разрешено» для примечаний,
+// связанных по w14:paraId их последнего абзаца; у ответов есть ссылка на родителя.
+type commentsExtended struct {
+	Comments []struct {
+		ParaID       string `xml:"paraId,attr"`
+		ParaIDParent string `xml:"paraIdParent,attr"`
+		Done         string `xml:"done,attr"`
+	} `xml:"commentEx"`
+}
+
+// resolveComments отмечает разрешённые примечания. Ответ считается разрешённым
+// вместе с исходным примечанием. Без commentsExtended.xml все примечания открыты.
+func (d *Document) resolveComments(reader fs.FS, name string) {
+	f, err := reader.Open(name)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	var ext commentsExtended
+	if err := newXMLDecoder(f).Decode(&ext); err != nil {
+		d.addPartError(name, describeXMLError(path.Base(name), err))
+		return
+	}
+	done := make(map[string]bool, len(ext.Comments))
+	parent := make(map[string]string, len(ext.Comments))
+	for _, c := range ext.Comments {
+		done[c.ParaID] = c.Done == "1" || c.Done == "true"
+		parent[c.ParaID] = c.ParaIDParent
+	}
+	for i := range d.Comments {
+		ids := d.Comments[i].paraIDs
+		if len(ids) == 0 {
+			continue
+		}
+		id := ids[len(ids)-1]
+		d.Comments[i].Resolved = done[id] || done[parent[id]]
+	}
+}
+
+func (d *Document) addPartError(name string, err error) {
+	if d.PartErrors == nil {
+		d.PartErrors = make(map[string]error)
+	}
+	d.PartErrors[name] = err
+}
+
+func readWordPart(reader fs.FS, name, source string) (*wordBody, error) {
+	f, err := reader.Open(name)
+	if err != nil {
+		return nil, errPartMissing
+	}
+	defer f.Close()
+
+	body, err := parseWordprocessingML(f)
+	if err != nil {
+		return nil, describeXMLError(path.Base(name), err)
+	}
+	for i := range body.Paragraphs {
+		body.Paragraphs[i].Source = source
+		body.Paragraphs[i].Part = name
+	}
+	for i := range body.Revisions {
+		body.Revisions[i].Part = name
+	}
+	for i := range body.Comments {
+		body.Comments[i].Part = name
+	}
+	return body, nil
+}
+
+func partErrorNames(errs map[string]error) []string {
+	names := make([]string, 0, len(errs))
+	for name := range errs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,127 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+func wordPart(root, body string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?><w:` + root + ` xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` + body + `</w:` + root + `>`
+}
+
+func createDOCXWithHeaderAndComments() []byte {
+	return buildZip(map[string]string{
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         testPackageRels,
+		"word/document.xml":   wordPart("document", `<w:body><w:p><w:r><w:t>Договор поставки</w:t></w:r></w:p></w:body>`),
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/header" Target="header1.xml"/>
+  <Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/footer" Target="/word/footer1.xml"/>
+  <Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/comments" Target="comments.xml"/>
+  <Relationship Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="https://example.com" TargetMode="External"/>
+</Relationships>`,
+		"word/header1.xml":  wordPart("hdr", `<w:p><w:r><w:t>от 01.02.2024</w:t></w:r></w:p>`),
+		"word/footer1.xml":  wordPart("ftr", `<w:p><w:r><w:t>Страница</w:t></w:r></w:p>`),
+		"word/comments.xml": wordPart("comments", `<w:comment w:id="0"><w:p><w:r><w:t>Old date 01.01.2015 check</w:t></w:r></w:p></w:comment>`),
+	})
+}
+
+func TestParseDocument_AuxiliaryParts(t *testing.T) {
+	doc, err := parseDocument(createDOCXWithHeaderAndComments(), testBudget())
+	require.NoError(t, err)
+
+	var sources []string
+	for _, para := range doc.Paragraphs {
+		sources = append(sources, para.Source)
+	}
+	require.Equal(t, []string{SourceBody, SourceHeader, SourceFooter, SourceComments}, sources)
+	require.Equal(t, "word/footer1.xml", doc.Paragraphs[2].Part)
+	require.Equal(t, "Договор поставки\nот 01.02.2024\nСтраница\nOld date 01.01.2015 check", doc.Text)
+	require.Equal(t, "Договор поставки\nот 01.02.2024", doc.TextFrom([]string{SourceBody, SourceHeader}))
+}
+
+func TestParseDocument_ResolvedComments(t *testing.T) {
+	const w14 = `xmlns:w14="http://schemas.microsoft.com/office/word/2010/wordml"`
+	comment := func(id, author, paraID string) string {
+		return `<w:comment w:id="` + id + `" w:author="` + author + `"><w:p w14:paraId="` + paraID + `"><w:r><w:t>Комментарий</w:t></w:r></w:p></w:comment>`
+	}
+	doc, err := parseDocument(buildZip(map[string]string{
+		"word/document.xml": wordPart("document", `<w:body><w:p><w:r><w:t>Договор</w:t></w:r></w:p></w:body>`),
+		"word/_rels/document.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/comments" Target="comments.xml"/>
+  <Relationship Id="rId2" Type="http://schemas.microsoft.com/office/2011/relationships/commentsExtended" Target="commentsExtended.xml"/>
+</Relationships>`,
+		"word/comments.xml": `<w:comments xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` + w14 + `>` +
+			comment("0", "Иванов", "00000001") + comment("1", "Петров", "00000002") + comment("2", "Иванов", "00000003") + `</w:comments>`,
+		// Примечание 1 — ответ на разрешённое примечание 0, примечание 2 открыто.
+		"word/commentsExtended.xml": `<w15:commentsEx xmlns:w15="http://schemas.microsoft.com/office/word/2012/wordml">
+  <w15:commentEx w15:paraId="00000001" w15:done="1"/>
+  <w15:commentEx w15:paraId="00000002" w15:paraIdParent="00000001" w15:done="0"/>
+  <w15:commentEx w15:paraId="00000003" w15:done="0"/>
+</w15:commentsEx>`,
+	}), testBudget())
+	require.NoError(t, err)
+
+	var resolved []bool
+	for _, c := range doc.Comments {
+		require.Equal(t, "word/comments.xml", c.Part)
+		resolved = append(resolved, c.Resolved)
+	}
+	require.Equal(t, []bool{true, true, false}, resolved)
+
+	// commentsExtended.xml разбирается с тем же ограничением глубины, что и другие части.
+	budget := newReadBudget(config.LimitsConfig{MaxXMLDepth: 16})
+	_, err = parseDocument(buildZip(map[string]string{
+		"word/document.xml": wordPart("document", `<w:body><w:p><w:r><w:t>Договор</w:t></w:r></w:p></w:body>`),
+		"word/_rels/document.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/comments" Target="comments.xml"/>
+  <Relationship Id="rId2" Type="http://schemas.microsoft.com/office/2011/relationships/commentsExtended" Target="commentsExtended.xml"/>
+</Relationships>`,
+		"word/comments.xml": `<w:comments xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` + w14 + `>` + comment("0", "Иванов", "00000001") + `</w:comments>`,
+		"word/commentsExtended.xml": `<w15:commentsEx xmlns:w15="http://schemas.microsoft.com/office/word/2012/wordml">` +
+			strings.Repeat("<x>", 20) + strings.Repeat("</x>", 20) + `</w15:commentsEx>`,
+	}), budget)
+	require.NoError(t, err)
+	require.NotNil(t, budget.violation)
+	require.Equal(t, limitXMLDepth, budget.violation.limit)
+	require.Equal(t, "word/commentsExtended.xml", budget.violation.part)
+}
+
+func TestParseDocument_BrokenAuxiliaryPart(t *testing.T) {
+	doc, err := parseDocument(buildZip(map[string]string{
+		"word/document.xml": wordPart("document", `<w:body><w:p><w:r><w:t>Текст</w:t></w:r></w:p></w:body>`),
+		"word/_rels/document.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/header" Target="header1.xml"/>
+</Relationships>`,
+		"word/header1.xml": `<w:hdr`,
+	}), testBudget())
+	require.NoError(t, err)
+	require.NoError(t, doc.MainPartErr)
+	require.Contains(t, doc.PartErrors, "word/header1.xml")
+	require.Equal(t, "Текст", doc.Text)
+}
+
+func TestRules_PartsSelection(t *testing.T) {
+	payload := createDOCXWithHeaderAndComments()
+
+	all, err := profileEngine(configWithRules("date_span"))
+	require.NoError(t, err)
+	res, _ := all.Run(Request{Payload: payload}).Result("date_span")
+	require.Equal(t, CodeDateSpanExceeded, res.Code)
+
+	bodyAndHeaders, err := profileEngine(config.ProfileConfig{
+		Rules:    []string{"date_span", "cyrillic_ratio"},
+		Dates:    config.DateRuleConfig{Parts: []string{SourceBody, SourceHeader}},
+		Cyrillic: config.CyrillicRuleConfig{Parts: []string{SourceBody, SourceHeader, SourceFooter}},
+	})
+	require.NoError(t, err)
+	require.True(t, bodyAndHeaders.Run(Request{Payload: payload}).Valid())
+
+	_, err = profileEngine(config.ProfileConfig{Dates: config.DateRuleConfig{Parts: []string{"sidebar"}}})
+	require.Error(t, err)
+}
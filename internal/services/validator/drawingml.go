@@ -0,0 +1,50 @@
+package validator
+
+import (
+	"encoding/xml"
+	"io"
+	"io/fs"
+	"path"
+)
+
+const (
+	nsDrawingMain   = "http://schemas.openxmlformats.org/drawingml/2006/main"
+	nsDrawingStrict = "http://purl.oclc.org/ooxml/drawingml/main"
+)
+
+// drawingElement сопоставляет текстовые элементы DrawingML элементам
+// WordprocessingML. a:tab не переносится: в DrawingML это позиция табуляции в свойствах абзаца.
+func drawingElement(name xml.Name) (string, bool) {
+	if name.Space != nsDrawingMain && name.Space != nsDrawingStrict {
+		return "", false
+	}
+	switch name.Local {
+	case "p", "r", "t", "br", "tbl", "tr", "tc":
+		return name.Local, true
+	case "fld":
+		return "r", true
+	}
+	return "", false
+}
+
+func parseDrawingML(r io.Reader) (*wordBody, error) {
+	return parseTextMarkup(r, drawingElement)
+}
+
+func readDrawingPart(reader fs.FS, name, source string) (*wordBody, error) {
+	f, err := reader.Open(name)
+	if err != nil {
+		return nil, errPartMissing
+	}
+	defer f.Close()
+
+	body, err := parseDrawingML(f)
+	if err != nil {
+		return nil, describeXMLError(path.Base(name), err)
+	}
+	for i := range body.Paragraphs {
+		body.Paragraphs[i].Source = source
+		body.Paragraphs[i].Part = name
+	}
+	return body, nil
+}
@@ -0,0 +1,86 @@
+package validator
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	FormatDOCX = "docx"
+	FormatPDF  = "pdf"
+)
+
+// formatHandler разбирает документ своего формата. Результат разбора попадает
+// в отчёт первым; при провале правила профиля не запускаются. Все чтения
+// распакованных данных учитываются в budget.
+type formatHandler func(data []byte, budget *readBudget) (*Document, RuleResult)
+
+var formatHandlers = map[string]formatHandler{
+	FormatDOCX: parseDOCX,
+	FormatPDF:  parsePDF,
+	FormatODT:  parseODT,
+	FormatXLSX: parseXLSX,
+	FormatPPTX: parsePPTX,
+}
+
+// pdfHeaderWindow — спецификация допускает мусор перед %PDF- в первых 1024 байтах.
+const pdfHeaderWindow = 1024
+
+// detectFormat определяет формат по сигнатуре. Нераспознанные данные
+// разбираются как DOCX, чтобы ошибка указывала на повреждённый архив.
+func detectFormat(data []byte, budget *readBudget) string {
+	head := data
+	if len(head) > pdfHeaderWindow {
+		head = head[:pdfHeaderWindow]
+	}
+	if bytes.HasPrefix(data, []byte("%PDF-")) || (!bytes.HasPrefix(data, []byte("PK")) && bytes.Contains(head, []byte("%PDF-"))) {
+		return FormatPDF
+	}
+	if bytes.HasPrefix(data, []byte("PK")) {
+		return zipFormat(data, budget)
+	}
+	return FormatDOCX
+}
+
+func parseDOCX(data []byte, budget *readBudget) (*Document, RuleResult) {
+	doc, err := parseDocument(data, budget)
+	if err != nil {
+		return nil, failed(ruleArchive, CodeInvalidArchive, err.Error(), nil)
+	}
+	return doc, passed(ruleArchive, map[string]interface{}{"entries": len(doc.Archive.File)})
+}
+
+func invalidArchive(err error) RuleResult {
+	return failed(ruleArchive, CodeInvalidArchive, fmt.Sprintf("не является допустимым ZIP: %v", err), nil)
+}
+
+// zipFormat различает ZIP-форматы: OpenDocument — по записи mimetype или
+// манифесту (даже если mimetype лежит не первым, такой пакет должен получить
+// понятную ошибку ODT), OOXML — по основной части из _rels/.rels.
+func zipFormat(data []byte, budget *readBudget) string {
+	reader, err := openPackage(data, budget)
+	if err != nil {
+		return FormatDOCX
+	}
+	entries := make(map[string]bool, len(reader.File))
+	for _, f := range reader.File {
+		entries[f.Name] = true
+	}
+	if entries[odfMimetypePart] || entries[odfManifestPart] {
+		return FormatODT
+	}
+
+	main := officeDocumentPart(reader)
+	switch {
+	case strings.HasPrefix(main, "xl/"):
+		return FormatXLSX
+	case strings.HasPrefix(main, "ppt/"):
+		return FormatPPTX
+	case main == "" && entries[workbookPartName]:
+		return FormatXLSX
+	case main == "" && entries[presentationPartName]:
+		return FormatPPTX
+	}
+	return FormatDOCX
+}
@@ -0,0 +1,80 @@
+package validator
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+)
+
+const (
+	FormatODT = "odt"
+
+	odfMimetypePart = "mimetype"
+	odfManifestPart = "META-INF/manifest.xml"
+	odfContentPart  = "content.xml"
+	odfStylesPart   = "styles.xml"
+	odtMimetype     = "application/vnd.oasis.opendocument.text"
+)
+
+func parseODT(data []byte, budget *readBudget) (*Document, RuleResult) {
+	reader, err := openPackage(data, budget)
+	if err != nil {
+		return nil, invalidArchive(err)
+	}
+
+	details := map[string]interface{}{"entries": len(reader.File)}
+	if res, ok := checkODFMimetype(reader.Reader, details); !ok {
+		return nil, res
+	}
+
+	doc := &Document{Format: FormatODT, Size: int64(len(data)), Archive: reader.Reader, parts: reader, raw: data, MainPart: odfContentPart}
+	doc.readODFMetadata(reader)
+	body, err := readODFPart(reader, odfContentPart, SourceBody)
+	if err != nil {
+		doc.MainPartErr = err
+		return doc, passed(ruleArchive, details)
+	}
+	doc.Paragraphs = body.Paragraphs
+	doc.Tables = body.Tables
+
+	// Колонтитулы OpenDocument хранятся в стилях страниц.
+	styles, err := readODFPart(reader, odfStylesPart, "")
+	switch {
+	case err == nil:
+		doc.Paragraphs = append(doc.Paragraphs, styles.Paragraphs...)
+	case err != errPartMissing:
+		doc.addPartError(odfStylesPart, err)
+	}
+	doc.Text = joinParagraphs(doc.Paragraphs)
+	return doc, passed(ruleArchive, details)
+}
+
+// checkODFMimetype проверяет, что mimetype — первая запись пакета, хранится
+// без сжатия и объявляет текстовый документ: по этому признаку файлы
+// OpenDocument опознаются без распаковки.
+func checkODFMimetype(reader *zip.Reader, details map[string]interface{}) (RuleResult, bool) {
+	first := reader.File[0]
+	if first.Name != odfMimetypePart {
+		return failed(ruleArchive, CodeInvalidPackage,
+			"mimetype должен быть первой записью пакета OpenDocument", details), false
+	}
+	if first.Method != zip.Store {
+		return failed(ruleArchive, CodeInvalidPackage, "mimetype не должен быть сжат", details), false
+	}
+
+	f, err := first.Open()
+	if err != nil {
+		return failed(ruleArchive, CodeInvalidArchive, fmt.Sprintf("невозможно прочитать mimetype: %v", err), details), false
+	}
+	defer f.Close()
+	mimetype, err := io.ReadAll(io.LimitReader(f, 256))
+	if err != nil {
+		return failed(ruleArchive, CodeInvalidArchive, fmt.Sprintf("невозможно прочитать mimetype: %v", err), details), false
+	}
+	details["mimetype"] = string(mimetype)
+	if string(mimetype) != odtMimetype {
+		return failed(ruleArchive, CodeInvalidPackage,
+			fmt.Sprintf("неподдерживаемый тип OpenDocument: %s", mimetype), details), false
+	}
+	return RuleResult{}, true
+}
@@ -0,0 +1,101 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/qnhqn1/file-validator/internal/services/validator/pdf"
+)
+
+const rulePDFStructure = "pdf_structure"
+
+func parsePDF(data []byte, budget *readBudget) (*Document, RuleResult) {
+	r, err := pdf.Open(data,
+		pdf.WithMaxStreamBytes(budget.limits.MaxEntryBytes),
+		pdf.WithMaxOperators(budget.limits.MaxPDFOperators),
+		pdf.WithMaxTextBytes(budget.limits.MaxPDFTextBytes),
+		pdf.WithMaxNesting(budget.limits.MaxXMLDepth),
+		pdf.WithStreamBudget(pdfBudget{budget}))
+	if err != nil {
+		notePDFLimit(budget, err)
+		return nil, failed(rulePDFStructure, CodeInvalidPDF,
+			fmt.Sprintf("не является допустимым PDF: %v", err), nil)
+	}
+
+	info := r.Info()
+	details := map[string]interface{}{
+		"version":       info.Version,
+		"pages":         info.Pages,
+		"encrypted":     info.Encrypted,
+		"xref_repaired": info.XrefRepaired,
+	}
+	switch {
+	case !info.HasEOFMarker:
+		return nil, failed(rulePDFStructure, CodeInvalidPDF, "PDF не содержит маркера %EOF, файл обрезан", details)
+	case info.Encrypted:
+		return nil, failed(rulePDFStructure, CodeEncryptedDocument, "PDF зашифрован, текст недоступен для проверки", details)
+	case info.Pages == 0:
+		return nil, failed(rulePDFStructure, CodeInvalidPDF, "PDF не содержит страниц", details)
+	}
+
+	pages, err := r.PageTexts()
+	if err != nil {
+		notePDFLimit(budget, err)
+		return nil, failed(rulePDFStructure, CodeInvalidPDF,
+			fmt.Sprintf("не удалось извлечь текст PDF: %v", err), details)
+	}
+
+	doc := &Document{Format: FormatPDF, Size: int64(len(data)), Metadata: pdfMetadata(r.DocInfo(), info.Pages)}
+	for i, text := range pages {
+		part := fmt.Sprintf("page %d", i+1)
+		for _, line := range strings.Split(text, "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			doc.Paragraphs = append(doc.Paragraphs, Paragraph{
+				Text: line, Source: SourceBody, Part: part, Table: outsideOfTables,
+			})
+		}
+	}
+	doc.Text = joinParagraphs(doc.Paragraphs)
+	return doc, passed(rulePDFStructure, details)
+}
+
+// notePDFLimit переводит ошибки лимитов пакета pdf в нарушение лимита ресурсов.
+func notePDFLimit(budget *readBudget, err error) {
+	switch {
+	case errors.Is(err, pdf.ErrStreamTooLarge):
+		max := budget.limits.MaxEntryBytes
+		budget.exceed(limitEntryBytes, "", max, "поток PDF распаковывается более чем в %d байт", max)
+	case errors.Is(err, pdf.ErrNestingTooDeep):
+		max := budget.limits.MaxXMLDepth
+		budget.exceed(limitXMLDepth, "", max, "вложенность объектов или форм PDF превышает %d", max)
+	case errors.Is(err, pdf.ErrTooManyOperators):
+		max := budget.limits.MaxPDFOperators
+		budget.exceed(limitPDFOperators, "", max, "содержимое PDF содержит более %d операторов", max)
+	case errors.Is(err, pdf.ErrTextTooLarge):
+		max := budget.limits.MaxPDFTextBytes
+		budget.exceed(limitPDFTextBytes, "", max, "из PDF извлекается более %d байт текста", max)
+	}
+}
+
+// pdfBudget учитывает распакованные потоки PDF в бюджете документа: суммарный
+// объём и степень сжатия проверяются так же, как у записей архива.
+type pdfBudget struct {
+	*readBudget
+}
+
+func (b pdfBudget) Decoded(n, read, compressed int64) error {
+	b.total += n
+	limits := b.limits
+	switch {
+	case b.total > limits.MaxUncompressedBytes:
+		return b.exceed(limitUncompressedBytes, "", limits.MaxUncompressedBytes,
+			"документ распаковывается более чем в %d байт", limits.MaxUncompressedBytes)
+	case read > ratioCheckMinBytes && float64(read) > limits.MaxCompressionRatio*float64(compressed):
+		return b.exceed(limitCompressionRatio, "", limits.MaxCompressionRatio,
+			"поток PDF сжат более чем в %g раз", limits.MaxCompressionRatio)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package validator
+
+import (
+	"encoding/xml"
+	"io"
+	"io/fs"
+	"path"
+)
+
+const (
+	FormatPPTX = "pptx"
+
+	presentationPartName = "ppt/presentation.xml"
+)
+
+func parsePPTX(data []byte, budget *readBudget) (*Document, RuleResult) {
+	reader, err := openPackage(data, budget)
+	if err != nil {
+		return nil, invalidArchive(err)
+	}
+
+	main := officeDocumentPart(reader)
+	if main == "" {
+		main = presentationPartName
+	}
+	doc := &Document{Format: FormatPPTX, Size: int64(len(data)), Archive: reader.Reader, parts: reader, raw: data, MainPart: main}
+	doc.readOOXMLMetadata(reader)
+	details := map[string]interface{}{"entries": len(reader.File)}
+
+	slides, err := readSlideList(reader, main)
+	if err != nil {
+		doc.MainPartErr = err
+		return doc, passed(ruleArchive, details)
+	}
+	details["slides"] = len(slides)
+
+	for _, slide := range slides {
+		body, err := readDrawingPart(reader, slide, SourceBody)
+		if err != nil {
+			doc.addPartError(slide, err)
+			continue
+		}
+		doc.appendBody(body)
+	}
+	// Заметки докладчика идут после всех слайдов, как колонтитулы в DOCX.
+	for _, slide := range slides {
+		for _, rel := range relationshipTargets(reader, slide) {
+			if rel.External() || rel.Kind() != "notesSlide" {
+				continue
+			}
+			body, err := readDrawingPart(reader, rel.Target, SourceNotes)
+			if err != nil {
+				doc.addPartError(rel.Target, err)
+				continue
+			}
+			doc.appendBody(body)
+		}
+	}
+	doc.Text = joinParagraphs(doc.Paragraphs)
+	return doc, passed(ruleArchive, details)
+}
+
+// readSlideList возвращает слайды в порядке показа из p:sldIdLst.
+func readSlideList(reader fs.FS, main string) ([]string, error) {
+	f, err := reader.Open(main)
+	if err != nil {
+		return nil, errPartMissing
+	}
+	defer f.Close()
+
+	targets := relationshipTargets(reader, main)
+	var slides []string
+	root := false
+	dec := newXMLDecoder(f)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, describeXMLError(path.Base(main), err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		root = true
+		if start.Name.Local != "sldId" {
+			continue
+		}
+		if rel, ok := targets[relationshipID(start.Attr)]; ok && !rel.External() {
+			slides = append(slides, rel.Target)
+		}
+	}
+	if !root {
+		return nil, describeXMLError(path.Base(main), errNoRootElement)
+	}
+	return slides, nil
+}
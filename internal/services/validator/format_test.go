@@ -0,0 +1,124 @@
+package validator
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+// createPDFWithText строит одностраничный PDF, текст которого набран
+// стандартным шрифтом с кодировкой WinAnsi (латиница) или CP1251 (кириллица).
+func createPDFWithText(lines ...string) []byte {
+	var content bytes.Buffer
+	content.WriteString("BT /F1 12 Tf 72 700 Td ")
+	for i, line := range lines {
+		if i > 0 {
+			content.WriteString("0 -14 Td ")
+		}
+		fmt.Fprintf(&content, "(%s) Tj ", toCP1251(line))
+	}
+	content.WriteString("ET")
+	return createPDFWithStream("", content.Bytes())
+}
+
+// createPDFWithStream строит одностраничный PDF с заданным потоком содержимого.
+func createPDFWithStream(dict string, content []byte) []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 5 0 R >> >> /Contents 4 0 R >>",
+		fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(content), content),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Arial /Encoding /CP1251 >>",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+func toCP1251(s string) []byte {
+	var out []byte
+	for _, r := range s {
+		switch {
+		case r >= 'А' && r <= 'я':
+			out = append(out, byte(r-'А'+0xC0))
+		case r == 'ё':
+			out = append(out, 0xB8)
+		case r == 'Ё':
+			out = append(out, 0xA8)
+		default:
+			out = append(out, byte(r))
+		}
+	}
+	return out
+}
+
+func TestDetectFormat(t *testing.T) {
+	require.Equal(t, FormatPDF, detectFormat([]byte("%PDF-1.7\n"), testBudget()))
+	require.Equal(t, FormatPDF, detectFormat([]byte("\x00\x00junk%PDF-1.4\n"), testBudget()))
+	require.Equal(t, FormatDOCX, detectFormat(createValidDOCXPayload(), testBudget()))
+	require.Equal(t, FormatDOCX, detectFormat([]byte("invalid"), testBudget()))
+}
+
+func TestEngine_PDF(t *testing.T) {
+	engine, err := NewEngine(config.ValidationConfig{})
+	require.NoError(t, err)
+
+	report := engine.Run(Request{Payload: createPDFWithText("Договор поставки", "от 01.02.2024 до 01.03.2024")})
+	require.True(t, report.Valid(), report.Failures())
+	require.Equal(t, FormatPDF, report.Format)
+
+	res, ok := report.Result(rulePDFStructure)
+	require.True(t, ok)
+	require.Equal(t, 1, res.Details["pages"])
+	require.Equal(t, "1.4", res.Details["version"])
+
+	res, _ = report.Result(ruleRequiredParts)
+	require.Equal(t, StatusSkipped, res.Status)
+
+	res, _ = report.Result("date_span")
+	require.Equal(t, 2, res.Details["dates_found"])
+}
+
+func TestEngine_PDFRulesApplyToText(t *testing.T) {
+	engine, err := NewEngine(config.ValidationConfig{})
+	require.NoError(t, err)
+
+	report := engine.Run(Request{Payload: createPDFWithText("Supply agreement 01.02.2024")})
+	require.Equal(t, CodeCyrillicRatioLow, report.FirstFailureCode())
+}
+
+func TestEngine_PDFStructureFailures(t *testing.T) {
+	engine, err := NewEngine(config.ValidationConfig{})
+	require.NoError(t, err)
+
+	truncated := createPDFWithText("Договор от 01.02.2024")
+	truncated = truncated[:len(truncated)-len("%%EOF\n")]
+	report := engine.Run(Request{Payload: truncated})
+	require.Equal(t, CodeInvalidPDF, report.FirstFailureCode())
+	require.Equal(t, []string{rulePDFStructure}, ruleNames(report))
+
+	report = engine.Run(Request{Payload: []byte("%PDF-1.4\nмусор без объектов")})
+	require.Equal(t, CodeInvalidPDF, report.FirstFailureCode())
+
+	encrypted := bytes.Replace(createPDFWithText("Договор от 01.02.2024"),
+		[]byte("/Root 1 0 R"), []byte("/Root 1 0 R /Encrypt << /Filter /Standard >>"), 1)
+	report = engine.Run(Request{Payload: encrypted})
+	require.Equal(t, CodeEncryptedDocument, report.FirstFailureCode())
+	require.Contains(t, (&ValidationError{Report: report}).Error(), "Валидация PDF не удалась")
+}
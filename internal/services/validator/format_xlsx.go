@@ -0,0 +1,110 @@
+package validator
+
+import (
+	"io"
+	"io/fs"
+	"path"
+)
+
+const (
+	FormatXLSX = "xlsx"
+
+	workbookPartName = "xl/workbook.xml"
+)
+
+func parseXLSX(data []byte, budget *readBudget) (*Document, RuleResult) {
+	reader, err := openPackage(data, budget)
+	if err != nil {
+		return nil, invalidArchive(err)
+	}
+
+	main := officeDocumentPart(reader)
+	if main == "" {
+		main = workbookPartName
+	}
+	doc := &Document{Format: FormatXLSX, Size: int64(len(data)), Archive: reader.Reader, parts: reader, raw: data, MainPart: main}
+	doc.readOOXMLMetadata(reader)
+	details := map[string]interface{}{"entries": len(reader.File)}
+
+	var workbook *workbookInfo
+	if err := readPart(reader, main, func(r io.Reader) (err error) {
+		workbook, err = parseWorkbook(r)
+		return err
+	}); err != nil {
+		doc.MainPartErr = err
+		return doc, passed(ruleArchive, details)
+	}
+	details["sheets"] = len(workbook.Sheets)
+
+	targets := relationshipTargets(reader, main)
+	var shared []string
+	var dateStyles map[int]bool
+	for _, rel := range targets {
+		if rel.External() {
+			continue
+		}
+		var err error
+		switch rel.Kind() {
+		case "sharedStrings":
+			err = readPart(reader, rel.Target, func(r io.Reader) (err error) {
+				shared, err = parseSharedStrings(r)
+				return err
+			})
+		case "styles":
+			err = readPart(reader, rel.Target, func(r io.Reader) (err error) {
+				dateStyles, err = parseDateStyles(r)
+				return err
+			})
+		}
+		if err != nil {
+			doc.addPartError(rel.Target, err)
+		}
+	}
+
+	for _, sheet := range workbook.Sheets {
+		rel, ok := targets[sheet.RID]
+		if !ok || rel.External() || rel.Kind() != "worksheet" {
+			continue
+		}
+		var table *Table
+		if err := readPart(reader, rel.Target, func(r io.Reader) (err error) {
+			table, err = parseWorksheet(r, shared, dateStyles, workbook.Date1904)
+			return err
+		}); err != nil {
+			doc.addPartError(rel.Target, err)
+			continue
+		}
+		doc.appendBody(sheetBody(table, rel.Target))
+	}
+	doc.Text = joinParagraphs(doc.Paragraphs)
+	return doc, passed(ruleArchive, details)
+}
+
+// sheetBody превращает непустые ячейки листа в абзацы с координатами ячейки.
+func sheetBody(table *Table, part string) *wordBody {
+	body := &wordBody{Tables: []Table{*table}}
+	for r, row := range table.Rows {
+		for c, text := range row {
+			if text == "" {
+				continue
+			}
+			body.Paragraphs = append(body.Paragraphs, Paragraph{
+				Text: text, Runs: []Run{{Text: text}}, Source: SourceBody, Part: part, Table: 0, Row: r, Cell: c,
+			})
+		}
+	}
+	return body
+}
+
+// readPart открывает запись архива и передаёт её разборщику, приводя ошибки к общему виду.
+func readPart(reader fs.FS, name string, parse func(io.Reader) error) error {
+	f, err := reader.Open(name)
+	if err != nil {
+		return errPartMissing
+	}
+	defer f.Close()
+	if err := parse(f); err != nil {
+		return describeXMLError(path.Base(name), err)
+	}
+	return nil
+}
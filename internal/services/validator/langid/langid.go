@@ -0,0 +1,192 @@
+// Package langid определяет язык текста по частотам символьных n-грамм.
+// Профили строятся при загрузке пакета из встроенных образцов текста в
+// profiles/; профиль транслитерированного русского получается из русского образца.
+package langid
+
+import (
+	"embed"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Коды языков по BCP 47.
+const (
+	Russian         = "ru"
+	RussianTranslit = "ru-Latn"
+	Ukrainian       = "uk"
+	Belarusian      = "be"
+	Bulgarian       = "bg"
+	Serbian         = "sr"
+	English         = "en"
+	// Unknown — в тексте нет букв.
+	Unknown = "und"
+)
+
+const (
+	maxGram = 3
+	// smoothing — аддитивное сглаживание: n-грамма, которой нет в образце,
+	// получает вероятность как встретившаяся smoothing раз.
+	smoothing = 0.5
+	// foreignLetter — логарифм вероятности буквы, которой нет в алфавите языка:
+	// «ы» в болгарском или «ї» в русском почти исключают язык.
+	foreignLetter = -30.0
+	// maxRunes ограничивает разбираемую часть текста: длиннее язык не уточняется.
+	maxRunes = 4096
+)
+
+//go:embed profiles/*.txt
+var samples embed.FS
+
+type profile struct {
+	lang   string
+	logp   map[string]float64
+	unseen float64
+}
+
+func (p profile) score(g string) float64 {
+	if lp, ok := p.logp[g]; ok {
+		return lp
+	}
+	if utf8.RuneCountInString(g) == 1 {
+		return foreignLetter
+	}
+	return p.unseen
+}
+
+var profiles = loadProfiles()
+
+func loadProfiles() []profile {
+	entries, err := samples.ReadDir("profiles")
+	if err != nil {
+		panic(err)
+	}
+	var out []profile
+	for _, e := range entries {
+		data, err := samples.ReadFile(path.Join("profiles", e.Name()))
+		if err != nil {
+			panic(err)
+		}
+		lang := strings.TrimSuffix(e.Name(), ".txt")
+		out = append(out, train(lang, string(data)))
+		if lang == Russian {
+			out = append(out, train(RussianTranslit, Transliterate(string(data))))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].lang < out[j].lang })
+	return out
+}
+
+func train(lang, text string) profile {
+	counts := make(map[string]int)
+	total := 0
+	grams(text, func(g string) {
+		counts[g]++
+		total++
+	})
+	denom := float64(total) + smoothing*float64(2*len(counts))
+	p := profile{lang: lang, logp: make(map[string]float64, len(counts)), unseen: math.Log(smoothing / denom)}
+	for g, c := range counts {
+		p.logp[g] = math.Log((float64(c) + smoothing) / denom)
+	}
+	return p
+}
+
+// grams перебирает n-граммы от 1 до maxGram внутри слов, дополненных пробелами
+// по краям, — так начало и конец слова тоже становятся признаками.
+func grams(text string, fn func(string)) {
+	var word []rune
+	seen := 0
+	flush := func() {
+		if len(word) == 0 {
+			return
+		}
+		padded := make([]rune, 0, len(word)+2)
+		padded = append(append(append(padded, ' '), word...), ' ')
+		for n := 1; n <= maxGram; n++ {
+			for i := 0; i+n <= len(padded); i++ {
+				if n == 1 && padded[i] == ' ' {
+					continue
+				}
+				fn(string(padded[i : i+n]))
+			}
+		}
+		word = word[:0]
+	}
+	for _, r := range text {
+		if seen++; seen > maxRunes {
+			break
+		}
+		if unicode.IsLetter(r) {
+			word = append(word, unicode.ToLower(r))
+			continue
+		}
+		flush()
+	}
+	flush()
+}
+
+// Detect возвращает наиболее вероятный язык текста или Unknown, если букв нет.
+func Detect(text string) string {
+	scores := make([]float64, len(profiles))
+	found := false
+	grams(text, func(g string) {
+		found = true
+		for i, p := range profiles {
+			scores[i] += p.score(g)
+		}
+	})
+	if !found {
+		return Unknown
+	}
+	best := 0
+	for i := range scores {
+		if scores[i] > scores[best] {
+			best = i
+		}
+	}
+	return profiles[best].lang
+}
+
+// Supported возвращает коды языков, для которых есть профиль.
+func Supported() []string {
+	langs := make([]string, len(profiles))
+	for i, p := range profiles {
+		langs[i] = p.lang
+	}
+	return langs
+}
+
+func IsSupported(lang string) bool {
+	for _, p := range profiles {
+		if p.lang == lang {
+			return true
+		}
+	}
+	return false
+}
+
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+}
+
+// Transliterate переводит русский текст в латиницу по распространённой
+// практической схеме (zh, kh, ts, ya, yu); прочие символы не меняются, регистр теряется.
+func Transliterate(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if s, ok := translit[unicode.ToLower(r)]; ok {
+			b.WriteString(s)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
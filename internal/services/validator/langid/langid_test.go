@@ -0,0 +1,33 @@
+package langid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect(t *testing.T) {
+	cases := map[string]string{
+		"Арендатор обязан своевременно вносить арендную плату и содержать помещение в надлежащем состоянии.":          Russian,
+		"Орендар зобов'язаний своєчасно вносити орендну плату й утримувати приміщення в належному стані.":             Ukrainian,
+		"Арандатар абавязаны своечасова ўносіць арэндную плату і ўтрымліваць памяшканне ў належным стане.":            Belarusian,
+		"Наемателят е длъжен своевременно да плаща наема и да поддържа помещението в добро състояние.":                Bulgarian,
+		"Закупац је дужан да благовремено плаћа закупнину и да одржава просторију у исправном стању.":                 Serbian,
+		"Arendator obyazan svoevremenno vnosit arendnuyu platu i soderzhat pomeshchenie v nadlezhashchem sostoyanii.": RussianTranslit,
+		"The tenant shall pay the rent on time and keep the premises in good condition.":                              English,
+		"12345 — 67/89": Unknown,
+	}
+	for text, want := range cases {
+		require.Equal(t, want, Detect(text), text)
+	}
+}
+
+func TestSupported(t *testing.T) {
+	require.Equal(t, []string{"be", "bg", "en", "ru", "ru-Latn", "sr", "uk"}, Supported())
+	require.True(t, IsSupported(Russian))
+	require.False(t, IsSupported("kk"))
+}
+
+func TestTransliterate(t *testing.T) {
+	require.Equal(t, "shchuka i ezh", Transliterate("Щука и ёж"))
+}
@@ -0,0 +1,188 @@
+package validator
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+const (
+	ruleResourceLimits = "resource_limits"
+
+	limitObjectBytes       = "max_object_bytes"
+	limitEntryBytes        = "max_entry_bytes"
+	limitUncompressedBytes = "max_uncompressed_bytes"
+	limitCompressionRatio  = "max_compression_ratio"
+	limitEntries           = "max_entries"
+	limitXMLDepth          = "max_xml_depth"
+	limitPDFOperators      = "max_pdf_operators"
+	limitPDFTextBytes      = "max_pdf_text_bytes"
+
+	// ratioCheckMinBytes — меньшие записи не проверяются на степень сжатия:
+	// короткие повторяющиеся XML законно сжимаются в сотни раз.
+	ratioCheckMinBytes = 1 << 20
+)
+
+var errResourceLimit = errors.New("превышен лимит ресурсов")
+
+type limitError struct {
+	limit string
+	part  string
+	max   interface{}
+	msg   string
+}
+
+func (e *limitError) Error() string { return e.msg }
+
+func (e *limitError) Unwrap() error { return errResourceLimit }
+
+func (e *limitError) result() RuleResult {
+	details := map[string]interface{}{"limit": e.limit, "max": e.max}
+	if e.part != "" {
+		details["part"] = e.part
+	}
+	return failed(ruleResourceLimits, CodeResourceLimitExceeded, e.msg, details)
+}
+
+// readBudget учитывает ресурсы, потраченные на разбор одного документа.
+// Первое нарушение запоминается: разборщики превращают ошибку чтения в
+// ошибку части, а движок по нарушению выносит отдельный вердикт.
+type readBudget struct {
+	limits    config.LimitsConfig
+	total     int64
+	violation *limitError
+}
+
+func newReadBudget(limits config.LimitsConfig) *readBudget {
+	return &readBudget{limits: limits.WithDefaults()}
+}
+
+func (b *readBudget) exceed(limit, part string, max interface{}, format string, args ...interface{}) *limitError {
+	err := &limitError{limit: limit, part: part, max: max, msg: fmt.Sprintf(format, args...)}
+	if b.violation == nil {
+		b.violation = err
+	}
+	return err
+}
+
+// packageReader — ZIP-пакет, записи которого читаются с учётом лимитов.
+// Реализует fs.FS, поэтому разборщики частей не зависят от того, открыт ли
+// пакет с лимитами (при разборе) или без них.
+type packageReader struct {
+	*zip.Reader
+	budget *readBudget
+}
+
+func openPackage(data []byte, budget *readBudget) (*packageReader, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	if max := budget.limits.MaxEntries; len(reader.File) > max {
+		return nil, budget.exceed(limitEntries, "", max,
+			"архив содержит %d записей, допускается не более %d", len(reader.File), max)
+	}
+	return &packageReader{Reader: reader, budget: budget}, nil
+}
+
+func (p *packageReader) Open(name string) (fs.File, error) {
+	f, err := p.Reader.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	entry := &limitedEntry{File: f, name: name, budget: p.budget}
+	if header, ok := info.Sys().(*zip.FileHeader); ok {
+		entry.compressed = header.CompressedSize64
+		if max := p.budget.limits.MaxEntryBytes; header.UncompressedSize64 > uint64(max) {
+			f.Close()
+			return nil, p.budget.exceed(limitEntryBytes, name, max,
+				"запись %s распаковывается в %d байт, допускается не более %d", name, header.UncompressedSize64, max)
+		}
+	}
+	return entry, nil
+}
+
+// limitedEntry считает фактически распакованные байты: суммарный объём
+// и степень сжатия становятся известны только по мере распаковки.
+type limitedEntry struct {
+	fs.File
+	name       string
+	compressed uint64
+	read       int64
+	budget     *readBudget
+}
+
+func (e *limitedEntry) Read(p []byte) (int, error) {
+	n, err := e.File.Read(p)
+	e.read += int64(n)
+	e.budget.total += int64(n)
+
+	limits := e.budget.limits
+	switch {
+	case e.read > limits.MaxEntryBytes:
+		return n, e.budget.exceed(limitEntryBytes, e.name, limits.MaxEntryBytes,
+			"запись %s распаковывается более чем в %d байт", e.name, limits.MaxEntryBytes)
+	case e.budget.total > limits.MaxUncompressedBytes:
+		return n, e.budget.exceed(limitUncompressedBytes, e.name, limits.MaxUncompressedBytes,
+			"документ распаковывается более чем в %d байт", limits.MaxUncompressedBytes)
+	case e.read > ratioCheckMinBytes && float64(e.read) > limits.MaxCompressionRatio*float64(e.compressed):
+		return n, e.budget.exceed(limitCompressionRatio, e.name, limits.MaxCompressionRatio,
+			"запись %s сжата более чем в %g раз", e.name, limits.MaxCompressionRatio)
+	}
+	return n, err
+}
+
+// xmlDecoder ограничивает глубину вложенности элементов, если часть открыта
+// через packageReader. Skip пропускает поддерево целиком и глубину не меняет.
+type xmlDecoder struct {
+	*xml.Decoder
+	depth int
+	entry *limitedEntry
+}
+
+func newXMLDecoder(r io.Reader) *xmlDecoder {
+	d := &xmlDecoder{Decoder: xml.NewDecoder(r)}
+	d.entry, _ = r.(*limitedEntry)
+	return d
+}
+
+func (d *xmlDecoder) Token() (xml.Token, error) {
+	tok, err := d.Decoder.Token()
+	switch tok.(type) {
+	case xml.StartElement:
+		d.depth++
+		if d.entry != nil && d.depth > d.entry.budget.limits.MaxXMLDepth {
+			max := d.entry.budget.limits.MaxXMLDepth
+			return nil, d.entry.budget.exceed(limitXMLDepth, d.entry.name, max,
+				"вложенность элементов в %s превышает %d", d.entry.name, max)
+		}
+	case xml.EndElement:
+		d.depth--
+	}
+	return tok, err
+}
+
+// Decode разбирает документ в v через Token, иначе встроенный Decode
+// обошёл бы проверку глубины.
+func (d *xmlDecoder) Decode(v interface{}) error {
+	return xml.NewTokenDecoder(d).Decode(v)
+}
+
+func (d *xmlDecoder) Skip() error {
+	if err := d.Decoder.Skip(); err != nil {
+		return err
+	}
+	d.depth--
+	return nil
+}
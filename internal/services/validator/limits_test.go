@@ -0,0 +1,116 @@
+package validator
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+func testBudget() *readBudget {
+	return newReadBudget(config.LimitsConfig{})
+}
+
+func limitsEngine(t *testing.T, limits config.LimitsConfig) *Engine {
+	engine, err := NewEngine(config.ValidationConfig{Limits: limits})
+	require.NoError(t, err)
+	return engine
+}
+
+func requireLimit(t *testing.T, report *ValidationReport, limit string) {
+	t.Helper()
+	require.Equal(t, VerdictResourceLimitExceeded, report.Verdict)
+	res, ok := report.Result(ruleResourceLimits)
+	require.True(t, ok, ruleNames(report))
+	require.Equal(t, CodeResourceLimitExceeded, res.Code)
+	require.Equal(t, limit, res.Details["limit"])
+}
+
+func TestLimits_CompressionRatio(t *testing.T) {
+	// 4 МиБ пробелов сжимаются примерно в тысячу раз.
+	payload := createDOCX(`<w:p><w:r><w:t>Договор от 01.02.2024`+strings.Repeat(" ", 4<<20)+`</w:t></w:r></w:p>`, nil)
+
+	report := limitsEngine(t, config.LimitsConfig{}).Run(Request{Payload: payload})
+	requireLimit(t, report, limitCompressionRatio)
+	require.Equal(t, FormatDOCX, report.Format)
+	// После нарушения правила профиля не выполняются.
+	require.Equal(t, []string{ruleResourceLimits}, ruleNames(report))
+
+	report = limitsEngine(t, config.LimitsConfig{MaxCompressionRatio: 10000}).Run(Request{Payload: payload})
+	require.True(t, report.Valid(), report.Failures())
+}
+
+func TestLimits_EntryBytes(t *testing.T) {
+	payload := createDOCXWithText(strings.Repeat("Договор от 01.02.2024 ", 500))
+
+	report := limitsEngine(t, config.LimitsConfig{MaxEntryBytes: 4096}).Run(Request{Payload: payload})
+	requireLimit(t, report, limitEntryBytes)
+	res, _ := report.Result(ruleResourceLimits)
+	require.Equal(t, "word/document.xml", res.Details["part"])
+}
+
+func TestLimits_EntriesObjectAndTotal(t *testing.T) {
+	payload := createDOCXWithText("Договор от 01.02.2024")
+
+	requireLimit(t, limitsEngine(t, config.LimitsConfig{MaxEntries: 2}).Run(Request{Payload: payload}), limitEntries)
+
+	report := limitsEngine(t, config.LimitsConfig{MaxObjectBytes: 64}).Run(Request{Payload: payload})
+	requireLimit(t, report, limitObjectBytes)
+	require.Empty(t, report.Format)
+	require.Contains(t, (&ValidationError{Report: report}).Error(), "Валидация документа не удалась")
+
+	requireLimit(t, limitsEngine(t, config.LimitsConfig{MaxUncompressedBytes: 100}).Run(Request{Payload: payload}), limitUncompressedBytes)
+}
+
+func TestLimits_XMLDepth(t *testing.T) {
+	nested := strings.Repeat(`<w:tbl><w:tr><w:tc>`, 20) + `<w:p><w:r><w:t>Договор от 01.02.2024</w:t></w:r></w:p>` + strings.Repeat(`</w:tc></w:tr></w:tbl>`, 20)
+	payload := createDOCX(nested, nil)
+
+	requireLimit(t, limitsEngine(t, config.LimitsConfig{MaxXMLDepth: 32}).Run(Request{Payload: payload}), limitXMLDepth)
+	require.True(t, limitsEngine(t, config.LimitsConfig{}).Run(Request{Payload: payload}).Valid())
+
+	// Части, которые разбираются целиком через Decode, тоже ограничены.
+	types := `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		strings.Repeat(`<x>`, 40) + strings.Repeat(`</x>`, 40) + `</Types>`
+	budget := newReadBudget(config.LimitsConfig{MaxXMLDepth: 32})
+	pkg, err := openPackage(createDOCX("", map[string]string{"[Content_Types].xml": types}), budget)
+	require.NoError(t, err)
+	_, err = readContentTypes(pkg)
+	require.ErrorIs(t, err, errResourceLimit)
+	require.Equal(t, limitXMLDepth, budget.violation.limit)
+}
+
+func TestLimits_PDFStream(t *testing.T) {
+	content := fmt.Sprintf("BT /F1 12 Tf 72 700 Td (%s) Tj ET %s", toCP1251("Договор поставки от 01.02.2024"), strings.Repeat(" ", 4096))
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write([]byte(content))
+	zw.Close()
+	payload := createPDFWithStream("/Filter /FlateDecode", compressed.Bytes())
+
+	requireLimit(t, limitsEngine(t, config.LimitsConfig{MaxEntryBytes: 1024}).Run(Request{Payload: payload}), limitEntryBytes)
+	requireLimit(t, limitsEngine(t, config.LimitsConfig{MaxUncompressedBytes: 1024}).Run(Request{Payload: payload}), limitUncompressedBytes)
+	require.True(t, limitsEngine(t, config.LimitsConfig{}).Run(Request{Payload: payload}).Valid())
+
+	// Потоки PDF проверяются на степень сжатия так же, как записи архива.
+	var bomb bytes.Buffer
+	zw = zlib.NewWriter(&bomb)
+	zw.Write([]byte(content + strings.Repeat(" ", 4<<20)))
+	zw.Close()
+	requireLimit(t, limitsEngine(t, config.LimitsConfig{}).Run(Request{Payload: createPDFWithStream("/Filter /FlateDecode", bomb.Bytes())}), limitCompressionRatio)
+
+	nested := fmt.Sprintf("%s0 0 m%s BT /F1 12 Tf (%s) Tj ET", strings.Repeat("[", 64), strings.Repeat("]", 64), toCP1251("Договор поставки от 01.02.2024"))
+	requireLimit(t, limitsEngine(t, config.LimitsConfig{MaxXMLDepth: 32}).Run(Request{Payload: createPDFWithStream("", []byte(nested))}), limitXMLDepth)
+}
+
+func TestLimits_PDFContent(t *testing.T) {
+	payload := createPDFWithText(strings.Repeat("Договор поставки от 01.02.2024 ", 20))
+
+	requireLimit(t, limitsEngine(t, config.LimitsConfig{MaxPDFOperators: 3}).Run(Request{Payload: payload}), limitPDFOperators)
+	requireLimit(t, limitsEngine(t, config.LimitsConfig{MaxPDFTextBytes: 256}).Run(Request{Payload: payload}), limitPDFTextBytes)
+}
@@ -0,0 +1,181 @@
+package validator
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qnhqn1/file-validator/internal/services/validator/pdf"
+)
+
+const (
+	corePropsPart = "docProps/core.xml"
+	appPropsPart  = "docProps/app.xml"
+	odfMetaPart   = "meta.xml"
+)
+
+// Metadata — свойства документа: docProps/core.xml и docProps/app.xml в OOXML,
+// meta.xml в ODT, словарь /Info в PDF. Незаполненные поля в ответ не попадают.
+type Metadata struct {
+	Title          string     `json:"title,omitempty"`
+	Author         string     `json:"author,omitempty"`
+	LastModifiedBy string     `json:"last_modified_by,omitempty"`
+	Created        *time.Time `json:"created,omitempty"`
+	Modified       *time.Time `json:"modified,omitempty"`
+	Pages          int        `json:"pages,omitempty"`
+	Words          int        `json:"words,omitempty"`
+	Characters     int        `json:"characters,omitempty"`
+	Application    string     `json:"application,omitempty"`
+	Company        string     `json:"company,omitempty"`
+}
+
+func (m Metadata) IsZero() bool {
+	return m == Metadata{}
+}
+
+// w3cdtfLayouts — варианты W3CDTF в dcterms:created и ODF-даты без часового пояса.
+var w3cdtfLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+func parsePropertyTime(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range w3cdtfLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+func parsePropertyInt(value string) int {
+	n, _ := strconv.Atoi(strings.TrimSpace(value))
+	return n
+}
+
+// readProperties собирает текст листовых элементов части по локальному имени
+// и атрибуты как «элемент@атрибут». Повторы не перезаписывают первое значение.
+func readProperties(reader fs.FS, name string) (map[string]string, error) {
+	f, err := reader.Open(name)
+	if err != nil {
+		return nil, errPartMissing
+	}
+	defer f.Close()
+
+	props := make(map[string]string)
+	set := func(key, value string) {
+		if _, ok := props[key]; !ok && value != "" {
+			props[key] = value
+		}
+	}
+	var text strings.Builder
+	dec := newXMLDecoder(f)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return props, nil
+		}
+		if err != nil {
+			return nil, describeXMLError(path.Base(name), err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			text.Reset()
+			for _, attr := range t.Attr {
+				set(t.Name.Local+"@"+attr.Name.Local, strings.TrimSpace(attr.Value))
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			set(t.Name.Local, strings.TrimSpace(text.String()))
+			text.Reset()
+		}
+	}
+}
+
+// readOOXMLMetadata находит части свойств по связям пакета, иначе берёт стандартные имена.
+func (d *Document) readOOXMLMetadata(reader fs.FS) {
+	core, app := corePropsPart, appPropsPart
+	if rels, err := readRelationships(reader, packageRelsPart); err == nil {
+		for _, rel := range rels {
+			switch {
+			case rel.External():
+			case rel.Kind() == "core-properties":
+				core = relationshipEntry("", rel.Target)
+			case rel.Kind() == "extended-properties":
+				app = relationshipEntry("", rel.Target)
+			}
+		}
+	}
+
+	if props, ok := d.readPropertiesPart(reader, core); ok {
+		d.Metadata.Title = props["title"]
+		d.Metadata.Author = props["creator"]
+		d.Metadata.LastModifiedBy = props["lastModifiedBy"]
+		d.Metadata.Created = parsePropertyTime(props["created"])
+		d.Metadata.Modified = parsePropertyTime(props["modified"])
+	}
+	if props, ok := d.readPropertiesPart(reader, app); ok {
+		d.Metadata.Pages = parsePropertyInt(props["Pages"])
+		d.Metadata.Words = parsePropertyInt(props["Words"])
+		d.Metadata.Characters = parsePropertyInt(props["Characters"])
+		d.Metadata.Application = props["Application"]
+		d.Metadata.Company = props["Company"]
+	}
+}
+
+// readODFMetadata читает meta.xml: initial-creator — автор, dc:creator — последний редактор.
+func (d *Document) readODFMetadata(reader fs.FS) {
+	props, ok := d.readPropertiesPart(reader, odfMetaPart)
+	if !ok {
+		return
+	}
+	d.Metadata = Metadata{
+		Title:          props["title"],
+		Author:         props["initial-creator"],
+		LastModifiedBy: props["creator"],
+		Created:        parsePropertyTime(props["creation-date"]),
+		Modified:       parsePropertyTime(props["date"]),
+		Pages:          parsePropertyInt(props["document-statistic@page-count"]),
+		Words:          parsePropertyInt(props["document-statistic@word-count"]),
+		Characters:     parsePropertyInt(props["document-statistic@character-count"]),
+		Application:    props["generator"],
+	}
+}
+
+// readPropertiesPart читает часть свойств; отсутствие части не ошибка, а
+// повреждённая часть попадает в PartErrors, как и другие вспомогательные части.
+func (d *Document) readPropertiesPart(reader fs.FS, name string) (map[string]string, bool) {
+	props, err := readProperties(reader, name)
+	if errors.Is(err, errPartMissing) {
+		return nil, false
+	}
+	if err != nil {
+		d.addPartError(name, err)
+		return nil, false
+	}
+	return props, true
+}
+
+func pdfMetadata(info pdf.DocInfo, pages int) Metadata {
+	m := Metadata{Title: info.Title, Author: info.Author, Pages: pages, Application: info.Creator}
+	if m.Application == "" {
+		m.Application = info.Producer
+	}
+	if !info.CreationDate.IsZero() {
+		m.Created = &info.CreationDate
+	}
+	if !info.ModDate.IsZero() {
+		m.Modified = &info.ModDate
+	}
+	return m
+}
@@ -0,0 +1,93 @@
+package validator
+
+import (
+	"archive/zip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testCoreProps = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+<dc:title>Договор поставки</dc:title><dc:creator>Иванов И.И.</dc:creator><cp:lastModifiedBy>Петров П.П.</cp:lastModifiedBy>
+<dcterms:created xsi:type="dcterms:W3CDTF">2024-02-01T09:30:00Z</dcterms:created><dcterms:modified xsi:type="dcterms:W3CDTF">2024-02-03T12:00:00Z</dcterms:modified>
+</cp:coreProperties>`
+	testAppProps = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties"><Application>Microsoft Office Word</Application><Pages>3</Pages><Words>512</Words><Characters>2920</Characters></Properties>`
+)
+
+func createDOCXWithMetadata() []byte {
+	return buildZip(map[string]string{
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels": `<Relationships ` + relsNS + `>` +
+			`<Relationship Id="rId1" Type="` + relType + `officeDocument" Target="word/document.xml"/>` +
+			`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="props/core.xml"/>` +
+			`<Relationship Id="rId3" Type="` + relType + `extended-properties" Target="docProps/app.xml"/></Relationships>`,
+		"word/document.xml": wordPart("document", `<w:body><w:p><w:r><w:t>Договор поставки от 01.02.2024</w:t></w:r></w:p></w:body>`),
+		"props/core.xml":    testCoreProps,
+		"docProps/app.xml":  testAppProps,
+	})
+}
+
+func TestMetadata_OOXML(t *testing.T) {
+	doc, err := parseDocument(createDOCXWithMetadata(), testBudget())
+	require.NoError(t, err)
+
+	m := doc.Metadata
+	require.Equal(t, "Договор поставки", m.Title)
+	require.Equal(t, "Иванов И.И.", m.Author)
+	require.Equal(t, "Петров П.П.", m.LastModifiedBy)
+	require.Equal(t, time.Date(2024, 2, 1, 9, 30, 0, 0, time.UTC), *m.Created)
+	require.Equal(t, time.Date(2024, 2, 3, 12, 0, 0, 0, time.UTC), *m.Modified)
+	require.Equal(t, 3, m.Pages)
+	require.Equal(t, 512, m.Words)
+	require.Equal(t, 2920, m.Characters)
+	require.Equal(t, "Microsoft Office Word", m.Application)
+}
+
+func TestMetadata_BrokenPartIsReported(t *testing.T) {
+	doc, err := parseDocument(buildZip(map[string]string{
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         testPackageRels,
+		"word/document.xml":   wordPart("document", `<w:body/>`),
+		"docProps/core.xml":   `<cp:coreProperties><dc:title>`,
+	}), testBudget())
+	require.NoError(t, err)
+	require.True(t, doc.Metadata.IsZero())
+	require.Contains(t, doc.PartErrors, corePropsPart)
+}
+
+func TestMetadata_ODT(t *testing.T) {
+	entries := append(odtEntries(`<text:p>Договор от 01.02.2024</text:p>`), odtEntry{
+		name: "meta.xml",
+		content: `<?xml version="1.0"?><office:document-meta xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:meta="urn:oasis:names:tc:opendocument:xmlns:meta:1.0" xmlns:dc="http://purl.org/dc/elements/1.1/"><office:meta>` +
+			`<meta:generator>LibreOffice/7.6</meta:generator><dc:title>Акт</dc:title><meta:initial-creator>Иванов</meta:initial-creator><dc:creator>Петров</dc:creator>` +
+			`<meta:creation-date>2024-02-01T10:00:00.123</meta:creation-date><dc:date>2024-02-02T11:00:00</dc:date>` +
+			`<meta:document-statistic meta:page-count="2" meta:word-count="100" meta:character-count="640"/></office:meta></office:document-meta>`,
+		method: zip.Deflate,
+	})
+	doc, res := parseODT(buildODT(entries...), testBudget())
+	require.Equal(t, StatusPassed, res.Status)
+
+	m := doc.Metadata
+	require.Equal(t, "Акт", m.Title)
+	require.Equal(t, "Иванов", m.Author)
+	require.Equal(t, "Петров", m.LastModifiedBy)
+	require.Equal(t, 2024, m.Created.Year())
+	require.Equal(t, 2, m.Modified.Day())
+	require.Equal(t, 2, m.Pages)
+	require.Equal(t, 100, m.Words)
+	require.Equal(t, 640, m.Characters)
+	require.Equal(t, "LibreOffice/7.6", m.Application)
+}
+
+func TestEngine_ReportCarriesMetadata(t *testing.T) {
+	report := zipRulesEngine(t).Run(Request{Payload: createDOCXWithMetadata()})
+	require.NotNil(t, report.Metadata)
+	require.Equal(t, "Договор поставки", report.Metadata.Title)
+
+	report = zipRulesEngine(t).Run(Request{Payload: createDOCXWithText("Текст")})
+	require.Nil(t, report.Metadata)
+}
@@ -56,16 +56,16 @@ func (_c *MockStorageInterface_Close_Call) RunAndReturn(run func()) *MockStorage
 }
 
 
-func (_m *MockStorageInterface) InsertEvent(ctx context.Context, key string, payload []byte) error {
-	ret := _m.Called(ctx, key, payload)
+func (_m *MockStorageInterface) InsertEvent(ctx context.Context, key string, payload []byte, metadata []byte) error {
+	ret := _m.Called(ctx, key, payload, metadata)
 
 	if len(ret) == 0 {
 		panic("no return value specified for InsertEvent")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, []byte) error); ok {
-		r0 = rf(ctx, key, payload)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte, []byte) error); ok {
+		r0 = rf(ctx, key, payload, metadata)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -82,13 +82,13 @@ type MockStorageInterface_InsertEvent_Call struct {
 
 
 
-func (_e *MockStorageInterface_Expecter) InsertEvent(ctx interface{}, key interface{}, payload interface{}) *MockStorageInterface_InsertEvent_Call {
-	return &MockStorageInterface_InsertEvent_Call{Call: _e.mock.On("InsertEvent", ctx, key, payload)}
+func (_e *MockStorageInterface_Expecter) InsertEvent(ctx interface{}, key interface{}, payload interface{}, metadata interface{}) *MockStorageInterface_InsertEvent_Call {
+	return &MockStorageInterface_InsertEvent_Call{Call: _e.mock.On("InsertEvent", ctx, key, payload, metadata)}
 }
 
-func (_c *MockStorageInterface_InsertEvent_Call) Run(run func(ctx context.Context, key string, payload []byte)) *MockStorageInterface_InsertEvent_Call {
+func (_c *MockStorageInterface_InsertEvent_Call) Run(run func(ctx context.Context, key string, payload []byte, metadata []byte)) *MockStorageInterface_InsertEvent_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].([]byte))
+		run(args[0].(context.Context), args[1].(string), args[2].([]byte), args[3].([]byte))
 	})
 	return _c
 }
@@ -98,7 +98,7 @@ func (_c *MockStorageInterface_InsertEvent_Call) Return(_a0 error) *MockStorageI
 	return _c
 }
 
-func (_c *MockStorageInterface_InsertEvent_Call) RunAndReturn(run func(context.Context, string, []byte) error) *MockStorageInterface_InsertEvent_Call {
+func (_c *MockStorageInterface_InsertEvent_Call) RunAndReturn(run func(context.Context, string, []byte, []byte) error) *MockStorageInterface_InsertEvent_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,230 @@
+package validator
+
+import (
+	"encoding/xml"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	nsODFOffice  = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	nsODFText    = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+	nsODFTable   = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	nsODFStyle   = "urn:oasis:names:tc:opendocument:xmlns:style:1.0"
+	nsODFSVG     = "urn:oasis:names:tc:opendocument:xmlns:svg-compatible:1.0"
+	nsDublinCore = "http://purl.org/dc/elements/1.1/"
+)
+
+// odfParser разбирает content.xml и styles.xml. Источник абзаца определяется
+// объемлющим элементом: сноски, примечания и колонтитулы лежат внутри
+// обычных абзацев или стилей страницы, поэтому источники ведутся стеком.
+type odfParser struct {
+	wordParser
+	sources []string
+}
+
+// parseODFText потоково разбирает часть OpenDocument. Текст удалённых
+// правок (text:tracked-changes), номера сносок и авторы примечаний пропускаются.
+func parseODFText(r io.Reader) (*wordBody, error) {
+	p := &odfParser{}
+	dec := newXMLDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if p.body.Root.Local == "" {
+				p.body.Root = t.Name
+			}
+			if p.skipped(t.Name) {
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			p.start(t)
+		case xml.EndElement:
+			p.end(t.Name)
+		case xml.CharData:
+			if p.inText {
+				p.write(collapseODFSpace(string(t)))
+			}
+		}
+	}
+	if p.body.Root.Local == "" {
+		return nil, errNoRootElement
+	}
+	return &p.body, nil
+}
+
+func (p *odfParser) skipped(name xml.Name) bool {
+	switch name.Space {
+	case nsDublinCore, nsODFSVG:
+		return true
+	case nsODFText:
+		return name.Local == "tracked-changes" || name.Local == "note-citation"
+	}
+	return false
+}
+
+func (p *odfParser) source() string {
+	if len(p.sources) == 0 {
+		return ""
+	}
+	return p.sources[len(p.sources)-1]
+}
+
+func (p *odfParser) start(t xml.StartElement) {
+	switch t.Name.Space {
+	case nsODFText:
+		switch t.Name.Local {
+		case "p", "h":
+			p.paras = append(p.paras, &paragraphState{})
+			p.inText = true
+		case "span", "a":
+			if p.run == nil {
+				p.run = &strings.Builder{}
+			}
+		case "s":
+			count := 1
+			for _, attr := range t.Attr {
+				if attr.Name.Local == "c" {
+					if n, err := strconv.Atoi(attr.Value); err == nil && n > 0 {
+						count = n
+					}
+				}
+			}
+			p.write(strings.Repeat(" ", count))
+		case "tab":
+			p.write("\t")
+		case "line-break":
+			p.write("\n")
+		case "note":
+			source := SourceFootnotes
+			for _, attr := range t.Attr {
+				if attr.Name.Local == "note-class" && attr.Value == "endnote" {
+					source = SourceEndnotes
+				}
+			}
+			p.sources = append(p.sources, source)
+		}
+	case nsODFOffice:
+		if t.Name.Local == "annotation" {
+			p.sources = append(p.sources, SourceComments)
+		}
+	case nsODFStyle:
+		switch t.Name.Local {
+		case "header", "header-left", "header-first":
+			p.sources = append(p.sources, SourceHeader)
+		case "footer", "footer-left", "footer-first":
+			p.sources = append(p.sources, SourceFooter)
+		}
+	case nsODFTable:
+		switch t.Name.Local {
+		case "table":
+			p.wordParser.start("tbl")
+		case "table-row":
+			p.wordParser.start("tr")
+		case "table-cell", "covered-table-cell":
+			p.wordParser.start("tc")
+		}
+	}
+}
+
+func (p *odfParser) end(name xml.Name) {
+	switch name.Space {
+	case nsODFText:
+		switch name.Local {
+		case "p", "h":
+			if len(p.paras) == 0 {
+				return
+			}
+			p.closeParagraph()
+			p.body.Paragraphs[len(p.body.Paragraphs)-1].Source = p.source()
+			p.inText = len(p.paras) > 0
+		case "span", "a":
+			if p.run != nil && len(p.paras) > 0 {
+				para := p.paras[len(p.paras)-1]
+				para.runs = append(para.runs, Run{Text: p.run.String()})
+			}
+			p.run = nil
+		case "note":
+			p.popSource()
+		}
+	case nsODFOffice:
+		if name.Local == "annotation" {
+			p.popSource()
+		}
+	case nsODFStyle:
+		switch name.Local {
+		case "header", "header-left", "header-first", "footer", "footer-left", "footer-first":
+			p.popSource()
+		}
+	case nsODFTable:
+		if name.Local == "table" {
+			p.wordParser.end("tbl")
+		}
+	}
+}
+
+func (p *odfParser) popSource() {
+	if len(p.sources) > 0 {
+		p.sources = p.sources[:len(p.sources)-1]
+	}
+}
+
+// readODFPart разбирает часть пакета; абзацы без собственного источника
+// получают defaultSource, пустой defaultSource их отбрасывает.
+func readODFPart(reader fs.FS, name, defaultSource string) (*wordBody, error) {
+	f, err := reader.Open(name)
+	if err != nil {
+		return nil, errPartMissing
+	}
+	defer f.Close()
+
+	body, err := parseODFText(f)
+	if err != nil {
+		return nil, describeXMLError(path.Base(name), err)
+	}
+	kept := body.Paragraphs[:0]
+	for _, para := range body.Paragraphs {
+		if para.Source == "" {
+			para.Source = defaultSource
+		}
+		if para.Source == "" {
+			continue
+		}
+		para.Part = name
+		kept = append(kept, para)
+	}
+	body.Paragraphs = kept
+	return body, nil
+}
+
+// collapseODFSpace сворачивает пробельные символы так, как это делают
+// редакторы OpenDocument: явные пробелы и табуляции задаются элементами text:s и text:tab.
+func collapseODFSpace(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
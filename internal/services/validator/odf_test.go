@@ -0,0 +1,128 @@
+package validator
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+const odfContentHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+ xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+ xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+ xmlns:dc="http://purl.org/dc/elements/1.1/"><office:body><office:text>`
+
+const odfContentFooter = `</office:text></office:body></office:document-content>`
+
+type odtEntry struct {
+	name    string
+	content string
+	method  uint16
+}
+
+func buildODT(entries ...odtEntry) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		w, _ := zw.CreateHeader(&zip.FileHeader{Name: e.name, Method: e.method})
+		w.Write([]byte(e.content))
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+func odtEntries(body string) []odtEntry {
+	return []odtEntry{
+		{name: "mimetype", content: odtMimetype, method: zip.Store},
+		{name: "META-INF/manifest.xml", content: `<?xml version="1.0"?><manifest:manifest xmlns:manifest="urn:oasis:names:tc:opendocument:xmlns:manifest:1.0"/>`, method: zip.Deflate},
+		{name: "content.xml", content: odfContentHeader + body + odfContentFooter, method: zip.Deflate},
+		{name: "styles.xml", content: `<?xml version="1.0"?><office:document-styles xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"><office:master-styles><style:master-page><style:header><text:p>ООО «Ромашка»</text:p></style:header></style:master-page></office:master-styles></office:document-styles>`, method: zip.Deflate},
+	}
+}
+
+func createODTWithText(text string) []byte {
+	return buildODT(odtEntries(`<text:p>` + text + `</text:p>`)...)
+}
+
+func TestParseODFText(t *testing.T) {
+	body, err := parseODFText(strings.NewReader(odfContentHeader +
+		`<text:h>Договор</text:h>
+		<text:p>Сторона<text:s text:c="2"/><text:span>поставщик</text:span><text:tab/>А<text:line-break/>Б` +
+		`<text:note text:note-class="footnote"><text:note-citation>1</text:note-citation><text:note-body><text:p>Сноска</text:p></text:note-body></text:note>` +
+		`<office:annotation><dc:creator>Иванов</dc:creator><text:p>Проверить</text:p></office:annotation></text:p>` +
+		`<text:tracked-changes><text:changed-region><text:deletion><text:p>Удалено</text:p></text:deletion></text:changed-region></text:tracked-changes>` +
+		`<table:table><table:table-row><table:table-cell><text:p>Ячейка</text:p></table:table-cell></table:table-row></table:table>` +
+		odfContentFooter))
+	require.NoError(t, err)
+
+	var texts, sources []string
+	for _, para := range body.Paragraphs {
+		texts = append(texts, para.Text)
+		sources = append(sources, para.Source)
+	}
+	require.Equal(t, []string{"Договор", "Сноска", "Проверить", "Сторона  поставщик\tА\nБ", "Ячейка"}, texts)
+	require.Equal(t, []string{"", SourceFootnotes, SourceComments, "", ""}, sources)
+	require.Equal(t, []Table{{Rows: [][]string{{"Ячейка"}}}}, body.Tables)
+	require.Equal(t, 0, body.Paragraphs[4].Table)
+}
+
+func TestEngine_ODT(t *testing.T) {
+	engine, err := NewEngine(config.ValidationConfig{})
+	require.NoError(t, err)
+
+	payload := createODTWithText("Договор поставки от 01.02.2024 до 01.03.2024")
+	require.Equal(t, FormatODT, detectFormat(payload, testBudget()))
+
+	report := engine.Run(Request{Payload: payload})
+	require.True(t, report.Valid(), report.Failures())
+	require.Equal(t, FormatODT, report.Format)
+
+	res, _ := report.Result(ruleArchive)
+	require.Equal(t, odtMimetype, res.Details["mimetype"])
+
+	// Колонтитул из styles.xml учитывается в тексте.
+	res, _ = report.Result("cyrillic_ratio")
+	require.Equal(t, StatusPassed, res.Status)
+
+	engine, err = profileEngine(config.ProfileConfig{Rules: []string{"cyrillic_ratio"}, Cyrillic: config.CyrillicRuleConfig{Parts: []string{SourceHeader}}})
+	require.NoError(t, err)
+	require.True(t, engine.Run(Request{Payload: createODTWithText("English only")}).Valid())
+}
+
+func TestEngine_ODTPackageLayout(t *testing.T) {
+	engine, err := NewEngine(config.ValidationConfig{})
+	require.NoError(t, err)
+
+	entries := odtEntries(`<text:p>Договор от 01.02.2024</text:p>`)
+
+	compressed := append([]odtEntry{}, entries...)
+	compressed[0].method = zip.Deflate
+	report := engine.Run(Request{Payload: buildODT(compressed...)})
+	require.Equal(t, CodeInvalidPackage, report.FirstFailureCode())
+	require.Contains(t, (&ValidationError{Report: report}).Error(), "Валидация ODT не удалась")
+
+	notFirst := append([]odtEntry{entries[1]}, entries[0], entries[2])
+	report = engine.Run(Request{Payload: buildODT(notFirst...)})
+	require.Equal(t, CodeInvalidPackage, report.FirstFailureCode())
+
+	spreadsheet := append([]odtEntry{}, entries...)
+	spreadsheet[0].content = "application/vnd.oasis.opendocument.spreadsheet"
+	report = engine.Run(Request{Payload: buildODT(spreadsheet...)})
+	require.Equal(t, CodeInvalidPackage, report.FirstFailureCode())
+
+	report = engine.Run(Request{Payload: buildODT(entries[0], entries[2])})
+	res, _ := report.Result(ruleRequiredParts)
+	require.Equal(t, CodeMissingPart, res.Code)
+	require.Equal(t, []string{odfManifestPart}, res.Details["missing"])
+
+	broken := append([]odtEntry{}, entries...)
+	broken[2].content = odfContentHeader + `<text:p>`
+	report = engine.Run(Request{Payload: buildODT(broken...)})
+	res, _ = report.Result(ruleMainPartXML)
+	require.Equal(t, CodeInvalidXML, res.Code)
+}
@@ -0,0 +1,176 @@
+package validator
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	packageRelsPart  = "_rels/.rels"
+	contentTypesPart = "[Content_Types].xml"
+)
+
+type Relationship struct {
+	ID         string `xml:"Id,attr"`
+	Type       string `xml:"Type,attr"`
+	Target     string `xml:"Target,attr"`
+	TargetMode string `xml:"TargetMode,attr"`
+}
+
+func (r Relationship) External() bool {
+	return strings.EqualFold(r.TargetMode, "External")
+}
+
+// Kind — последний сегмент URI типа связи: "header", "footer", "officeDocument" и т.д.
+// Одинаков для переходной и строгой (purl.oclc.org) схем.
+func (r Relationship) Kind() string {
+	return r.Type[strings.LastIndex(r.Type, "/")+1:]
+}
+
+// contentTypes — содержимое [Content_Types].xml: тип части берётся из Override
+// по её имени, иначе из Default по расширению.
+type contentTypes struct {
+	XMLName   xml.Name              `xml:"Types"`
+	Defaults  []contentTypeDefault  `xml:"Default"`
+	Overrides []contentTypeOverride `xml:"Override"`
+}
+
+type contentTypeDefault struct {
+	Extension   string `xml:"Extension,attr"`
+	ContentType string `xml:"ContentType,attr"`
+}
+
+type contentTypeOverride struct {
+	PartName    string `xml:"PartName,attr"`
+	ContentType string `xml:"ContentType,attr"`
+}
+
+func readContentTypes(reader fs.FS) (*contentTypes, error) {
+	f, err := reader.Open(contentTypesPart)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var types contentTypes
+	if err := newXMLDecoder(f).Decode(&types); err != nil {
+		return nil, fmt.Errorf("разобрать %s: %w", contentTypesPart, err)
+	}
+	return &types, nil
+}
+
+// lookup возвращает тип записи ZIP. Имена частей и расширения в OPC сравниваются без учёта регистра.
+func (t *contentTypes) lookup(name string) (string, bool) {
+	for _, o := range t.Overrides {
+		if strings.EqualFold(strings.TrimPrefix(o.PartName, "/"), name) {
+			return o.ContentType, true
+		}
+	}
+	ext := strings.TrimPrefix(path.Ext(name), ".")
+	for _, d := range t.Defaults {
+		if ext != "" && strings.EqualFold(d.Extension, ext) {
+			return d.ContentType, true
+		}
+	}
+	return "", false
+}
+
+type relationships struct {
+	Items []Relationship `xml:"Relationship"`
+}
+
+// relsPathFor возвращает путь к файлу связей части: word/document.xml → word/_rels/document.xml.rels.
+func relsPathFor(part string) string {
+	dir, file := path.Split(part)
+	return dir + "_rels/" + file + ".rels"
+}
+
+// resolveTarget переводит Target связи в имя записи ZIP относительно части-источника.
+func resolveTarget(sourcePart, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(path.Clean(target), "/")
+	}
+	return strings.TrimPrefix(path.Join(path.Dir(sourcePart), target), "/")
+}
+
+// relsSource возвращает часть, которой принадлежит файл связей:
+// word/_rels/document.xml.rels → word/document.xml, _rels/.rels → "" (сам пакет).
+func relsSource(name string) (string, bool) {
+	dir, file := path.Split(name)
+	if path.Base(dir) != "_rels" || !strings.HasSuffix(file, ".rels") {
+		return "", false
+	}
+	return strings.TrimSuffix(dir, "_rels/") + strings.TrimSuffix(file, ".rels"), true
+}
+
+// relationshipEntry переводит Target в имя записи: фрагмент отбрасывается,
+// экранирование URI снимается.
+func relationshipEntry(source, target string) string {
+	if i := strings.IndexByte(target, '#'); i >= 0 {
+		target = target[:i]
+	}
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+	return resolveTarget(source, target)
+}
+
+func readRelationships(reader fs.FS, name string) ([]Relationship, error) {
+	f, err := reader.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rels relationships
+	if err := newXMLDecoder(f).Decode(&rels); err != nil {
+		return nil, fmt.Errorf("разобрать %s: %w", name, err)
+	}
+	return rels.Items, nil
+}
+
+// officeDocumentPart возвращает основную часть пакета по связи officeDocument
+// из _rels/.rels или пустую строку, если связи нет.
+func officeDocumentPart(reader fs.FS) string {
+	rels, err := readRelationships(reader, packageRelsPart)
+	if err != nil {
+		return ""
+	}
+	for _, rel := range rels {
+		if !rel.External() && rel.Kind() == "officeDocument" {
+			return relationshipEntry("", rel.Target)
+		}
+	}
+	return ""
+}
+
+// relationshipID возвращает атрибут r:id элемента; пространство имён
+// отличается в переходной и строгой схемах, поэтому сравнивается только суффикс.
+func relationshipID(attrs []xml.Attr) string {
+	for _, attr := range attrs {
+		if attr.Name.Local == "id" && strings.HasSuffix(attr.Name.Space, "relationships") {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// relationshipTargets строит отображение Id → имя записи для связей части.
+func relationshipTargets(reader fs.FS, part string) map[string]Relationship {
+	rels, err := readRelationships(reader, relsPathFor(part))
+	if err != nil {
+		return nil
+	}
+	out := make(map[string]Relationship, len(rels))
+	for _, rel := range rels {
+		if !rel.External() {
+			rel.Target = relationshipEntry(part, rel.Target)
+		}
+		out[rel.ID] = rel
+	}
+	return out
+}
@@ -0,0 +1,160 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+const (
+	xmlnsSheet = `xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+	relsNS     = `xmlns="http://schemas.openxmlformats.org/package/2006/relationships"`
+	relType    = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/"
+)
+
+func packageRels(target string) string {
+	return `<Relationships ` + relsNS + `><Relationship Id="rId1" Type="` + relType + `officeDocument" Target="` + target + `"/></Relationships>`
+}
+
+func createXLSX(sheet string) []byte {
+	return buildZip(map[string]string{
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         packageRels("xl/workbook.xml"),
+		"xl/workbook.xml":     `<workbook ` + xmlnsSheet + `><sheets><sheet name="Прайс" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships ` + relsNS + `>` +
+			`<Relationship Id="rId1" Type="` + relType + `worksheet" Target="worksheets/sheet1.xml"/>` +
+			`<Relationship Id="rId2" Type="` + relType + `sharedStrings" Target="sharedStrings.xml"/>` +
+			`<Relationship Id="rId3" Type="` + relType + `styles" Target="styles.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst ` + xmlnsSheet + `><si><t>Наименование</t></si><si><r><t>Цена </t></r><r><t>за единицу</t></r><rPh><t>ignored</t></rPh></si></sst>`,
+		"xl/styles.xml": `<styleSheet ` + xmlnsSheet + `><numFmts><numFmt numFmtId="164" formatCode="dd/mm/yyyy;@"/><numFmt numFmtId="165" formatCode="&quot;d&quot;0.00"/></numFmts>` +
+			`<cellStyleXfs><xf numFmtId="14"/></cellStyleXfs><cellXfs><xf numFmtId="0"/><xf numFmtId="14"/><xf numFmtId="164"/><xf numFmtId="165"/></cellXfs></styleSheet>`,
+		"xl/worksheets/sheet1.xml": `<worksheet ` + xmlnsSheet + `><sheetData>` + sheet + `</sheetData></worksheet>`,
+	})
+}
+
+func createPPTX(slides ...string) []byte {
+	files := map[string]string{
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         packageRels("ppt/presentation.xml"),
+	}
+	var ids, rels strings.Builder
+	// Первый слайд показа хранится в slideN.xml с наибольшим номером:
+	// порядок должен браться из sldIdLst, а не из имён записей.
+	for i := range slides {
+		n := len(slides) - i
+		id := "rId" + string(rune('0'+n))
+		ids.WriteString(`<p:sldId id="` + string(rune('0'+n)) + `" r:id="` + id + `"/>`)
+		rels.WriteString(`<Relationship Id="` + id + `" Type="` + relType + `slide" Target="slides/slide` + string(rune('0'+n)) + `.xml"/>`)
+		files["ppt/slides/slide"+string(rune('0'+n))+".xml"] = `<p:sld xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"><p:cSld><p:spTree><p:sp><p:txBody><a:p><a:pPr><a:tabLst><a:tab pos="0"/></a:tabLst></a:pPr>` + slides[i] + `</a:p></p:txBody></p:sp></p:spTree></p:cSld></p:sld>`
+	}
+	files["ppt/presentation.xml"] = `<p:presentation xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><p:sldIdLst>` + ids.String() + `</p:sldIdLst></p:presentation>`
+	files["ppt/_rels/presentation.xml.rels"] = `<Relationships ` + relsNS + `>` + rels.String() + `</Relationships>`
+	files["ppt/slides/_rels/slide1.xml.rels"] = `<Relationships ` + relsNS + `><Relationship Id="rId1" Type="` + relType + `notesSlide" Target="../notesSlides/notesSlide1.xml"/></Relationships>`
+	files["ppt/notesSlides/notesSlide1.xml"] = `<p:notes xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"><a:p><a:r><a:t>Заметка докладчика</a:t></a:r></a:p></p:notes>`
+	return buildZip(files)
+}
+
+func TestDetectFormat_OOXML(t *testing.T) {
+	require.Equal(t, FormatXLSX, detectFormat(createXLSX(""), testBudget()))
+	require.Equal(t, FormatPPTX, detectFormat(createPPTX("<a:r><a:t>x</a:t></a:r>"), testBudget()))
+	require.Equal(t, FormatDOCX, detectFormat(createDOCXWithText("Договор"), testBudget()))
+}
+
+func TestParseXLSX_CellsAndDates(t *testing.T) {
+	payload := createXLSX(
+		`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>` +
+			`<row r="2"><c r="A2" t="inlineStr"><is><t>Поставка</t></is></c><c r="B2"><f>1+1</f><v>1250.5</v></c></row>` +
+			`<row r="3"><c r="A3" s="1"><v>45323</v></c><c r="B3" s="2"><v>45352.5</v></c><c r="C3" s="3"><v>12</v></c><c r="D3" t="d"><v>2024-03-01T00:00:00</v></c></row>`)
+
+	doc, res := parseXLSX(payload, testBudget())
+	require.Equal(t, StatusPassed, res.Status)
+	require.Equal(t, 1, res.Details["sheets"])
+	require.Empty(t, doc.PartErrors)
+
+	require.Equal(t, []Table{{Rows: [][]string{
+		{"Наименование", "Цена за единицу"},
+		{"Поставка", "1250.5"},
+		{"01.02.2024", "01.03.2024", "12", "01.03.2024"},
+	}}}, doc.Tables)
+	require.Equal(t, "xl/worksheets/sheet1.xml", doc.Paragraphs[0].Part)
+	require.Equal(t, 2, doc.Paragraphs[4].Row)
+}
+
+func TestExcelSerialDate(t *testing.T) {
+	date, ok := excelSerialDate(1, false)
+	require.True(t, ok)
+	require.Equal(t, time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC), date)
+
+	date, _ = excelSerialDate(61, false)
+	require.Equal(t, time.Date(1900, 3, 1, 0, 0, 0, 0, time.UTC), date)
+
+	date, _ = excelSerialDate(0+1, true)
+	require.Equal(t, time.Date(1904, 1, 2, 0, 0, 0, 0, time.UTC), date)
+
+	_, ok = excelSerialDate(-5, false)
+	require.False(t, ok)
+}
+
+func TestIsDateFormatCode(t *testing.T) {
+	require.True(t, isDateFormatCode("dd/mm/yyyy;@"))
+	require.True(t, isDateFormatCode(`[$-419]d mmmm yyyy "г."`))
+	require.False(t, isDateFormatCode("hh:mm:ss"))
+	require.False(t, isDateFormatCode(`"d"0.00`))
+	require.False(t, isDateFormatCode(`[Red]0.00`))
+}
+
+func TestEngine_XLSX(t *testing.T) {
+	engine, err := NewEngine(config.ValidationConfig{})
+	require.NoError(t, err)
+
+	report := engine.Run(Request{Payload: createXLSX(
+		`<row><c t="s"><v>0</v></c><c t="s"><v>1</v></c></row>` +
+			`<row><c t="inlineStr"><is><t>Действует с</t></is></c><c s="1"><v>45323</v></c></row>`)})
+	require.True(t, report.Valid(), report.Failures())
+	require.Equal(t, FormatXLSX, report.Format)
+
+	res, _ := report.Result("date_span")
+	require.Equal(t, "2024-02-01", res.Details["min_date"])
+
+	broken := buildZip(map[string]string{
+		"[Content_Types].xml": `<Types/>`,
+		"_rels/.rels":         packageRels("xl/workbook.xml"),
+	})
+	report = engine.Run(Request{Payload: broken})
+	res, _ = report.Result(ruleRequiredParts)
+	require.Equal(t, []string{workbookPartName}, res.Details["missing"])
+}
+
+func TestParsePPTX_SlideOrderAndNotes(t *testing.T) {
+	doc, res := parsePPTX(createPPTX(
+		`<a:r><a:t>Коммерческое</a:t></a:r><a:br/><a:fld type="slidenum"><a:t>1</a:t></a:fld>`,
+		`<a:r><a:t>предложение</a:t></a:r>`,
+	), testBudget())
+	require.Equal(t, StatusPassed, res.Status)
+	require.Equal(t, 2, res.Details["slides"])
+
+	var texts, sources []string
+	for _, para := range doc.Paragraphs {
+		texts = append(texts, para.Text)
+		sources = append(sources, para.Source)
+	}
+	require.Equal(t, []string{"Коммерческое\n1", "предложение", "Заметка докладчика"}, texts)
+	require.Equal(t, []string{SourceBody, SourceBody, SourceNotes}, sources)
+	require.Equal(t, "ppt/slides/slide2.xml", doc.Paragraphs[0].Part)
+}
+
+func TestEngine_PPTX(t *testing.T) {
+	engine, err := profileEngine(config.ProfileConfig{
+		Rules:    []string{"required_parts", "main_part_xml", "cyrillic_ratio"},
+		Cyrillic: config.CyrillicRuleConfig{Parts: []string{SourceNotes}},
+	})
+	require.NoError(t, err)
+
+	report := engine.Run(Request{Payload: createPPTX(`<a:r><a:t>English slide</a:t></a:r>`)})
+	require.True(t, report.Valid(), report.Failures())
+	require.Equal(t, FormatPPTX, report.Format)
+}
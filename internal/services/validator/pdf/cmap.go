@@ -0,0 +1,187 @@
+package pdf
+
+import (
+	"unicode/utf16"
+)
+
+// cmap — таблица ToUnicode: коды символов переменной длины в строки Unicode.
+type cmap struct {
+	codespaces  []cmapRange
+	codeLengths map[int]bool
+	single      map[string]string
+	ranges      []cmapRange
+}
+
+type cmapRange struct {
+	lo, hi []byte
+	dst    []byte
+	array  []string
+}
+
+func parseCMap(data []byte) *cmap {
+	cm := &cmap{codeLengths: map[int]bool{}, single: map[string]string{}}
+	lx := newLexer(data, 0)
+	for {
+		tok, err := lx.next()
+		if err != nil || tok.kind == tokEOF {
+			break
+		}
+		if tok.kind != tokKeyword {
+			continue
+		}
+		switch tok.text {
+		case "begincodespacerange":
+			for {
+				lo, ok := nextString(lx)
+				if !ok {
+					break
+				}
+				hi, ok := nextString(lx)
+				if !ok {
+					break
+				}
+				if len(lo) == len(hi) && len(lo) > 0 {
+					cm.codespaces = append(cm.codespaces, cmapRange{lo: lo, hi: hi})
+				}
+				cm.codeLengths[len(lo)] = true
+			}
+		case "beginbfchar":
+			for {
+				src, ok := nextString(lx)
+				if !ok {
+					break
+				}
+				dst, ok := nextString(lx)
+				if !ok {
+					break
+				}
+				cm.single[string(src)] = decodeUTF16(dst)
+				cm.codeLengths[len(src)] = true
+			}
+		case "beginbfrange":
+			for {
+				lo, ok := nextString(lx)
+				if !ok {
+					break
+				}
+				hi, ok := nextString(lx)
+				if !ok {
+					break
+				}
+				obj, err := lx.parseObject()
+				if err != nil {
+					break
+				}
+				rng := cmapRange{lo: lo, hi: hi}
+				switch v := obj.(type) {
+				case String:
+					rng.dst = v
+				case Array:
+					for _, item := range v {
+						if s, ok := item.(String); ok {
+							rng.array = append(rng.array, decodeUTF16(s))
+						}
+					}
+				}
+				if len(lo) == len(hi) {
+					cm.ranges = append(cm.ranges, rng)
+					cm.codeLengths[len(lo)] = true
+				}
+			}
+		}
+	}
+	return cm
+}
+
+// nextString читает строковый операнд; на ключевом слове (end*) возвращает false.
+func nextString(lx *lexer) ([]byte, bool) {
+	tok, err := lx.next()
+	if err != nil || tok.kind != tokString {
+		return nil, false
+	}
+	return tok.value.(String), true
+}
+
+func decodeUTF16(b []byte) string {
+	if len(b)%2 == 1 {
+		return string(b)
+	}
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+	return string(utf16.Decode(units))
+}
+
+func codeValue(b []byte) int {
+	v := 0
+	for _, c := range b {
+		v = v<<8 | int(c)
+	}
+	return v
+}
+
+func (cm *cmap) lookup(code []byte) (string, bool) {
+	if s, ok := cm.single[string(code)]; ok {
+		return s, true
+	}
+	v := codeValue(code)
+	for _, rng := range cm.ranges {
+		if len(rng.lo) != len(code) {
+			continue
+		}
+		lo, hi := codeValue(rng.lo), codeValue(rng.hi)
+		if v < lo || v > hi {
+			continue
+		}
+		if rng.array != nil {
+			if v-lo < len(rng.array) {
+				return rng.array[v-lo], true
+			}
+			return "", false
+		}
+		if len(rng.dst) < 2 {
+			return "", false
+		}
+		dst := append([]byte(nil), rng.dst...)
+		last := int(dst[len(dst)-2])<<8 | int(dst[len(dst)-1])
+		last += v - lo
+		dst[len(dst)-2], dst[len(dst)-1] = byte(last>>8), byte(last)
+		return decodeUTF16(dst), true
+	}
+	return "", false
+}
+
+// codeLength определяет длину очередного кода по codespacerange; без них —
+// по длинам, встретившимся в bfchar/bfrange, начиная с самой короткой подходящей.
+func (cm *cmap) codeLength(data []byte) int {
+	for _, cs := range cm.codespaces {
+		n := len(cs.lo)
+		if n > len(data) {
+			continue
+		}
+		match := true
+		for i := 0; i < n; i++ {
+			if data[i] < cs.lo[i] || data[i] > cs.hi[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return n
+		}
+	}
+	for n := 1; n <= 4 && n <= len(data); n++ {
+		if cm.codeLengths[n] {
+			if _, ok := cm.lookup(data[:n]); ok {
+				return n
+			}
+		}
+	}
+	for n := 1; n <= 4; n++ {
+		if cm.codeLengths[n] {
+			return n
+		}
+	}
+	return 1
+}
@@ -0,0 +1,114 @@
+package pdf
+
+import (
+	"bytes"
+	"strconv"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// DocInfo — поля словаря /Info из trailer. Отсутствующие даты остаются нулевыми.
+type DocInfo struct {
+	Title        string
+	Author       string
+	Creator      string
+	Producer     string
+	CreationDate time.Time
+	ModDate      time.Time
+}
+
+func (r *Reader) DocInfo() DocInfo {
+	info := r.resolveDict(r.trailer["Info"])
+	if info == nil {
+		return DocInfo{}
+	}
+	text := func(key Name) string {
+		if s, ok := r.resolve(info[key]).(String); ok {
+			return decodeTextString(s)
+		}
+		return ""
+	}
+	date := func(key Name) time.Time {
+		t, _ := parseDate(text(key))
+		return t
+	}
+	return DocInfo{
+		Title:        text("Title"),
+		Author:       text("Author"),
+		Creator:      text("Creator"),
+		Producer:     text("Producer"),
+		CreationDate: date("CreationDate"),
+		ModDate:      date("ModDate"),
+	}
+}
+
+// decodeTextString декодирует текстовую строку PDF: UTF-16BE или UTF-8 с BOM,
+// иначе PDFDocEncoding, которую приближаем cp1252.
+func decodeTextString(s String) string {
+	switch {
+	case bytes.HasPrefix(s, []byte{0xFE, 0xFF}):
+		units := make([]uint16, 0, len(s)/2)
+		for i := 2; i+1 < len(s); i += 2 {
+			units = append(units, uint16(s[i])<<8|uint16(s[i+1]))
+		}
+		return string(utf16.Decode(units))
+	case bytes.HasPrefix(s, []byte{0xEF, 0xBB, 0xBF}) && utf8.Valid(s[3:]):
+		return string(s[3:])
+	}
+	runes := make([]rune, len(s))
+	for i, b := range s {
+		runes[i] = winAnsiRune(b)
+	}
+	return string(runes)
+}
+
+// parseDate разбирает дату вида D:YYYYMMDDHHmmSSOHH'mm'; все поля после года необязательны.
+func parseDate(s string) (time.Time, bool) {
+	if len(s) >= 2 && s[:2] == "D:" {
+		s = s[2:]
+	}
+	if len(s) < 4 {
+		return time.Time{}, false
+	}
+	fields := []int{0, 1, 1, 0, 0, 0}
+	widths := []int{4, 2, 2, 2, 2, 2}
+	pos := 0
+	for i, w := range widths {
+		if pos+w > len(s) || !isDigits(s[pos:pos+w]) {
+			if i == 0 {
+				return time.Time{}, false
+			}
+			break
+		}
+		fields[i], _ = strconv.Atoi(s[pos : pos+w])
+		pos += w
+	}
+
+	loc := time.UTC
+	if pos < len(s) && (s[pos] == '+' || s[pos] == '-') {
+		sign := 1
+		if s[pos] == '-' {
+			sign = -1
+		}
+		var hh, mm int
+		if rest := s[pos+1:]; len(rest) >= 2 && isDigits(rest[:2]) {
+			hh, _ = strconv.Atoi(rest[:2])
+			if len(rest) >= 5 && rest[2] == '\'' && isDigits(rest[3:5]) {
+				mm, _ = strconv.Atoi(rest[3:5])
+			}
+		}
+		loc = time.FixedZone("", sign*(hh*3600+mm*60))
+	}
+	t := time.Date(fields[0], time.Month(fields[1]), fields[2], fields[3], fields[4], fields[5], 0, loc)
+	return t, true
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package pdf
+
+import (
+	"strconv"
+	"strings"
+)
+
+// winAnsiHigh — символы cp1252 в диапазоне 0x80–0x9F; остальные байты совпадают с Latin-1.
+var winAnsiHigh = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡',
+	0x88: 'ˆ', 0x89: '‰', 0x8A: 'Š', 0x8B: '‹', 0x8C: 'Œ', 0x8E: 'Ž',
+	0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—',
+	0x98: '˜', 0x99: '™', 0x9A: 'š', 0x9B: '›', 0x9C: 'œ', 0x9E: 'ž', 0x9F: 'Ÿ',
+}
+
+func winAnsiRune(b byte) rune {
+	if r, ok := winAnsiHigh[b]; ok {
+		return r
+	}
+	return rune(b)
+}
+
+// cp1251Table — кириллическая кодировка Windows для простых шрифтов с нестандартной /Encoding.
+func cp1251Rune(b byte) rune {
+	switch {
+	case b < 0x80:
+		return rune(b)
+	case b >= 0xC0:
+		return rune(0x0410 + int(b) - 0xC0)
+	case b == 0xA8:
+		return 'Ё'
+	case b == 0xB8:
+		return 'ё'
+	}
+	return winAnsiRune(b)
+}
+
+var namedGlyphs = map[string]rune{
+	"space": ' ', "exclam": '!', "quotedbl": '"', "numbersign": '#', "dollar": '$',
+	"percent": '%', "ampersand": '&', "quotesingle": '\'', "quoteright": '’', "parenleft": '(',
+	"parenright": ')', "asterisk": '*', "plus": '+', "comma": ',', "hyphen": '-',
+	"period": '.', "slash": '/', "zero": '0', "one": '1', "two": '2', "three": '3',
+	"four": '4', "five": '5', "six": '6', "seven": '7', "eight": '8', "nine": '9',
+	"colon": ':', "semicolon": ';', "less": '<', "equal": '=', "greater": '>',
+	"question": '?', "at": '@', "bracketleft": '[', "backslash": '\\', "bracketright": ']',
+	"underscore": '_', "quoteleft": '‘', "braceleft": '{', "bar": '|', "braceright": '}',
+	"endash": '–', "emdash": '—', "guillemotleft": '«', "guillemotright": '»',
+	"quotedblleft": '“', "quotedblright": '”', "bullet": '•', "ellipsis": '…',
+	"numero": '№', "afii61352": '№', "nbspace": ' ', "section": '§', "degree": '°',
+}
+
+// glyphRune переводит имя глифа из /Differences в символ: стандартные имена,
+// кириллица afii10017–afii10097, а также uniXXXX и uXXXX.
+func glyphRune(name string) (rune, bool) {
+	if len(name) == 1 {
+		c := name[0]
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+			return rune(c), true
+		}
+	}
+	if r, ok := namedGlyphs[name]; ok {
+		return r, true
+	}
+	if strings.HasPrefix(name, "afii") {
+		if n, err := strconv.Atoi(name[4:]); err == nil {
+			switch {
+			case n == 10023:
+				return 'Ё', true
+			case n == 10071:
+				return 'ё', true
+			case n >= 10017 && n <= 10022:
+				return rune(0x0410 + n - 10017), true
+			case n >= 10024 && n <= 10049:
+				return rune(0x0416 + n - 10024), true
+			case n >= 10065 && n <= 10070:
+				return rune(0x0430 + n - 10065), true
+			case n >= 10072 && n <= 10097:
+				return rune(0x0436 + n - 10072), true
+			}
+		}
+	}
+	if strings.HasPrefix(name, "uni") && len(name) >= 7 {
+		if v, err := strconv.ParseUint(name[3:7], 16, 32); err == nil {
+			return rune(v), true
+		}
+	}
+	if strings.HasPrefix(name, "u") && len(name) >= 5 && len(name) <= 7 {
+		if v, err := strconv.ParseUint(name[1:], 16, 32); err == nil {
+			return rune(v), true
+		}
+	}
+	return 0, false
+}
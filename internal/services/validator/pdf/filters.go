@@ -0,0 +1,247 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	ErrUnsupportedFilter = errors.New("неподдерживаемый фильтр потока")
+	ErrStreamTooLarge    = errors.New("распакованный поток превышает лимит")
+	ErrBudgetExceeded    = errors.New("распаковка потоков остановлена бюджетом")
+)
+
+// decodeStream применяет цепочку фильтров /Filter с параметрами /DecodeParms.
+func (r *Reader) decodeStream(s *Stream) ([]byte, error) {
+	filters, params := r.filterChain(s.Dict)
+	data := s.Data
+	c := &decodeCounter{budget: r.budget, compressed: int64(len(s.Data))}
+	for i, f := range filters {
+		var err error
+		switch f {
+		case "FlateDecode", "Fl":
+			data, err = r.inflate(data, c)
+			if err == nil && i < len(params) {
+				data, err = applyPredictor(data, params[i])
+			}
+		case "ASCIIHexDecode", "AHx":
+			data, err = decodeASCIIHex(data)
+		case "ASCII85Decode", "A85":
+			data, err = decodeASCII85(data)
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedFilter, f)
+		}
+		if c.err != nil {
+			return nil, c.err
+		}
+		if err != nil {
+			return nil, err
+		}
+		if f != "FlateDecode" && f != "Fl" {
+			if err := c.add(len(data)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return data, nil
+}
+
+// decodeCounter передаёт байты на выходе фильтров одного потока в StreamBudget.
+type decodeCounter struct {
+	budget     StreamBudget
+	read       int64
+	compressed int64
+	err        error
+}
+
+func (c *decodeCounter) add(n int) error {
+	if c.budget == nil || n == 0 || c.err != nil {
+		return c.err
+	}
+	c.read += int64(n)
+	if err := c.budget.Decoded(int64(n), c.read, c.compressed); err != nil {
+		c.err = fmt.Errorf("%w: %w", ErrBudgetExceeded, err)
+	}
+	return c.err
+}
+
+// countingReader считает байты по мере распаковки, чтобы бюджет прервал
+// её раньше, чем данные окажутся в памяти целиком.
+type countingReader struct {
+	r io.Reader
+	c *decodeCounter
+}
+
+func (cr countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	if cerr := cr.c.add(n); cerr != nil {
+		return n, cerr
+	}
+	return n, err
+}
+
+func (r *Reader) filterChain(d Dict) ([]Name, []Dict) {
+	var filters []Name
+	switch f := r.resolve(d["Filter"]).(type) {
+	case Name:
+		filters = []Name{f}
+	case Array:
+		for _, item := range f {
+			if n, ok := r.resolve(item).(Name); ok {
+				filters = append(filters, n)
+			}
+		}
+	}
+
+	var params []Dict
+	switch p := r.resolve(d["DecodeParms"]).(type) {
+	case Dict:
+		params = []Dict{p}
+	case Array:
+		for _, item := range p {
+			pd, _ := r.resolve(item).(Dict)
+			params = append(params, pd)
+		}
+	}
+	return filters, params
+}
+
+func (r *Reader) inflate(data []byte, c *decodeCounter) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("FlateDecode: %w", err)
+	}
+	defer zr.Close()
+
+	out, err := io.ReadAll(io.LimitReader(countingReader{r: zr, c: c}, r.maxStreamBytes+1))
+	if int64(len(out)) > r.maxStreamBytes {
+		return nil, ErrStreamTooLarge
+	}
+	// Многие генераторы обрезают контрольную сумму zlib; данные при этом полные.
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && len(out) == 0 {
+		return nil, fmt.Errorf("FlateDecode: %w", err)
+	}
+	return out, nil
+}
+
+// applyPredictor снимает PNG-предикторы (Predictor >= 10), которыми обычно сжаты потоки xref.
+func applyPredictor(data []byte, params Dict) ([]byte, error) {
+	if params == nil {
+		return data, nil
+	}
+	predictor, _ := toInt(params["Predictor"])
+	if predictor < 10 {
+		return data, nil
+	}
+	columns := int64(1)
+	if c, ok := toInt(params["Columns"]); ok {
+		columns = c
+	}
+	colors := int64(1)
+	if c, ok := toInt(params["Colors"]); ok {
+		colors = c
+	}
+	bpc := int64(8)
+	if b, ok := toInt(params["BitsPerComponent"]); ok {
+		bpc = b
+	}
+	// Параметры проверяются до умножения: иначе произведение переполняется.
+	switch {
+	case columns <= 0 || columns > 1<<20, colors <= 0 || colors > 32:
+		return nil, fmt.Errorf("недопустимые параметры предиктора")
+	case bpc != 1 && bpc != 2 && bpc != 4 && bpc != 8 && bpc != 16:
+		return nil, fmt.Errorf("недопустимые параметры предиктора")
+	}
+	bpp := int((colors*bpc + 7) / 8)
+	rowLen := int((columns*colors*bpc + 7) / 8)
+	if rowLen <= 0 || rowLen > 1<<20 || bpp <= 0 || bpp > rowLen {
+		return nil, fmt.Errorf("недопустимые параметры предиктора")
+	}
+
+	var out []byte
+	prev := make([]byte, rowLen)
+	for i := 0; i+1+rowLen <= len(data); i += rowLen + 1 {
+		kind := data[i]
+		row := append([]byte(nil), data[i+1:i+1+rowLen]...)
+		for j := range row {
+			var left, up, upLeft byte
+			if j >= bpp {
+				left = row[j-bpp]
+				upLeft = prev[j-bpp]
+			}
+			up = prev[j]
+			switch kind {
+			case 0:
+			case 1:
+				row[j] += left
+			case 2:
+				row[j] += up
+			case 3:
+				row[j] += byte((int(left) + int(up)) / 2)
+			case 4:
+				row[j] += paeth(left, up, upLeft)
+			default:
+				return nil, fmt.Errorf("неизвестный тип PNG-фильтра %d", kind)
+			}
+		}
+		out = append(out, row...)
+		prev = row
+	}
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	}
+	return c
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func decodeASCIIHex(data []byte) ([]byte, error) {
+	var digits []byte
+	for _, c := range data {
+		if c == '>' {
+			break
+		}
+		if !isSpace(c) {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	_, err := hex.Decode(out, digits)
+	return out, err
+}
+
+func decodeASCII85(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	data = bytes.TrimPrefix(data, []byte("<~"))
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+	out := make([]byte, 4*len(data)+4)
+	n, _, err := ascii85.Decode(out, data, true)
+	if err != nil {
+		return nil, err
+	}
+	return out[:n], nil
+}
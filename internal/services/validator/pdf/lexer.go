@@ -0,0 +1,356 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var errEOF = errors.New("неожиданный конец данных")
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokName
+	tokString
+	tokArrayStart
+	tokArrayEnd
+	tokDictStart
+	tokDictEnd
+	tokKeyword
+)
+
+type token struct {
+	kind  tokenKind
+	value Object
+	text  string
+}
+
+type lexer struct {
+	data     []byte
+	pos      int
+	maxDepth int
+}
+
+func newLexer(data []byte, pos int) *lexer {
+	return &lexer{data: data, pos: pos, maxDepth: maxNesting}
+}
+
+func isSpace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func isDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isSpace(c) {
+			l.pos++
+			continue
+		}
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		return
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return token{kind: tokEOF}, nil
+	}
+
+	c := l.data[l.pos]
+	switch {
+	case c == '/':
+		return l.readName()
+	case c == '(':
+		s, err := l.readLiteralString()
+		return token{kind: tokString, value: s}, err
+	case c == '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return token{kind: tokDictStart}, nil
+		}
+		s, err := l.readHexString()
+		return token{kind: tokString, value: s}, err
+	case c == '>':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '>' {
+			l.pos += 2
+			return token{kind: tokDictEnd}, nil
+		}
+		l.pos++
+		return token{}, fmt.Errorf("непарный '>' на позиции %d", l.pos-1)
+	case c == '[':
+		l.pos++
+		return token{kind: tokArrayStart}, nil
+	case c == ']':
+		l.pos++
+		return token{kind: tokArrayEnd}, nil
+	case c == '{' || c == '}':
+		l.pos++
+		return token{kind: tokKeyword, text: string(c)}, nil
+	case c == ')':
+		l.pos++
+		return token{}, fmt.Errorf("непарная ')' на позиции %d", l.pos-1)
+	}
+
+	start := l.pos
+	for l.pos < len(l.data) && !isSpace(l.data[l.pos]) && !isDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	word := string(l.data[start:l.pos])
+	if n, ok := parseNumber(word); ok {
+		return token{kind: tokNumber, value: n, text: word}, nil
+	}
+	return token{kind: tokKeyword, text: word}, nil
+}
+
+func parseNumber(word string) (Object, bool) {
+	if word == "" {
+		return nil, false
+	}
+	c := word[0]
+	if !(c >= '0' && c <= '9') && c != '+' && c != '-' && c != '.' {
+		return nil, false
+	}
+	if i, err := strconv.ParseInt(word, 10, 64); err == nil {
+		return i, true
+	}
+	if f, err := strconv.ParseFloat(word, 64); err == nil {
+		return f, true
+	}
+	return nil, false
+}
+
+func (l *lexer) readName() (token, error) {
+	l.pos++ // '/'
+	var b bytes.Buffer
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isSpace(c) || isDelimiter(c) {
+			break
+		}
+		if c == '#' && l.pos+2 < len(l.data) {
+			if v, err := strconv.ParseUint(string(l.data[l.pos+1:l.pos+3]), 16, 8); err == nil {
+				b.WriteByte(byte(v))
+				l.pos += 3
+				continue
+			}
+		}
+		b.WriteByte(c)
+		l.pos++
+	}
+	return token{kind: tokName, value: Name(b.String())}, nil
+}
+
+func (l *lexer) readLiteralString() (String, error) {
+	l.pos++ // '('
+	var b bytes.Buffer
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+			b.WriteByte(c)
+		case ')':
+			depth--
+			if depth == 0 {
+				return String(b.Bytes()), nil
+			}
+			b.WriteByte(c)
+		case '\\':
+			if l.pos >= len(l.data) {
+				return nil, errEOF
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					b.WriteByte(byte(v))
+				} else {
+					b.WriteByte(e)
+				}
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return nil, errEOF
+}
+
+func (l *lexer) readHexString() (String, error) {
+	l.pos++ // '<'
+	var digits []byte
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		if c == '>' {
+			if len(digits)%2 == 1 {
+				digits = append(digits, '0')
+			}
+			out := make([]byte, len(digits)/2)
+			for i := range out {
+				v, err := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+				if err != nil {
+					return nil, fmt.Errorf("недопустимая шестнадцатеричная строка: %w", err)
+				}
+				out[i] = byte(v)
+			}
+			return String(out), nil
+		}
+		if isSpace(c) {
+			continue
+		}
+		digits = append(digits, c)
+	}
+	return nil, errEOF
+}
+
+// parseObject читает одно значение. Ссылки "N G R" распознаются заглядыванием вперёд.
+func (l *lexer) parseObject() (Object, error) {
+	tok, err := l.next()
+	if err != nil {
+		return nil, err
+	}
+	return l.parseFrom(tok, 0)
+}
+
+const maxNesting = 256
+
+var ErrNestingTooDeep = errors.New("слишком глубокая вложенность объектов")
+
+func (l *lexer) parseFrom(tok token, depth int) (Object, error) {
+	if depth > l.maxDepth {
+		return nil, ErrNestingTooDeep
+	}
+	switch tok.kind {
+	case tokEOF:
+		return nil, errEOF
+	case tokNumber:
+		if n, ok := tok.value.(int64); ok && n >= 0 {
+			if ref, ok := l.tryRef(n); ok {
+				return ref, nil
+			}
+		}
+		return tok.value, nil
+	case tokName, tokString:
+		return tok.value, nil
+	case tokArrayStart:
+		var arr Array
+		for {
+			t, err := l.next()
+			if err != nil {
+				return nil, err
+			}
+			if t.kind == tokArrayEnd {
+				return arr, nil
+			}
+			v, err := l.parseFrom(t, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+	case tokDictStart:
+		dict := Dict{}
+		for {
+			t, err := l.next()
+			if err != nil {
+				return nil, err
+			}
+			if t.kind == tokDictEnd {
+				return dict, nil
+			}
+			key, ok := t.value.(Name)
+			if t.kind != tokName || !ok {
+				return nil, fmt.Errorf("ключ словаря не является именем на позиции %d", l.pos)
+			}
+			vt, err := l.next()
+			if err != nil {
+				return nil, err
+			}
+			if vt.kind == tokDictEnd {
+				dict[key] = nil
+				return dict, nil
+			}
+			v, err := l.parseFrom(vt, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			dict[key] = v
+		}
+	case tokKeyword:
+		switch tok.text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return keyword(tok.text), nil
+	}
+	return nil, fmt.Errorf("неожиданный токен на позиции %d", l.pos)
+}
+
+func (l *lexer) tryRef(num int64) (Object, bool) {
+	save := l.pos
+	gen, err := l.next()
+	if err != nil || gen.kind != tokNumber {
+		l.pos = save
+		return nil, false
+	}
+	g, ok := gen.value.(int64)
+	if !ok || g < 0 {
+		l.pos = save
+		return nil, false
+	}
+	r, err := l.next()
+	if err != nil || r.kind != tokKeyword || r.text != "R" {
+		l.pos = save
+		return nil, false
+	}
+	return Ref{Num: int(num), Gen: int(g)}, true
+}
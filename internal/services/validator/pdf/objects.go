@@ -0,0 +1,51 @@
+package pdf
+
+// Object — значение PDF: int64, float64, bool, nil, Name, String, Array, Dict, Ref или *Stream.
+type Object interface{}
+
+type Name string
+
+// String хранит сырые байты строки; кодировка зависит от шрифта или контекста.
+type String []byte
+
+type Array []Object
+
+type Dict map[Name]Object
+
+type Ref struct {
+	Num int
+	Gen int
+}
+
+type Stream struct {
+	Dict Dict
+	Data []byte
+}
+
+// keyword — операторы содержимого и служебные слова (obj, stream, R и т.п.).
+type keyword string
+
+func (d Dict) Name(key Name) Name {
+	n, _ := d[key].(Name)
+	return n
+}
+
+func toFloat(o Object) (float64, bool) {
+	switch v := o.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func toInt(o Object) (int64, bool) {
+	switch v := o.(type) {
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,640 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var (
+	ErrNotPDF    = errors.New("отсутствует заголовок %PDF")
+	ErrNoCatalog = errors.New("не найден корневой каталог /Root")
+)
+
+const (
+	headerSearchWindow    = 1024
+	trailerSearchWindow   = 2048
+	defaultMaxStreamBytes = 64 << 20
+	defaultMaxOperators   = 10000000
+	defaultMaxTextBytes   = 64 << 20
+	maxResolveDepth       = 32
+)
+
+type xrefEntry struct {
+	offset   int64
+	inStream bool
+	stream   int
+	index    int
+}
+
+// Info — результат структурной проверки файла.
+type Info struct {
+	Version      string
+	Pages        int
+	Encrypted    bool
+	HasEOFMarker bool
+	// XrefRepaired — таблица xref повреждена, объекты найдены сканированием файла.
+	XrefRepaired bool
+}
+
+type Reader struct {
+	data           []byte
+	version        string
+	trailer        Dict
+	xref           map[int]xrefEntry
+	cache          map[int]Object
+	objStreams     map[int]map[int]Object
+	maxStreamBytes int64
+	maxOperators   int
+	maxTextBytes   int64
+	maxNesting     int
+	budget         StreamBudget
+	repaired       bool
+	depth          int
+
+	// Состояние извлечения текста: формы распаковываются один раз, а
+	// операторы и текст считаются за весь документ.
+	decoded     map[*Stream]decodedStream
+	activeForms map[*Stream]bool
+	operators   int
+	textBytes   int64
+}
+
+type Option func(*Reader)
+
+// WithMaxStreamBytes ограничивает размер каждого распакованного потока.
+func WithMaxStreamBytes(n int64) Option {
+	return func(r *Reader) {
+		if n > 0 {
+			r.maxStreamBytes = n
+		}
+	}
+}
+
+// WithMaxOperators ограничивает число операторов содержимого за документ,
+// включая повторные вызовы одной и той же формы.
+func WithMaxOperators(n int) Option {
+	return func(r *Reader) {
+		if n > 0 {
+			r.maxOperators = n
+		}
+	}
+}
+
+// WithMaxTextBytes ограничивает объём текста, извлекаемого из документа.
+func WithMaxTextBytes(n int64) Option {
+	return func(r *Reader) {
+		if n > 0 {
+			r.maxTextBytes = n
+		}
+	}
+}
+
+// WithMaxNesting ограничивает вложенность массивов и словарей в потоках
+// содержимого и вложенность форм XObject друг в друга.
+func WithMaxNesting(n int) Option {
+	return func(r *Reader) {
+		if n > 0 {
+			r.maxNesting = n
+		}
+	}
+}
+
+// StreamBudget учитывает распакованные байты всех потоков документа. Decoded
+// вызывается по мере распаковки: n — новые байты, read — всего байт на выходе
+// фильтров потока, compressed — размер потока в файле. Ошибка прерывает распаковку.
+type StreamBudget interface {
+	Decoded(n, read, compressed int64) error
+}
+
+// WithStreamBudget передаёт распаковку потоков под учёт budget.
+func WithStreamBudget(budget StreamBudget) Option {
+	return func(r *Reader) {
+		r.budget = budget
+	}
+}
+
+func Open(data []byte, opts ...Option) (*Reader, error) {
+	r := &Reader{
+		data:           data,
+		xref:           make(map[int]xrefEntry),
+		cache:          make(map[int]Object),
+		objStreams:     make(map[int]map[int]Object),
+		maxStreamBytes: defaultMaxStreamBytes,
+		maxOperators:   defaultMaxOperators,
+		maxTextBytes:   defaultMaxTextBytes,
+		maxNesting:     maxNesting,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	window := data
+	if len(window) > headerSearchWindow {
+		window = window[:headerSearchWindow]
+	}
+	i := bytes.Index(window, []byte("%PDF-"))
+	if i < 0 {
+		return nil, ErrNotPDF
+	}
+	r.version = readVersion(data[i+5:])
+
+	if err := r.loadXref(); err != nil || r.trailer == nil || r.trailer["Root"] == nil {
+		if err := r.repair(); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := r.resolve(r.trailer["Root"]).(Dict); !ok {
+		return nil, ErrNoCatalog
+	}
+	return r, nil
+}
+
+func readVersion(b []byte) string {
+	end := 0
+	for end < len(b) && end < 8 && (b[end] == '.' || (b[end] >= '0' && b[end] <= '9')) {
+		end++
+	}
+	return string(b[:end])
+}
+
+func (r *Reader) Info() Info {
+	tail := r.data
+	if len(tail) > trailerSearchWindow {
+		tail = tail[len(tail)-trailerSearchWindow:]
+	}
+	return Info{
+		Version:      r.version,
+		Pages:        len(r.pages()),
+		Encrypted:    r.trailer["Encrypt"] != nil,
+		HasEOFMarker: bytes.Contains(tail, []byte("%%EOF")),
+		XrefRepaired: r.repaired,
+	}
+}
+
+func (r *Reader) loadXref() error {
+	i := bytes.LastIndex(r.data, []byte("startxref"))
+	if i < 0 {
+		return fmt.Errorf("не найден startxref")
+	}
+	lx := newLexer(r.data, i+len("startxref"))
+	tok, err := lx.next()
+	if err != nil || tok.kind != tokNumber {
+		return fmt.Errorf("недопустимое значение startxref")
+	}
+	offset, _ := toInt(tok.value)
+
+	seen := map[int64]bool{}
+	for {
+		if seen[offset] || offset <= 0 || offset >= int64(len(r.data)) {
+			return fmt.Errorf("недопустимое смещение xref %d", offset)
+		}
+		seen[offset] = true
+
+		trailer, err := r.readXrefSection(int(offset))
+		if err != nil {
+			return err
+		}
+		if r.trailer == nil {
+			r.trailer = trailer
+		}
+		if stm, ok := toInt(trailer["XRefStm"]); ok && !seen[stm] {
+			seen[stm] = true
+			if _, err := r.readXrefSection(int(stm)); err != nil {
+				return err
+			}
+		}
+		prev, ok := toInt(trailer["Prev"])
+		if !ok {
+			break
+		}
+		offset = prev
+	}
+	return nil
+}
+
+func (r *Reader) readXrefSection(offset int) (Dict, error) {
+	lx := newLexer(r.data, offset)
+	lx.skipSpace()
+	if bytes.HasPrefix(r.data[lx.pos:], []byte("xref")) {
+		lx.pos += len("xref")
+		return r.readXrefTable(lx)
+	}
+	return r.readXrefStream(offset)
+}
+
+func (r *Reader) readXrefTable(lx *lexer) (Dict, error) {
+	for {
+		tok, err := lx.next()
+		if err != nil {
+			return nil, err
+		}
+		if tok.kind == tokKeyword && tok.text == "trailer" {
+			trailer, err := lx.parseObject()
+			if err != nil {
+				return nil, fmt.Errorf("разобрать trailer: %w", err)
+			}
+			d, ok := trailer.(Dict)
+			if !ok {
+				return nil, fmt.Errorf("trailer не является словарём")
+			}
+			return d, nil
+		}
+		if tok.kind != tokNumber {
+			return nil, fmt.Errorf("недопустимая таблица xref")
+		}
+		start, _ := toInt(tok.value)
+		countTok, err := lx.next()
+		if err != nil || countTok.kind != tokNumber {
+			return nil, fmt.Errorf("недопустимый подраздел xref")
+		}
+		count, _ := toInt(countTok.value)
+		if count < 0 || count > int64(len(r.data)) {
+			return nil, fmt.Errorf("недопустимый размер подраздела xref")
+		}
+		for n := int64(0); n < count; n++ {
+			offTok, err1 := lx.next()
+			genTok, err2 := lx.next()
+			kindTok, err3 := lx.next()
+			if err1 != nil || err2 != nil || err3 != nil || offTok.kind != tokNumber || genTok.kind != tokNumber || kindTok.kind != tokKeyword {
+				return nil, fmt.Errorf("недопустимая запись xref")
+			}
+			num := int(start + n)
+			if _, exists := r.xref[num]; exists || kindTok.text != "n" {
+				continue
+			}
+			off, _ := toInt(offTok.value)
+			r.xref[num] = xrefEntry{offset: off}
+		}
+	}
+}
+
+func (r *Reader) readXrefStream(offset int) (Dict, error) {
+	_, obj, err := r.parseIndirectAt(offset)
+	if err != nil {
+		return nil, fmt.Errorf("разобрать поток xref: %w", err)
+	}
+	s, ok := obj.(*Stream)
+	if !ok || s.Dict.Name("Type") != "XRef" {
+		return nil, fmt.Errorf("по смещению xref нет потока /XRef")
+	}
+	data, err := r.decodeStream(s)
+	if err != nil {
+		return nil, err
+	}
+
+	w, _ := s.Dict["W"].(Array)
+	if len(w) != 3 {
+		return nil, fmt.Errorf("недопустимый /W в потоке xref")
+	}
+	widths := make([]int, 3)
+	rowLen := 0
+	for i, v := range w {
+		n, _ := toInt(v)
+		if n < 0 || n > 8 {
+			return nil, fmt.Errorf("недопустимый /W в потоке xref")
+		}
+		widths[i] = int(n)
+		rowLen += int(n)
+	}
+	if rowLen == 0 {
+		return nil, fmt.Errorf("недопустимый /W в потоке xref")
+	}
+
+	size, _ := toInt(s.Dict["Size"])
+	index, _ := s.Dict["Index"].(Array)
+	if len(index) == 0 {
+		index = Array{int64(0), size}
+	}
+
+	pos := 0
+	for i := 0; i+1 < len(index); i += 2 {
+		start, _ := toInt(index[i])
+		count, _ := toInt(index[i+1])
+		for n := int64(0); n < count; n++ {
+			if pos+rowLen > len(data) {
+				return s.Dict, nil
+			}
+			fields := [3]int64{1, 0, 0}
+			p := pos
+			for f := 0; f < 3; f++ {
+				if widths[f] == 0 {
+					continue
+				}
+				var v int64
+				for k := 0; k < widths[f]; k++ {
+					v = v<<8 | int64(data[p])
+					p++
+				}
+				fields[f] = v
+			}
+			pos += rowLen
+
+			num := int(start + n)
+			if _, exists := r.xref[num]; exists {
+				continue
+			}
+			switch fields[0] {
+			case 1:
+				r.xref[num] = xrefEntry{offset: fields[1]}
+			case 2:
+				r.xref[num] = xrefEntry{inStream: true, stream: int(fields[1]), index: int(fields[2])}
+			}
+		}
+	}
+	return s.Dict, nil
+}
+
+var objHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// repair восстанавливает таблицу объектов сканированием файла, если xref повреждена.
+func (r *Reader) repair() error {
+	r.repaired = true
+	r.xref = make(map[int]xrefEntry)
+	r.cache = make(map[int]Object)
+
+	for _, m := range objHeader.FindAllSubmatchIndex(r.data, -1) {
+		if m[0] > 0 && !isSpace(r.data[m[0]-1]) && !isDelimiter(r.data[m[0]-1]) {
+			continue
+		}
+		num, err := strconv.Atoi(string(r.data[m[2]:m[3]]))
+		if err != nil {
+			continue
+		}
+		r.xref[num] = xrefEntry{offset: int64(m[0])}
+	}
+
+	// Объекты из потоков /ObjStm в режиме восстановления индексируются отдельно.
+	for num := range r.xref {
+		s, ok := r.object(num).(*Stream)
+		if !ok || s.Dict.Name("Type") != "ObjStm" {
+			continue
+		}
+		objs, err := r.loadObjStream(num)
+		if err != nil {
+			continue
+		}
+		for inner := range objs {
+			if _, exists := r.xref[inner]; !exists {
+				r.xref[inner] = xrefEntry{inStream: true, stream: num}
+			}
+		}
+	}
+
+	var trailer Dict
+	for i := bytes.Index(r.data, []byte("trailer")); i >= 0; {
+		lx := newLexer(r.data, i+len("trailer"))
+		if obj, err := lx.parseObject(); err == nil {
+			if d, ok := obj.(Dict); ok && d["Root"] != nil {
+				trailer = d
+			}
+		}
+		next := bytes.Index(r.data[i+1:], []byte("trailer"))
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	if trailer == nil {
+		for num := range r.xref {
+			if s, ok := r.object(num).(*Stream); ok && s.Dict.Name("Type") == "XRef" && s.Dict["Root"] != nil {
+				trailer = s.Dict
+			}
+		}
+	}
+	if trailer == nil {
+		for num := range r.xref {
+			if d, ok := r.object(num).(Dict); ok && d.Name("Type") == "Catalog" {
+				trailer = Dict{"Root": Ref{Num: num}}
+				break
+			}
+		}
+	}
+	if trailer == nil {
+		return ErrNoCatalog
+	}
+	r.trailer = trailer
+	return nil
+}
+
+// parseIndirectAt разбирает "N G obj ... endobj" по смещению.
+func (r *Reader) parseIndirectAt(offset int) (int, Object, error) {
+	if offset < 0 || offset >= len(r.data) {
+		return 0, nil, fmt.Errorf("смещение объекта вне файла")
+	}
+	lx := newLexer(r.data, offset)
+	numTok, err := lx.next()
+	if err != nil || numTok.kind != tokNumber {
+		return 0, nil, fmt.Errorf("ожидался номер объекта на позиции %d", offset)
+	}
+	genTok, err := lx.next()
+	if err != nil || genTok.kind != tokNumber {
+		return 0, nil, fmt.Errorf("ожидался номер поколения на позиции %d", offset)
+	}
+	objTok, err := lx.next()
+	if err != nil || objTok.kind != tokKeyword || objTok.text != "obj" {
+		return 0, nil, fmt.Errorf("ожидалось obj на позиции %d", offset)
+	}
+	num, _ := toInt(numTok.value)
+
+	obj, err := lx.parseObject()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	dict, ok := obj.(Dict)
+	if !ok {
+		return int(num), obj, nil
+	}
+	save := lx.pos
+	tok, err := lx.next()
+	if err != nil || tok.kind != tokKeyword || tok.text != "stream" {
+		lx.pos = save
+		return int(num), obj, nil
+	}
+	data, err := r.readStreamData(lx.pos, dict)
+	if err != nil {
+		return 0, nil, err
+	}
+	return int(num), &Stream{Dict: dict, Data: data}, nil
+}
+
+func (r *Reader) readStreamData(pos int, dict Dict) ([]byte, error) {
+	if pos < len(r.data) && r.data[pos] == '\r' {
+		pos++
+	}
+	if pos < len(r.data) && r.data[pos] == '\n' {
+		pos++
+	}
+
+	if length, ok := toInt(r.resolve(dict["Length"])); ok && length >= 0 && int64(pos)+length <= int64(len(r.data)) {
+		end := pos + int(length)
+		rest := newLexer(r.data, end)
+		rest.skipSpace()
+		if bytes.HasPrefix(r.data[rest.pos:], []byte("endstream")) {
+			return r.data[pos:end], nil
+		}
+	}
+
+	// /Length неверен или косвенный и недоступен: ищем endstream.
+	i := bytes.Index(r.data[pos:], []byte("endstream"))
+	if i < 0 {
+		return nil, fmt.Errorf("не найден endstream")
+	}
+	end := pos + i
+	if end > pos && r.data[end-1] == '\n' {
+		end--
+	}
+	if end > pos && r.data[end-1] == '\r' {
+		end--
+	}
+	return r.data[pos:end], nil
+}
+
+func (r *Reader) object(num int) Object {
+	if obj, ok := r.cache[num]; ok {
+		return obj
+	}
+	entry, ok := r.xref[num]
+	if !ok {
+		return nil
+	}
+
+	// Защита от циклов: /Length потока может ссылаться на объект, который сейчас разбирается.
+	r.cache[num] = nil
+	var obj Object
+	if entry.inStream {
+		objs, err := r.loadObjStream(entry.stream)
+		if err == nil {
+			obj = objs[num]
+		}
+	} else if _, parsed, err := r.parseIndirectAt(int(entry.offset)); err == nil {
+		obj = parsed
+	}
+	r.cache[num] = obj
+	return obj
+}
+
+func (r *Reader) loadObjStream(num int) (map[int]Object, error) {
+	if objs, ok := r.objStreams[num]; ok {
+		return objs, nil
+	}
+	r.objStreams[num] = nil
+
+	entry, ok := r.xref[num]
+	if !ok || entry.inStream {
+		return nil, fmt.Errorf("поток объектов %d не найден", num)
+	}
+	_, obj, err := r.parseIndirectAt(int(entry.offset))
+	if err != nil {
+		return nil, err
+	}
+	s, ok := obj.(*Stream)
+	if !ok {
+		return nil, fmt.Errorf("объект %d не является потоком", num)
+	}
+	data, err := r.decodeStream(s)
+	if err != nil {
+		return nil, err
+	}
+
+	n, _ := toInt(s.Dict["N"])
+	first, _ := toInt(s.Dict["First"])
+	if n < 0 || first < 0 || first > int64(len(data)) {
+		return nil, fmt.Errorf("недопустимый заголовок потока объектов %d", num)
+	}
+	lx := newLexer(data, 0)
+	objs := make(map[int]Object, n)
+	for i := int64(0); i < n; i++ {
+		numTok, err1 := lx.next()
+		offTok, err2 := lx.next()
+		if err1 != nil || err2 != nil || numTok.kind != tokNumber || offTok.kind != tokNumber {
+			break
+		}
+		objNum, _ := toInt(numTok.value)
+		off, _ := toInt(offTok.value)
+		if first+off >= int64(len(data)) {
+			continue
+		}
+		inner := newLexer(data, int(first+off))
+		if v, err := inner.parseObject(); err == nil {
+			objs[int(objNum)] = v
+		}
+	}
+	r.objStreams[num] = objs
+	return objs, nil
+}
+
+// resolve разыменовывает ссылки; глубина ограничена против циклов ссылок.
+func (r *Reader) resolve(o Object) Object {
+	for i := 0; i < maxResolveDepth; i++ {
+		ref, ok := o.(Ref)
+		if !ok {
+			return o
+		}
+		if r.depth > maxResolveDepth {
+			return nil
+		}
+		r.depth++
+		o = r.object(ref.Num)
+		r.depth--
+	}
+	return nil
+}
+
+func (r *Reader) resolveDict(o Object) Dict {
+	switch v := r.resolve(o).(type) {
+	case Dict:
+		return v
+	case *Stream:
+		return v.Dict
+	}
+	return nil
+}
+
+type page struct {
+	dict      Dict
+	resources Dict
+}
+
+// pages обходит дерево страниц, наследуя /Resources от родительских узлов.
+func (r *Reader) pages() []page {
+	root := r.resolveDict(r.trailer["Root"])
+	if root == nil {
+		return nil
+	}
+	var out []page
+	visited := map[Ref]bool{}
+	var walk func(node Object, inherited Dict, depth int)
+	walk = func(node Object, inherited Dict, depth int) {
+		if depth > maxResolveDepth {
+			return
+		}
+		if ref, ok := node.(Ref); ok {
+			if visited[ref] {
+				return
+			}
+			visited[ref] = true
+		}
+		d := r.resolveDict(node)
+		if d == nil {
+			return
+		}
+		resources := inherited
+		if res := r.resolveDict(d["Resources"]); res != nil {
+			resources = res
+		}
+		kids, hasKids := r.resolve(d["Kids"]).(Array)
+		if d.Name("Type") == "Page" || (!hasKids && d.Name("Type") != "Pages") {
+			out = append(out, page{dict: d, resources: resources})
+			return
+		}
+		for _, kid := range kids {
+			walk(kid, resources, depth+1)
+		}
+	}
+	walk(root["Pages"], nil, 0)
+	return out
+}
@@ -0,0 +1,260 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// buildPDF собирает минимальный PDF с корректной таблицей xref. Объекты
+// нумеруются с 1 в порядке передачи; каталог — объект 1.
+func buildPDF(objects []string, trailerExtra string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R %s>>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, trailerExtra, xref)
+	return buf.Bytes()
+}
+
+func streamObject(dict string, data []byte) string {
+	return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+}
+
+func flate(data []byte) []byte {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	zw.Write(data)
+	zw.Close()
+	return buf.Bytes()
+}
+
+const cyrillicToUnicode = `/CIDInit /ProcSet findresource begin
+12 dict begin
+begincmap
+1 begincodespacerange
+<0000> <FFFF>
+endcodespacerange
+4 beginbfchar
+<0001> <0414>
+<0002> <043E>
+<0003> <0433>
+<0005> <0440>
+endbfchar
+1 beginbfrange
+<0010> <0012> <0430>
+endbfrange
+endcmap
+CMapName currentdict /CMap defineresource pop
+end
+end`
+
+// pdfWithCyrillicText — страница со шрифтом Type0, текст через ToUnicode:
+// 0001 0002 0003 0002 0012 0002 0005 → «Договор», «в» берётся из bfrange.
+func pdfWithCyrillicText() []byte {
+	content := []byte("BT /F1 12 Tf 72 700 Td <0001000200030002001200020005> Tj /F2 12 Tf 0 -14 Td (Hello) Tj ET")
+	return buildPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /Resources << /Font << /F1 5 0 R /F2 7 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R >>",
+		streamObject("/Filter /FlateDecode", flate(content)),
+		"<< /Type /Font /Subtype /Type0 /BaseFont /Test /Encoding /Identity-H /ToUnicode 6 0 R >>",
+		streamObject("", []byte(cyrillicToUnicode)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	}, "")
+}
+
+func TestOpen_NotPDF(t *testing.T) {
+	_, err := Open([]byte("PK\x03\x04 not a pdf"))
+	require.ErrorIs(t, err, ErrNotPDF)
+}
+
+func TestPageTexts_ToUnicodeAndFlate(t *testing.T) {
+	r, err := Open(pdfWithCyrillicText())
+	require.NoError(t, err)
+
+	info := r.Info()
+	require.Equal(t, "1.7", info.Version)
+	require.Equal(t, 1, info.Pages)
+	require.True(t, info.HasEOFMarker)
+	require.False(t, info.XrefRepaired)
+	require.False(t, info.Encrypted)
+
+	texts, err := r.PageTexts()
+	require.NoError(t, err)
+	require.Equal(t, []string{"Договор\nHello"}, texts)
+}
+
+func TestPageTexts_SimpleFontEncodings(t *testing.T) {
+	content := []byte("BT /F1 12 Tf 72 700 Td [(\xc4\xee\xe3) -300 (\xee\xe2\xee\xf0)] TJ /F2 12 Tf 0 -14 Td (\x01\x02) Tj ET")
+	data := buildPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 5 0 R /F2 6 0 R >> >> /Contents 4 0 R >>",
+		streamObject("", content),
+		"<< /Type /Font /Subtype /Type1 /Encoding /CP1251 >>",
+		"<< /Type /Font /Subtype /Type1 /Encoding << /Differences [1 /afii10017 /uni0431] >> >>",
+	}, "")
+
+	r, err := Open(data)
+	require.NoError(t, err)
+	texts, err := r.PageTexts()
+	require.NoError(t, err)
+	require.Equal(t, []string{"Дог овор\nАб"}, texts)
+}
+
+func TestOpen_RepairsBrokenXref(t *testing.T) {
+	data := pdfWithCyrillicText()
+	i := bytes.LastIndex(data, []byte("startxref"))
+	broken := append(append([]byte{}, data[:i]...), []byte("startxref\n999999\n%%EOF\n")...)
+	// Смещения в таблице тоже портим, чтобы восстановление шло только сканированием.
+	broken = bytes.Replace(broken, []byte("0000000015 00000 n"), []byte("0000000001 00000 n"), 1)
+
+	r, err := Open(broken)
+	require.NoError(t, err)
+	require.True(t, r.Info().XrefRepaired)
+
+	texts, err := r.PageTexts()
+	require.NoError(t, err)
+	require.Len(t, texts, 1)
+	require.True(t, strings.HasPrefix(texts[0], "Договор"))
+}
+
+func TestInfo_EncryptedAndMissingEOF(t *testing.T) {
+	data := buildPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [] /Count 0 >>",
+		"<< /Filter /Standard /V 2 /R 3 >>",
+	}, "/Encrypt 3 0 R ")
+	data = bytes.TrimSuffix(data, []byte("%%EOF\n"))
+
+	r, err := Open(data)
+	require.NoError(t, err)
+	info := r.Info()
+	require.True(t, info.Encrypted)
+	require.False(t, info.HasEOFMarker)
+	require.Zero(t, info.Pages)
+}
+
+func TestDocInfo(t *testing.T) {
+	data := buildPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [] /Count 0 >>",
+		// Заголовок «Договор» в UTF-16BE.
+		"<< /Title <FEFF0414043E0433043E0432043E0440> /Author (Ivanov) /Producer (LibreOffice) " +
+			"/CreationDate (D:20240201103000+03'00') /ModDate (D:2024) >>",
+	}, "/Info 3 0 R ")
+
+	r, err := Open(data)
+	require.NoError(t, err)
+	info := r.DocInfo()
+	require.Equal(t, "Договор", info.Title)
+	require.Equal(t, "Ivanov", info.Author)
+	require.Equal(t, "LibreOffice", info.Producer)
+	require.Equal(t, "2024-02-01T07:30:00Z", info.CreationDate.UTC().Format(time.RFC3339))
+	require.Equal(t, 2024, info.ModDate.Year())
+	require.Equal(t, time.January, info.ModDate.Month())
+}
+
+func TestDecodeStream_Limit(t *testing.T) {
+	content := bytes.Repeat([]byte("A"), 4096)
+	data := buildPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		streamObject("/Filter /FlateDecode", flate(content)),
+	}, "")
+
+	r, err := Open(data, WithMaxStreamBytes(1024))
+	require.NoError(t, err)
+	_, err = r.PageTexts()
+	require.ErrorIs(t, err, ErrStreamTooLarge)
+}
+
+func TestApplyPredictor(t *testing.T) {
+	// Две строки по два байта с фильтром Up: вторая прибавляется к первой.
+	out, err := applyPredictor([]byte{2, 1, 2, 2, 1, 1}, Dict{"Predictor": int64(12), "Columns": int64(2)})
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 2, 3}, out)
+
+	// colors*bpc переполняет int64, и без проверки bpp становится отрицательным.
+	_, err = applyPredictor([]byte{1, 1, 1}, Dict{
+		"Predictor": int64(12), "Columns": int64(2), "Colors": int64(1152921504606846977), "BitsPerComponent": int64(8),
+	})
+	require.Error(t, err)
+
+	for _, params := range []Dict{
+		{"Predictor": int64(12), "BitsPerComponent": int64(3)},
+		{"Predictor": int64(12), "Columns": int64(1<<20 + 1)},
+		{"Predictor": int64(12), "Colors": int64(0)},
+	} {
+		_, err = applyPredictor([]byte{0, 0}, params)
+		require.Error(t, err)
+	}
+
+	data := buildPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		streamObject("/Filter /FlateDecode /DecodeParms << /Predictor 12 /Columns 2 /Colors 1152921504606846977 >>", flate([]byte{1, 1, 1})),
+	}, "")
+	r, err := Open(data)
+	require.NoError(t, err)
+	require.NotPanics(t, func() { _, _ = r.PageTexts() })
+}
+
+// formPDF — страница вызывает форму 5, которая ссылается сама на себя и
+// трижды вызывает форму 6; форма 6 десять раз вызывает форму 7 и так далее.
+func formPDF() []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 8 0 R >> /XObject << /X 5 0 R >> >> /Contents 4 0 R >>",
+		streamObject("", []byte("/X Do")),
+		streamObject("/Type /XObject /Subtype /Form /Resources << /Font << /F1 8 0 R >> /XObject << /X 5 0 R /Y 6 0 R >> >>",
+			[]byte("BT /F1 12 Tf (Loop) Tj ET /X Do /Y Do /Y Do /Y Do")),
+		streamObject("/Type /XObject /Subtype /Form /Resources << /XObject << /Z 7 0 R >> >>",
+			[]byte(strings.Repeat("/Z Do ", 10))),
+		streamObject("/Type /XObject /Subtype /Form /Resources << /Font << /F1 8 0 R >> >>",
+			[]byte("BT /F1 12 Tf (Leaf) Tj ET")),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	}
+	return buildPDF(objects, "")
+}
+
+func TestPageTexts_FormCycleAndLimits(t *testing.T) {
+	r, err := Open(formPDF())
+	require.NoError(t, err)
+	texts, err := r.PageTexts()
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(texts[0], "Loop"))
+	require.Equal(t, 30, strings.Count(texts[0], "Leaf"))
+
+	r, err = Open(formPDF(), WithMaxOperators(100))
+	require.NoError(t, err)
+	_, err = r.PageTexts()
+	require.ErrorIs(t, err, ErrTooManyOperators)
+
+	r, err = Open(formPDF(), WithMaxTextBytes(64))
+	require.NoError(t, err)
+	_, err = r.PageTexts()
+	require.ErrorIs(t, err, ErrTextTooLarge)
+
+	r, err = Open(formPDF(), WithMaxNesting(1))
+	require.NoError(t, err)
+	_, err = r.PageTexts()
+	require.ErrorIs(t, err, ErrNestingTooDeep)
+}
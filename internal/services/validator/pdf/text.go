@@ -0,0 +1,354 @@
+package pdf
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrTooManyOperators = errors.New("содержимое страниц содержит слишком много операторов")
+	ErrTextTooLarge     = errors.New("извлечённый текст превышает лимит")
+)
+
+type font struct {
+	toUnicode *cmap
+	composite bool
+	cyrillic  bool
+	diffs     map[byte]rune
+}
+
+func (r *Reader) loadFont(d Dict) *font {
+	f := &font{}
+	if d == nil {
+		return f
+	}
+	f.composite = d.Name("Subtype") == "Type0"
+
+	if s, ok := r.resolve(d["ToUnicode"]).(*Stream); ok {
+		if data, err := r.decodeOnce(s); err == nil {
+			f.toUnicode = parseCMap(data)
+		}
+	}
+
+	switch enc := r.resolve(d["Encoding"]).(type) {
+	case Name:
+		f.cyrillic = strings.Contains(strings.ToLower(string(enc)), "1251")
+	case Dict:
+		f.cyrillic = strings.Contains(strings.ToLower(string(enc.Name("BaseEncoding"))), "1251")
+		if diffs, ok := r.resolve(enc["Differences"]).(Array); ok {
+			f.diffs = map[byte]rune{}
+			code := int64(-1)
+			for _, item := range diffs {
+				switch v := r.resolve(item).(type) {
+				case int64:
+					code = v
+				case Name:
+					if code >= 0 && code < 256 {
+						if rn, ok := glyphRune(string(v)); ok {
+							f.diffs[byte(code)] = rn
+						}
+					}
+					code++
+				}
+			}
+		}
+	}
+	return f
+}
+
+func (f *font) decode(s []byte) string {
+	var b strings.Builder
+	if f.toUnicode != nil {
+		for i := 0; i < len(s); {
+			n := f.toUnicode.codeLength(s[i:])
+			if i+n > len(s) {
+				n = len(s) - i
+			}
+			if text, ok := f.toUnicode.lookup(s[i : i+n]); ok {
+				b.WriteString(text)
+			} else if !f.composite && n == 1 {
+				b.WriteRune(f.simpleRune(s[i]))
+			}
+			i += n
+		}
+		return b.String()
+	}
+	if f.composite {
+		// Identity-H без ToUnicode: коды — номера глифов, текст восстановить нельзя.
+		return ""
+	}
+	for _, c := range s {
+		b.WriteRune(f.simpleRune(c))
+	}
+	return b.String()
+}
+
+func (f *font) simpleRune(c byte) rune {
+	if r, ok := f.diffs[c]; ok {
+		return r
+	}
+	if f.cyrillic {
+		return cp1251Rune(c)
+	}
+	return winAnsiRune(c)
+}
+
+type textWriter struct {
+	b              strings.Builder
+	pendingNewline bool
+	pendingSpace   bool
+}
+
+func (w *textWriter) write(s string) {
+	if s == "" {
+		return
+	}
+	if w.b.Len() > 0 {
+		last := w.b.String()[w.b.Len()-1]
+		switch {
+		case w.pendingNewline && last != '\n':
+			w.b.WriteByte('\n')
+		case w.pendingSpace && last != ' ' && last != '\n' && !strings.HasPrefix(s, " "):
+			w.b.WriteByte(' ')
+		}
+	}
+	w.pendingNewline, w.pendingSpace = false, false
+	w.b.WriteString(s)
+}
+
+// PageTexts извлекает текст каждой страницы. Строки разделяются по смене
+// вертикальной позиции, пробелы — по сдвигам внутри строки и кернингу в TJ.
+func (r *Reader) PageTexts() ([]string, error) {
+	pages := r.pages()
+	out := make([]string, 0, len(pages))
+	r.decoded = make(map[*Stream]decodedStream)
+	r.activeForms = make(map[*Stream]bool)
+	r.operators, r.textBytes = 0, 0
+	for _, p := range pages {
+		w := &textWriter{}
+		content, err := r.pageContent(p.dict)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.runContent(content, p.resources, w, 0); err != nil {
+			return nil, err
+		}
+		r.textBytes += int64(w.b.Len())
+		out = append(out, w.b.String())
+	}
+	return out, nil
+}
+
+func (r *Reader) pageContent(d Dict) ([]byte, error) {
+	var streams []*Stream
+	switch c := r.resolve(d["Contents"]).(type) {
+	case *Stream:
+		streams = append(streams, c)
+	case Array:
+		for _, item := range c {
+			if s, ok := r.resolve(item).(*Stream); ok {
+				streams = append(streams, s)
+			}
+		}
+	}
+
+	var content []byte
+	for _, s := range streams {
+		data, err := r.decodeStream(s)
+		if err != nil {
+			if errors.Is(err, ErrUnsupportedFilter) {
+				continue
+			}
+			return nil, err
+		}
+		content = append(content, data...)
+		content = append(content, '\n')
+	}
+	return content, nil
+}
+
+func (r *Reader) runContent(content []byte, resources Dict, w *textWriter, depth int) error {
+	if depth > r.maxNesting {
+		return ErrNestingTooDeep
+	}
+	fonts := map[Name]*font{}
+	fontDicts := r.resolveDict(resources["Font"])
+	xobjects := r.resolveDict(resources["XObject"])
+	var current *font
+	var lineY float64
+	haveY := false
+
+	setY := func(y float64) {
+		if haveY && y != lineY {
+			w.pendingNewline = true
+		} else if haveY {
+			w.pendingSpace = true
+		}
+		lineY, haveY = y, true
+	}
+
+	lx := newLexer(content, 0)
+	lx.maxDepth = r.maxNesting
+	var operands []Object
+	for {
+		tok, err := lx.next()
+		if err != nil {
+			// Повреждённый хвост потока не должен лишать нас уже извлечённого текста.
+			return nil
+		}
+		if tok.kind == tokEOF {
+			return nil
+		}
+		if tok.kind != tokKeyword {
+			obj, err := lx.parseFrom(tok, 0)
+			if errors.Is(err, ErrNestingTooDeep) {
+				return err
+			}
+			if err != nil {
+				return nil
+			}
+			operands = append(operands, obj)
+			continue
+		}
+
+		if r.operators++; r.operators > r.maxOperators {
+			return ErrTooManyOperators
+		}
+		if r.textBytes+int64(w.b.Len()) > r.maxTextBytes {
+			return ErrTextTooLarge
+		}
+
+		switch tok.text {
+		case "BT":
+			haveY = false
+			w.pendingSpace = true
+		case "Tf":
+			if len(operands) >= 2 {
+				if name, ok := operands[len(operands)-2].(Name); ok {
+					f, ok := fonts[name]
+					if !ok {
+						f = r.loadFont(r.resolveDict(fontDicts[name]))
+						fonts[name] = f
+					}
+					current = f
+				}
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				ty, _ := toFloat(operands[len(operands)-1])
+				if ty != 0 {
+					w.pendingNewline = true
+					lineY += ty
+				} else {
+					w.pendingSpace = true
+				}
+			}
+		case "Tm":
+			if len(operands) >= 6 {
+				y, _ := toFloat(operands[len(operands)-1])
+				setY(y)
+			}
+		case "T*":
+			w.pendingNewline = true
+		case "Tj":
+			if len(operands) >= 1 {
+				w.write(showString(current, operands[len(operands)-1]))
+			}
+		case "'", "\"":
+			w.pendingNewline = true
+			if len(operands) >= 1 {
+				w.write(showString(current, operands[len(operands)-1]))
+			}
+		case "TJ":
+			if len(operands) >= 1 {
+				arr, _ := operands[len(operands)-1].(Array)
+				for _, item := range arr {
+					if n, ok := toFloat(item); ok {
+						if n < -250 {
+							w.pendingSpace = true
+						}
+						continue
+					}
+					w.write(showString(current, item))
+				}
+			}
+		case "Do":
+			if len(operands) >= 1 {
+				name, _ := operands[len(operands)-1].(Name)
+				if s, ok := r.resolve(xobjects[name]).(*Stream); ok && s.Dict.Name("Subtype") == "Form" {
+					if err := r.runForm(s, resources, w, depth); err != nil {
+						return err
+					}
+				}
+			}
+		case "BI":
+			skipInlineImage(lx)
+		}
+		operands = operands[:0]
+	}
+}
+
+// runForm выполняет форму XObject. Форма, уже выполняемая выше по стеку,
+// пропускается: циклическая ссылка иначе разворачивалась бы до предела вложенности.
+func (r *Reader) runForm(s *Stream, resources Dict, w *textWriter, depth int) error {
+	if r.activeForms[s] {
+		return nil
+	}
+	data, err := r.decodeOnce(s)
+	if err != nil {
+		if errors.Is(err, ErrStreamTooLarge) || errors.Is(err, ErrBudgetExceeded) {
+			return err
+		}
+		// Повреждённая форма пропускается.
+		return nil
+	}
+	formRes := r.resolveDict(s.Dict["Resources"])
+	if formRes == nil {
+		formRes = resources
+	}
+	w.pendingNewline = true
+	r.activeForms[s] = true
+	defer delete(r.activeForms, s)
+	return r.runContent(data, formRes, w, depth+1)
+}
+
+type decodedStream struct {
+	data []byte
+	err  error
+}
+
+// decodeOnce распаковывает поток один раз за извлечение текста: формы и
+// шрифты используются многократно, а в бюджет каждый поток входит однажды.
+func (r *Reader) decodeOnce(s *Stream) ([]byte, error) {
+	d, ok := r.decoded[s]
+	if !ok {
+		d.data, d.err = r.decodeStream(s)
+		r.decoded[s] = d
+	}
+	return d.data, d.err
+}
+
+func showString(f *font, o Object) string {
+	s, ok := o.(String)
+	if !ok {
+		return ""
+	}
+	if f == nil {
+		f = &font{}
+	}
+	return f.decode(s)
+}
+
+// skipInlineImage пропускает двоичные данные встроенного изображения до EI.
+func skipInlineImage(lx *lexer) {
+	for lx.pos+2 < len(lx.data) {
+		if lx.data[lx.pos] == 'E' && lx.data[lx.pos+1] == 'I' &&
+			(lx.pos == 0 || isSpace(lx.data[lx.pos-1])) &&
+			(lx.pos+2 == len(lx.data) || isSpace(lx.data[lx.pos+2])) {
+			lx.pos += 2
+			return
+		}
+		lx.pos++
+	}
+	lx.pos = len(lx.data)
+}
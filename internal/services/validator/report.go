@@ -0,0 +1,164 @@
+package validator
+
+import (
+	"strings"
+
+	"github.com/qnhqn1/file-validator/internal/domain"
+)
+
+const (
+	StatusPassed  = "passed"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+	// StatusWarning — находка не делает документ недопустимым, но попадает в отчёт.
+	StatusWarning = "warning"
+)
+
+const (
+	VerdictValid   = "valid"
+	VerdictInvalid = "invalid"
+	// VerdictResourceLimitExceeded — разбор остановлен лимитом ресурсов,
+	// о содержимом документа ничего не известно.
+	VerdictResourceLimitExceeded = "resource_limit_exceeded"
+)
+
+// Коды стабильны: на них опираются потребители ответа, текст сообщений может меняться.
+const (
+	CodeUnknownProfile        = "unknown_profile"
+	CodeResourceLimitExceeded = "resource_limit_exceeded"
+	CodeDocumentTooLarge      = "document_too_large"
+	CodeInvalidArchive        = "invalid_archive"
+	CodeInvalidPackage        = "invalid_package"
+	CodeInvalidPDF            = "invalid_pdf"
+	CodeEncryptedDocument     = "encrypted_document"
+	CodeMissingPart           = "missing_part"
+	CodeSuspiciousPath        = "suspicious_path"
+	CodeEncryptedEntry        = "encrypted_entry"
+	CodeInconsistentArchive   = "inconsistent_archive"
+	CodeActiveContent         = "active_content"
+	CodeInvalidXML            = "invalid_xml"
+	CodeForbiddenDTD          = "forbidden_dtd"
+	CodeInvalidStructure      = "invalid_structure"
+	CodeNoText                = "no_text"
+	CodeNoLetters             = "no_letters"
+	CodeCyrillicRatioLow      = "cyrillic_ratio_low"
+	CodeLanguageMismatch      = "language_mismatch"
+	CodeNoDates               = "no_dates"
+	CodeDateSpanExceeded      = "date_span_exceeded"
+	CodeAmbiguousDate         = "ambiguous_date"
+	CodeDateOutOfRange        = "date_out_of_range"
+	CodeDateRangeReversed     = "date_range_reversed"
+	CodeDocumentDateNotFound  = "document_date_not_found"
+	CodeFutureDated           = "future_dated"
+	CodeMetadataDatesMismatch = "metadata_dates_mismatch"
+	CodeAuthorNotAllowed      = "author_not_allowed"
+	CodePersonalData          = "personal_data"
+	CodePersonalDataMissing   = "personal_data_missing"
+	CodeRequisiteInvalid      = "requisite_invalid"
+	CodeIncompleteRequisites  = "incomplete_requisites"
+	CodeForbiddenPhrase       = "forbidden_phrase"
+	CodeRequiredPhraseMissing = "required_phrase_missing"
+	CodePendingRevisions      = "pending_revisions"
+	CodeUnresolvedComments    = "unresolved_comments"
+)
+
+type RuleResult struct {
+	Rule    string                 `json:"rule"`
+	Status  string                 `json:"status"`
+	Code    string                 `json:"code,omitempty"`
+	Message string                 `json:"message,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+func passed(rule string, details map[string]interface{}) RuleResult {
+	return RuleResult{Rule: rule, Status: StatusPassed, Details: details}
+}
+
+func failed(rule, code, message string, details map[string]interface{}) RuleResult {
+	return RuleResult{Rule: rule, Status: StatusFailed, Code: code, Message: message, Details: details}
+}
+
+func warning(rule, code, message string, details map[string]interface{}) RuleResult {
+	return RuleResult{Rule: rule, Status: StatusWarning, Code: code, Message: message, Details: details}
+}
+
+func skipped(rule, message string) RuleResult {
+	return RuleResult{Rule: rule, Status: StatusSkipped, Message: message}
+}
+
+type ValidationReport struct {
+	Verdict string       `json:"verdict"`
+	Profile string       `json:"profile"`
+	Format  string       `json:"format"`
+	Results []RuleResult `json:"results"`
+	// Metadata — свойства документа, если формат их хранит и пакет удалось разобрать.
+	Metadata *Metadata `json:"metadata,omitempty"`
+}
+
+func (r *ValidationReport) add(res RuleResult) {
+	r.Results = append(r.Results, res)
+}
+
+func (r *ValidationReport) finish() *ValidationReport {
+	r.Verdict = VerdictValid
+	for _, res := range r.Failures() {
+		if res.Code == CodeResourceLimitExceeded {
+			r.Verdict = VerdictResourceLimitExceeded
+			return r
+		}
+		r.Verdict = VerdictInvalid
+	}
+	return r
+}
+
+func (r *ValidationReport) Valid() bool {
+	return r.Verdict == VerdictValid
+}
+
+func (r *ValidationReport) Failures() []RuleResult {
+	var out []RuleResult
+	for _, res := range r.Results {
+		if res.Status == StatusFailed {
+			out = append(out, res)
+		}
+	}
+	return out
+}
+
+// FirstFailureCode возвращает код первого проваленного правила или пустую строку.
+func (r *ValidationReport) FirstFailureCode() string {
+	if failures := r.Failures(); len(failures) > 0 {
+		return failures[0].Code
+	}
+	return ""
+}
+
+func (r *ValidationReport) Result(rule string) (RuleResult, bool) {
+	for _, res := range r.Results {
+		if res.Rule == rule {
+			return res, true
+		}
+	}
+	return RuleResult{}, false
+}
+
+type ValidationError struct {
+	Report *ValidationReport
+}
+
+func (e *ValidationError) Error() string {
+	failures := e.Report.Failures()
+	msgs := make([]string, 0, len(failures))
+	for _, f := range failures {
+		msgs = append(msgs, f.Message)
+	}
+	subject := "документа"
+	if e.Report.Format != "" {
+		subject = strings.ToUpper(e.Report.Format)
+	}
+	return "Валидация " + subject + " не удалась: " + strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return domain.ErrValidationFailed
+}
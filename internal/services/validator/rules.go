@@ -0,0 +1,177 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+type Rule interface {
+	Name() string
+	Check(doc *Document) RuleResult
+}
+
+// RuleFactory строит правило из настроек профиля.
+// Фабрика возвращает nil, если правило выключено настройками.
+type RuleFactory func(cfg config.ProfileConfig) (Rule, error)
+
+var registry = map[string]RuleFactory{}
+
+// RegisterRule добавляет правило в реестр. Вызывается из init(), поэтому
+// повторная регистрация считается ошибкой программиста.
+func RegisterRule(name string, factory RuleFactory) {
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("правило %s уже зарегистрировано", name))
+	}
+	registry[name] = factory
+}
+
+// DefaultRules — порядок проверок, если в профиле список правил не задан.
+var DefaultRules = []string{
+	ruleDocumentSize,
+	ruleRequiredParts,
+	ruleSafePaths,
+	ruleZipIntegrity,
+	rulePackageConsistency,
+	ruleActiveContent,
+	ruleXMLWellFormed,
+	ruleMainPartXML,
+	ruleCyrillicRatio,
+	ruleLanguage,
+	ruleDateSpan,
+	ruleDateConstraints,
+	ruleMetadataDates,
+	ruleMetadataAuthor,
+	rulePII,
+	ruleRequisites,
+	rulePhrases,
+	ruleReview,
+}
+
+const ruleProfile = "profile"
+
+type Engine struct {
+	profiles map[string][]Rule
+	tenants  map[string]string
+	limits   config.LimitsConfig
+}
+
+func NewEngine(cfg config.ValidationConfig) (*Engine, error) {
+	e := &Engine{profiles: make(map[string][]Rule), tenants: cfg.Tenants, limits: cfg.Limits.WithDefaults()}
+
+	names := cfg.ProfileNames()
+	sort.Strings(names)
+	for _, name := range names {
+		profile, _ := cfg.Profile(name)
+		rules, err := buildRules(profile)
+		if err != nil {
+			return nil, fmt.Errorf("профиль %s: %w", name, err)
+		}
+		e.profiles[name] = rules
+	}
+
+	for tenant, profile := range cfg.Tenants {
+		if _, ok := e.profiles[profile]; !ok {
+			return nil, fmt.Errorf("арендатор %s ссылается на неизвестный профиль %s", tenant, profile)
+		}
+	}
+	return e, nil
+}
+
+func buildRules(profile config.ProfileConfig) ([]Rule, error) {
+	names := profile.Rules
+	if len(names) == 0 {
+		names = DefaultRules
+	}
+
+	seen := make(map[string]bool, len(names))
+	rules := make([]Rule, 0, len(names))
+	for _, name := range names {
+		factory, ok := registry[name]
+		if !ok {
+			return nil, fmt.Errorf("неизвестное правило валидации: %s", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("правило валидации указано дважды: %s", name)
+		}
+		seen[name] = true
+
+		rule, err := factory(profile)
+		if err != nil {
+			return nil, fmt.Errorf("настроить правило %s: %w", name, err)
+		}
+		if rule == nil {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// resolveProfile выбирает профиль: явно указанный, затем по арендатору, иначе профиль по умолчанию.
+// Явно указанный, но неизвестный профиль — ошибка, чтобы не принять документ по чужим критериям.
+func (e *Engine) resolveProfile(profile, tenant string) (string, bool) {
+	if profile != "" {
+		_, ok := e.profiles[profile]
+		return profile, ok
+	}
+	if name, ok := e.tenants[tenant]; ok && tenant != "" {
+		return name, true
+	}
+	return config.DefaultProfileName, true
+}
+
+func (e *Engine) RuleNames(profile string) []string {
+	rules := e.profiles[profile]
+	names := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		names = append(names, rule.Name())
+	}
+	return names
+}
+
+func (e *Engine) Run(req Request) *ValidationReport {
+	report := &ValidationReport{}
+
+	profile, ok := e.resolveProfile(req.Profile, req.TenantID)
+	report.Profile = profile
+	if !ok {
+		report.add(failed(ruleProfile, CodeUnknownProfile,
+			fmt.Sprintf("неизвестный профиль валидации: %s", profile), map[string]interface{}{"profile": profile}))
+		return report.finish()
+	}
+
+	budget := newReadBudget(e.limits)
+	if size := int64(len(req.Payload)); size > e.limits.MaxObjectBytes {
+		report.add(budget.exceed(limitObjectBytes, "", e.limits.MaxObjectBytes,
+			"размер объекта %d байт превышает лимит %d байт", size, e.limits.MaxObjectBytes).result())
+		return report.finish()
+	}
+
+	report.Format = detectFormat(req.Payload, budget)
+	doc, res := formatHandlers[report.Format](req.Payload, budget)
+	if budget.violation != nil {
+		// Ошибки частей после нарушения — его следствие, в отчёт идёт только само нарушение.
+		report.add(budget.violation.result())
+		return report.finish()
+	}
+	report.add(res)
+	if res.Status == StatusFailed {
+		return report.finish()
+	}
+	if !doc.Metadata.IsZero() {
+		report.Metadata = &doc.Metadata
+	}
+	doc.DocumentDate = req.DocumentDate
+
+	for _, rule := range e.profiles[profile] {
+		report.add(rule.Check(doc))
+	}
+	// Правила тоже читают части через бюджет; их результаты остаются в отчёте,
+	// но итог определяет нарушение лимита.
+	if budget.violation != nil {
+		report.add(budget.violation.result())
+	}
+	return report.finish()
+}
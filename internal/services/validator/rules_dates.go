@@ -0,0 +1,180 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+const ruleDateConstraints = "date_constraints"
+
+func init() {
+	RegisterRule(ruleDateConstraints, newDateConstraintsRule)
+}
+
+// Слова, открывающие и закрывающие диапазон дат: «от … до …», «с … по …».
+var (
+	rangeOpeners = map[string]bool{"от": true, "с": true, "со": true}
+	rangeClosers = map[string]bool{"до": true, "по": true}
+	yearSuffixes = map[string]bool{"г": true, "года": true, "гг": true}
+)
+
+func flagSet(flag *bool) bool {
+	return flag != nil && *flag
+}
+
+type dateConstraintsRule struct {
+	parts             []string
+	dates             dateExtractor
+	window            config.CalendarSpan
+	noFuture          bool
+	matchDocumentDate bool
+	orderedRanges     bool
+}
+
+// newDateConstraintsRule включает правило, только если задано хотя бы одно ограничение;
+// dates.enabled: false выключает его вместе с date_span.
+func newDateConstraintsRule(cfg config.ProfileConfig) (Rule, error) {
+	d := cfg.Dates
+	if !ruleEnabled(d.Enabled) {
+		return nil, nil
+	}
+	r := dateConstraintsRule{
+		parts:             d.Parts,
+		window:            d.Window,
+		noFuture:          flagSet(d.NoFuture),
+		matchDocumentDate: flagSet(d.MatchDocumentDate),
+		orderedRanges:     flagSet(d.OrderedRanges),
+	}
+	if r.window.IsZero() && !r.noFuture && !r.matchDocumentDate && !r.orderedRanges {
+		return nil, nil
+	}
+	if r.window.Years < 0 || r.window.Months < 0 || r.window.Days < 0 {
+		return nil, fmt.Errorf("window не может быть отрицательным: %s", r.window)
+	}
+	if err := checkSources(d.Parts); err != nil {
+		return nil, err
+	}
+	dates, err := newDateExtractor(d)
+	if err != nil {
+		return nil, err
+	}
+	r.dates = dates
+	return r, nil
+}
+
+func (dateConstraintsRule) Name() string { return ruleDateConstraints }
+
+// Check проверяет ограничения по порядку и сообщает о первом нарушенном.
+// Неоднозначные даты сравниваются в прочтении по локали, кроме сверки с
+// document_date, где подходит любое прочтение.
+func (r dateConstraintsRule) Check(doc *Document) RuleResult {
+	text := doc.TextFrom(r.parts)
+	matches := r.dates.find(text)
+	details := map[string]interface{}{"dates_found": len(matches)}
+
+	if r.orderedRanges {
+		if reversed := reversedRanges(text, matches); len(reversed) > 0 {
+			details["ranges"] = reversed
+			return failed(ruleDateConstraints, CodeDateRangeReversed,
+				fmt.Sprintf("диапазон дат начинается позже, чем заканчивается: %s … %s", reversed[0]["from"], reversed[0]["to"]), details)
+		}
+	}
+
+	now := clock()
+	if r.noFuture {
+		limit := truncateToDay(now.Add(clockSkew))
+		if late := filterDates(matches, func(d time.Time) bool { return d.After(limit) }); len(late) > 0 {
+			details["future_dates"] = dateDetails(late)
+			return failed(ruleDateConstraints, CodeFutureDated,
+				fmt.Sprintf("дата «%s» в тексте в будущем", late[0].Text), details)
+		}
+	}
+
+	if !r.window.IsZero() {
+		today := truncateToDay(now)
+		earliest := today.AddDate(-r.window.Years, -r.window.Months, -r.window.Days)
+		latest := r.window.AddTo(today)
+		outside := filterDates(matches, func(d time.Time) bool { return d.Before(earliest) || d.After(latest) })
+		details["window"] = r.window.String()
+		if len(outside) > 0 {
+			details["out_of_window"] = dateDetails(outside)
+			return failed(ruleDateConstraints, CodeDateOutOfRange,
+				fmt.Sprintf("дата «%s» отстоит от даты проверки более чем на %s", outside[0].Text, formatSpanRu(r.window)), details)
+		}
+	}
+
+	if r.matchDocumentDate {
+		if doc.DocumentDate.IsZero() {
+			if r.window.IsZero() && !r.noFuture && !r.orderedRanges {
+				return skipped(ruleDateConstraints, "дата документа в запросе не указана")
+			}
+			return passed(ruleDateConstraints, details)
+		}
+		declared := truncateToDay(doc.DocumentDate)
+		details["document_date"] = declared.Format("2006-01-02")
+		if !containsDate(matches, declared) {
+			return failed(ruleDateConstraints, CodeDocumentDateNotFound,
+				fmt.Sprintf("в тексте нет даты документа %s", declared.Format("02.01.2006")), details)
+		}
+	}
+	return passed(ruleDateConstraints, details)
+}
+
+func filterDates(matches []dateMatch, keep func(time.Time) bool) []dateMatch {
+	var out []dateMatch
+	for _, m := range matches {
+		if keep(m.Date) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func containsDate(matches []dateMatch, day time.Time) bool {
+	for _, m := range matches {
+		if m.Date.Equal(day) || (m.Ambiguous && m.Alternative.Equal(day)) {
+			return true
+		}
+	}
+	return false
+}
+
+// reversedRanges находит соседние даты вида «от X до Y», где X позже Y. Между
+// датами допускаются только «г.»/«года», запятые и закрывающее слово.
+func reversedRanges(text string, matches []dateMatch) []map[string]interface{} {
+	var out []map[string]interface{}
+	for i := 0; i+1 < len(matches); i++ {
+		from, to := matches[i], matches[i+1]
+		if !rangeOpeners[lastWord(text[:from.start])] || !closesRange(text[from.end:to.start]) {
+			continue
+		}
+		if from.Date.After(to.Date) {
+			out = append(out, map[string]interface{}{"from": from.Text, "to": to.Text, "offset": from.Offset})
+		}
+	}
+	return out
+}
+
+func lastWord(s string) string {
+	s = strings.TrimRightFunc(s, unicode.IsSpace)
+	if i := strings.LastIndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) }); i >= 0 {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		s = s[i+size:]
+	}
+	return strings.ToLower(s)
+}
+
+func closesRange(between string) bool {
+	words := strings.FieldsFunc(strings.ToLower(between), func(r rune) bool {
+		return unicode.IsSpace(r) || r == ',' || r == '.'
+	})
+	if len(words) == 2 && yearSuffixes[words[0]] {
+		words = words[1:]
+	}
+	return len(words) == 1 && rangeClosers[words[0]]
+}
@@ -0,0 +1,99 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+func TestDateConstraints_DisabledWithoutConstraints(t *testing.T) {
+	engine, err := profileEngine(config.ProfileConfig{Rules: []string{"date_constraints"}})
+	require.NoError(t, err)
+	require.Empty(t, engine.RuleNames(config.DefaultProfileName))
+}
+
+func TestDateConstraints_NoFuture(t *testing.T) {
+	withClock(t, time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	enabled := true
+	cfg := config.DateRuleConfig{NoFuture: &enabled}
+
+	res := ruleResult(t, config.ProfileConfig{Dates: cfg}, "date_constraints", Request{Payload: createDOCXWithText("Договор от 01.06.2024, акт от 02.06.2024")})
+	require.Equal(t, StatusPassed, res.Status)
+
+	res = ruleResult(t, config.ProfileConfig{Dates: cfg}, "date_constraints", Request{Payload: createDOCXWithText("Договор от 01.06.2024, оплата до 15 июля 2024 г.")})
+	require.Equal(t, CodeFutureDated, res.Code)
+	require.Contains(t, res.Message, "15 июля 2024 г.")
+}
+
+func TestDateConstraints_Window(t *testing.T) {
+	withClock(t, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	cfg := config.DateRuleConfig{Window: config.CalendarSpan{Years: 2}}
+
+	res := ruleResult(t, config.ProfileConfig{Dates: cfg}, "date_constraints", Request{Payload: createDOCXWithText("с 01.06.2022 по 01.06.2026")})
+	require.Equal(t, StatusPassed, res.Status)
+
+	res = ruleResult(t, config.ProfileConfig{Dates: cfg}, "date_constraints", Request{Payload: createDOCXWithText("Приказ от 31.05.2022")})
+	require.Equal(t, CodeDateOutOfRange, res.Code)
+	require.Contains(t, res.Message, "2 года")
+}
+
+func TestDateConstraints_MatchDocumentDate(t *testing.T) {
+	enabled := true
+	cfg := config.DateRuleConfig{MatchDocumentDate: &enabled}
+	payload := createDOCXWithText("Договор № 5 от «01» марта 2024 года")
+
+	res := ruleResult(t, config.ProfileConfig{Dates: cfg}, "date_constraints", Request{Payload: payload, DocumentDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)})
+	require.Equal(t, StatusPassed, res.Status)
+	require.Equal(t, "2024-03-01", res.Details["document_date"])
+
+	res = ruleResult(t, config.ProfileConfig{Dates: cfg}, "date_constraints", Request{Payload: payload, DocumentDate: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)})
+	require.Equal(t, CodeDocumentDateNotFound, res.Code)
+	require.Contains(t, res.Message, "02.03.2024")
+
+	res = ruleResult(t, config.ProfileConfig{Dates: cfg}, "date_constraints", Request{Payload: payload})
+	require.Equal(t, StatusSkipped, res.Status)
+
+	// У неоднозначной даты подходит любое прочтение.
+	res = ruleResult(t, config.ProfileConfig{Dates: cfg}, "date_constraints", Request{Payload: createDOCXWithText("Акт 03/04/2024"), DocumentDate: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)})
+	require.Equal(t, StatusPassed, res.Status)
+}
+
+func TestDateConstraints_OrderedRanges(t *testing.T) {
+	enabled := true
+	cfg := config.DateRuleConfig{OrderedRanges: &enabled}
+
+	for _, text := range []string{
+		"Срок действия от 01.02.2024 до 01.03.2024",
+		"Период с 1 марта 2024 г. по 31 марта 2024 г.",
+		"Договор от 01.03.2024. Оплата до 01.02.2024",
+		"Акт от 01.03.2024 и приказ 01.02.2024",
+	} {
+		res := ruleResult(t, config.ProfileConfig{Dates: cfg}, "date_constraints", Request{Payload: createDOCXWithText(text)})
+		require.Equal(t, StatusPassed, res.Status, text)
+	}
+
+	for _, text := range []string{
+		"Срок действия от 01.03.2024 до 01.02.2024",
+		"Период с 31 марта 2024 года по 1 марта 2024 года",
+		"Аренда с 2024-05-01 г., по 2024-04-01",
+	} {
+		res := ruleResult(t, config.ProfileConfig{Dates: cfg}, "date_constraints", Request{Payload: createDOCXWithText(text)})
+		require.Equal(t, CodeDateRangeReversed, res.Code, text)
+	}
+}
+
+func TestParseDocumentDate(t *testing.T) {
+	d, err := ParseDocumentDate("2024-03-01")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), d)
+
+	d, err = ParseDocumentDate("2024-03-01T23:30:00+03:00")
+	require.NoError(t, err)
+	require.Equal(t, "2024-03-01", truncateToDay(d).Format("2006-01-02"))
+
+	_, err = ParseDocumentDate("01.03.2024")
+	require.Error(t, err)
+}
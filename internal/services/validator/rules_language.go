@@ -0,0 +1,164 @@
+package validator
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/qnhqn1/file-validator/config"
+	"github.com/qnhqn1/file-validator/internal/services/validator/langid"
+)
+
+const ruleLanguage = "language"
+
+const defaultLanguageMinPercent = 90
+
+// minChunkLetters — сколько букв нужно для уверенного определения языка:
+// короткие абзацы («Подпись», «Итого») объединяются с соседними.
+const minChunkLetters = 40
+
+// maxReportedParagraphs ограничивает список абзацев на другом языке в details:
+// распределение по всем абзацам есть в details.paragraphs.
+const maxReportedParagraphs = 20
+
+func init() {
+	RegisterRule(ruleLanguage, newLanguageRule)
+}
+
+type languageRule struct {
+	primary    string
+	minPercent float64
+	parts      []string
+}
+
+func newLanguageRule(cfg config.ProfileConfig) (Rule, error) {
+	if !ruleEnabled(cfg.Language.Enabled) || cfg.Language.Primary == "" {
+		return nil, nil
+	}
+	if !langid.IsSupported(cfg.Language.Primary) {
+		return nil, fmt.Errorf("неизвестный язык %q, допустимы %s", cfg.Language.Primary, strings.Join(langid.Supported(), ", "))
+	}
+	minPercent := cfg.Language.MinPercent
+	if minPercent == 0 {
+		minPercent = defaultLanguageMinPercent
+	}
+	if minPercent < 0 || minPercent > 100 {
+		return nil, fmt.Errorf("minPercent должен быть в диапазоне (0, 100], получено %g", minPercent)
+	}
+	if err := checkSources(cfg.Language.Parts); err != nil {
+		return nil, err
+	}
+	return languageRule{primary: cfg.Language.Primary, minPercent: minPercent, parts: cfg.Language.Parts}, nil
+}
+
+func (languageRule) Name() string { return ruleLanguage }
+
+// languageChunk — абзац или несколько подряд идущих коротких абзацев с общим языком.
+// Index — номер первого абзаца среди отобранных источников.
+type languageChunk struct {
+	index, count, letters int
+	part, lang            string
+}
+
+// Check определяет язык по абзацам и требует, чтобы на основной язык
+// приходилась заданная доля букв текста. В details.paragraphs — число абзацев
+// каждого языка; короткий абзац получает язык группы, в которую он попал.
+func (r languageRule) Check(doc *Document) RuleResult {
+	paragraphs := doc.ParagraphsFrom(r.parts)
+	if strings.TrimSpace(joinParagraphs(paragraphs)) == "" {
+		return failed(ruleLanguage, CodeNoText, "в документе не найден текст", nil)
+	}
+	chunks := languageChunks(paragraphs)
+	if len(chunks) == 0 {
+		return failed(ruleLanguage, CodeNoLetters, "в документе не найдены буквы", nil)
+	}
+
+	letters := make(map[string]int)
+	counts := make(map[string]int)
+	total := 0
+	var foreign []map[string]interface{}
+	for _, c := range chunks {
+		letters[c.lang] += c.letters
+		counts[c.lang] += c.count
+		total += c.letters
+		if c.lang != r.primary && len(foreign) < maxReportedParagraphs {
+			entry := map[string]interface{}{"index": c.index, "part": c.part, "language": c.lang, "letters": c.letters}
+			if c.count > 1 {
+				entry["count"] = c.count
+			}
+			foreign = append(foreign, entry)
+		}
+	}
+
+	shares := make(map[string]float64, len(letters))
+	dominant := r.primary
+	for lang, n := range letters {
+		shares[lang] = math.Round(float64(n)/float64(total)*10000) / 100
+		if n > letters[dominant] || (n == letters[dominant] && lang < dominant) {
+			dominant = lang
+		}
+	}
+	details := map[string]interface{}{
+		"primary":           r.primary,
+		"required_percent":  r.minPercent,
+		"languages":         shares,
+		"paragraphs":        counts,
+		"min_chunk_letters": minChunkLetters,
+		"chunks":            len(chunks),
+	}
+	if len(foreign) > 0 {
+		details["foreign_paragraphs"] = foreign
+	}
+
+	share := float64(letters[r.primary]) / float64(total) * 100
+	if share < r.minPercent {
+		return failed(ruleLanguage, CodeLanguageMismatch,
+			fmt.Sprintf("доля текста на языке %s — %.2f%%, требуется %g%%; преобладает %s", r.primary, share, r.minPercent, dominant), details)
+	}
+	return passed(ruleLanguage, details)
+}
+
+// languageChunks определяет язык каждого достаточно длинного абзаца; короткие
+// копятся, пока не наберётся minChunkLetters букв. Остаток в конце определяется как есть.
+func languageChunks(paragraphs []Paragraph) []languageChunk {
+	var chunks []languageChunk
+	var pending strings.Builder
+	current := languageChunk{index: -1}
+	flush := func() {
+		if current.letters > 0 {
+			current.lang = langid.Detect(pending.String())
+			chunks = append(chunks, current)
+		}
+		pending.Reset()
+		current = languageChunk{index: -1}
+	}
+	for i, para := range paragraphs {
+		n := countLetters(para.Text)
+		if n == 0 {
+			continue
+		}
+		if current.index < 0 {
+			current.index, current.part = i, para.Part
+		}
+		pending.WriteString(para.Text)
+		pending.WriteByte('\n')
+		current.letters += n
+		current.count++
+		if current.letters >= minChunkLetters {
+			flush()
+		}
+	}
+	flush()
+	return chunks
+}
+
+func countLetters(s string) int {
+	n := 0
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			n++
+		}
+	}
+	return n
+}
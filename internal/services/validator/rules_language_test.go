@@ -0,0 +1,88 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+func createDOCXWithParagraphs(paragraphs ...string) []byte {
+	var body strings.Builder
+	for _, p := range paragraphs {
+		body.WriteString("<w:p><w:r><w:t>" + p + "</w:t></w:r></w:p>")
+	}
+	return createDOCX(body.String(), nil)
+}
+
+const (
+	ruParagraph = "Арендатор обязан своевременно вносить арендную плату и содержать помещение в надлежащем состоянии."
+	ukParagraph = "Орендар зобов'язаний своєчасно вносити орендну плату й утримувати приміщення в належному стані."
+	bgParagraph = "Наемателят е длъжен своевременно да плаща наема и да поддържа помещението в добро състояние."
+)
+
+func TestLanguage_Russian(t *testing.T) {
+	res := ruleResult(t, config.ProfileConfig{Language: config.LanguageRuleConfig{Primary: "ru"}}, "language",
+		Request{Payload: createDOCXWithParagraphs(ruParagraph, "Подпись", "Дата", ruParagraph)})
+	require.Equal(t, StatusPassed, res.Status)
+	require.Equal(t, map[string]float64{"ru": 100}, res.Details["languages"])
+	require.Equal(t, map[string]int{"ru": 4}, res.Details["paragraphs"])
+}
+
+func TestLanguage_CyrillicButNotRussian(t *testing.T) {
+	for _, para := range []string{ukParagraph, bgParagraph} {
+		// cyrillic_ratio такой текст пропускает, language — нет.
+		cyrillic, err := profileEngine(config.ProfileConfig{Rules: []string{"cyrillic_ratio"}})
+		require.NoError(t, err)
+		require.True(t, cyrillic.Run(Request{Payload: createDOCXWithText(para)}).Valid())
+
+		res := ruleResult(t, config.ProfileConfig{Language: config.LanguageRuleConfig{Primary: "ru"}}, "language", Request{Payload: createDOCXWithText(para)})
+		require.Equal(t, CodeLanguageMismatch, res.Code, para)
+	}
+}
+
+func TestLanguage_ReportsForeignParagraphs(t *testing.T) {
+	payload := createDOCXWithParagraphs(ruParagraph, ruParagraph, ukParagraph)
+
+	res := ruleResult(t, config.ProfileConfig{Language: config.LanguageRuleConfig{Primary: "ru"}}, "language", Request{Payload: payload})
+	require.Equal(t, CodeLanguageMismatch, res.Code)
+	require.Contains(t, res.Message, "преобладает ru")
+	require.Equal(t, []map[string]interface{}{
+		{"index": 2, "part": "word/document.xml", "language": "uk", "letters": countLetters(ukParagraph)},
+	}, res.Details["foreign_paragraphs"])
+	require.Equal(t, map[string]int{"ru": 2, "uk": 1}, res.Details["paragraphs"])
+
+	// Короткие абзацы определяются группой и учитываются в её языке.
+	res = ruleResult(t, config.ProfileConfig{Language: config.LanguageRuleConfig{Primary: "ru"}}, "language",
+		Request{Payload: createDOCXWithParagraphs("Подпись", "Итого", ruParagraph)})
+	require.Equal(t, map[string]int{"ru": 3}, res.Details["paragraphs"])
+	require.Equal(t, 1, res.Details["chunks"])
+
+	res = ruleResult(t, config.ProfileConfig{Language: config.LanguageRuleConfig{Primary: "ru", MinPercent: 60}}, "language", Request{Payload: payload})
+	require.Equal(t, StatusPassed, res.Status)
+}
+
+func TestLanguage_Transliteration(t *testing.T) {
+	payload := createDOCXWithText("Arendator obyazan svoevremenno vnosit arendnuyu platu i soderzhat pomeshchenie v nadlezhashchem sostoyanii.")
+	res := ruleResult(t, config.ProfileConfig{Language: config.LanguageRuleConfig{Primary: "ru"}}, "language", Request{Payload: payload})
+	require.Equal(t, CodeLanguageMismatch, res.Code)
+	require.Contains(t, res.Message, "преобладает ru-Latn")
+}
+
+func TestLanguage_NoLetters(t *testing.T) {
+	res := ruleResult(t, config.ProfileConfig{Language: config.LanguageRuleConfig{Primary: "ru"}}, "language", Request{Payload: createDOCXWithText("12345 — 67")})
+	require.Equal(t, CodeNoLetters, res.Code)
+}
+
+func TestLanguage_Config(t *testing.T) {
+	engine, err := profileEngine(config.ProfileConfig{Rules: []string{"language"}})
+	require.NoError(t, err)
+	require.Empty(t, engine.RuleNames(config.DefaultProfileName))
+
+	_, err = profileEngine(config.ProfileConfig{Language: config.LanguageRuleConfig{Primary: "kk"}})
+	require.Error(t, err)
+	_, err = profileEngine(config.ProfileConfig{Language: config.LanguageRuleConfig{Primary: "ru", MinPercent: 101}})
+	require.Error(t, err)
+}
@@ -0,0 +1,145 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+const (
+	ruleMetadataDates  = "metadata_dates"
+	ruleMetadataAuthor = "metadata_author"
+)
+
+var defaultMaxAfterCreated = config.CalendarSpan{Years: 3}
+
+func init() {
+	RegisterRule(ruleMetadataDates, newMetadataDatesRule)
+	RegisterRule(ruleMetadataAuthor, newMetadataAuthorRule)
+}
+
+type metadataDatesRule struct {
+	maxAfterCreated config.CalendarSpan
+	dates           dateExtractor
+}
+
+// newMetadataDatesRule включает правило, только если в профиле задан metadata.enabled.
+func newMetadataDatesRule(cfg config.ProfileConfig) (Rule, error) {
+	if !flagSet(cfg.Metadata.Enabled) {
+		return nil, nil
+	}
+	span := cfg.Metadata.MaxAfterCreated
+	if span.IsZero() {
+		span = defaultMaxAfterCreated
+	}
+	if span.Years < 0 || span.Months < 0 || span.Days < 0 {
+		return nil, fmt.Errorf("maxAfterCreated не может быть отрицательным: %s", span)
+	}
+	dates, err := newDateExtractor(cfg.Dates)
+	if err != nil {
+		return nil, err
+	}
+	return metadataDatesRule{maxAfterCreated: span, dates: dates}, nil
+}
+
+func (metadataDatesRule) Name() string { return ruleMetadataDates }
+
+// Check сверяет даты создания и изменения из свойств документа с текущим
+// временем, друг с другом и с датами в тексте.
+func (r metadataDatesRule) Check(doc *Document) RuleResult {
+	created, modified := doc.Metadata.Created, doc.Metadata.Modified
+	if created == nil && modified == nil {
+		return skipped(ruleMetadataDates, "в свойствах документа нет дат создания и изменения")
+	}
+
+	details := map[string]interface{}{"max_after_created": r.maxAfterCreated.String()}
+	if created != nil {
+		details["created"] = created.Format(time.RFC3339)
+	}
+	if modified != nil {
+		details["modified"] = modified.Format(time.RFC3339)
+	}
+
+	limit := clock().Add(clockSkew)
+	for _, field := range []struct {
+		name string
+		at   *time.Time
+	}{{"создания", created}, {"изменения", modified}} {
+		if field.at != nil && field.at.After(limit) {
+			return failed(ruleMetadataDates, CodeFutureDated,
+				fmt.Sprintf("дата %s документа %s в будущем", field.name, field.at.Format("02.01.2006 15:04")), details)
+		}
+	}
+	if created != nil && modified != nil && modified.Before(created.Add(-clockSkew)) {
+		return failed(ruleMetadataDates, CodeMetadataDatesMismatch,
+			fmt.Sprintf("дата изменения %s раньше даты создания %s", modified.Format("02.01.2006"), created.Format("02.01.2006")), details)
+	}
+
+	if created != nil {
+		// Даты текста — без времени, поэтому сравниваются с днём создания.
+		latest := r.maxAfterCreated.AddTo(truncateToDay(*created))
+		var late []dateMatch
+		for _, m := range r.dates.find(doc.Text) {
+			if m.Date.After(latest) {
+				late = append(late, m)
+			}
+		}
+		if len(late) > 0 {
+			details["late_dates"] = dateDetails(late)
+			return failed(ruleMetadataDates, CodeMetadataDatesMismatch,
+				fmt.Sprintf("дата «%s» в тексте позже даты создания документа %s более чем на %s",
+					late[0].Text, created.Format("02.01.2006"), formatSpanRu(r.maxAfterCreated)), details)
+		}
+	}
+	return passed(ruleMetadataDates, details)
+}
+
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+type metadataAuthorRule struct {
+	authors   []string
+	companies []string
+}
+
+// newMetadataAuthorRule включает правило, только если в профиле задан хотя бы один список.
+func newMetadataAuthorRule(cfg config.ProfileConfig) (Rule, error) {
+	if len(cfg.Metadata.Authors) == 0 && len(cfg.Metadata.Companies) == 0 {
+		return nil, nil
+	}
+	return metadataAuthorRule{authors: cfg.Metadata.Authors, companies: cfg.Metadata.Companies}, nil
+}
+
+func (metadataAuthorRule) Name() string { return ruleMetadataAuthor }
+
+// Check требует, чтобы автор и организация из свойств были в списках профиля.
+// Пустое свойство при заданном списке тоже нарушение: иначе проверку обходит
+// простое удаление свойства.
+func (r metadataAuthorRule) Check(doc *Document) RuleResult {
+	details := map[string]interface{}{"author": doc.Metadata.Author, "company": doc.Metadata.Company}
+	if len(r.authors) > 0 && !inAllowList(doc.Metadata.Author, r.authors) {
+		return failed(ruleMetadataAuthor, CodeAuthorNotAllowed,
+			fmt.Sprintf("автор документа %q не входит в список допустимых", doc.Metadata.Author), details)
+	}
+	if len(r.companies) > 0 && !inAllowList(doc.Metadata.Company, r.companies) {
+		return failed(ruleMetadataAuthor, CodeAuthorNotAllowed,
+			fmt.Sprintf("организация документа %q не входит в список допустимых", doc.Metadata.Company), details)
+	}
+	return passed(ruleMetadataAuthor, details)
+}
+
+func inAllowList(value string, allowed []string) bool {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return false
+	}
+	for _, a := range allowed {
+		if strings.EqualFold(strings.TrimSpace(a), value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+// createDOCXWithProps собирает DOCX с текстом и свойствами; пустые значения в части свойств не попадают.
+func createDOCXWithProps(text, created, modified, author, company string) []byte {
+	core := `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/">`
+	if author != "" {
+		core += `<dc:creator>` + author + `</dc:creator>`
+	}
+	if created != "" {
+		core += `<dcterms:created>` + created + `</dcterms:created>`
+	}
+	if modified != "" {
+		core += `<dcterms:modified>` + modified + `</dcterms:modified>`
+	}
+	app := `<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties">`
+	if company != "" {
+		app += `<Company>` + company + `</Company>`
+	}
+	return createDOCX(`<w:p><w:r><w:t>`+text+`</w:t></w:r></w:p>`, map[string]string{
+		"docProps/core.xml": core + `</cp:coreProperties>`,
+		"docProps/app.xml":  app + `</Properties>`,
+	})
+}
+
+func withClock(t *testing.T, now time.Time) {
+	t.Helper()
+	prev := clock
+	clock = func() time.Time { return now }
+	t.Cleanup(func() { clock = prev })
+}
+
+// metadataDatesProfile включает metadata_dates: без Enabled правило выключено.
+func metadataDatesProfile(span config.CalendarSpan) config.ProfileConfig {
+	enabled := true
+	return config.ProfileConfig{Metadata: config.MetadataRuleConfig{Enabled: &enabled, MaxAfterCreated: span}}
+}
+
+func TestMetadataDates_Passes(t *testing.T) {
+	withClock(t, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	res := ruleResult(t, metadataDatesProfile(config.CalendarSpan{}), "metadata_dates", Request{Payload: createDOCXWithMetadata()})
+	require.Equal(t, StatusPassed, res.Status)
+	require.Equal(t, "2024-02-01T09:30:00Z", res.Details["created"])
+}
+
+func TestMetadataDates_FutureDated(t *testing.T) {
+	withClock(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+	res := ruleResult(t, metadataDatesProfile(config.CalendarSpan{}), "metadata_dates", Request{Payload: createDOCXWithMetadata()})
+	require.Equal(t, StatusFailed, res.Status)
+	require.Equal(t, CodeFutureDated, res.Code)
+	require.Contains(t, res.Message, "дата создания")
+}
+
+func TestMetadataDates_ClockSkewTolerated(t *testing.T) {
+	withClock(t, time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC))
+	res := ruleResult(t, metadataDatesProfile(config.CalendarSpan{}), "metadata_dates", Request{Payload: createDOCXWithMetadata()})
+	require.Equal(t, StatusPassed, res.Status)
+}
+
+func TestMetadataDates_ModifiedBeforeCreated(t *testing.T) {
+	withClock(t, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	payload := createDOCXWithProps("Договор", "2024-03-01T00:00:00Z", "2024-01-10T00:00:00Z", "", "")
+	res := ruleResult(t, metadataDatesProfile(config.CalendarSpan{}), "metadata_dates", Request{Payload: payload})
+	require.Equal(t, CodeMetadataDatesMismatch, res.Code)
+	require.Contains(t, res.Message, "раньше даты создания")
+}
+
+func TestMetadataDates_TextDateTooLate(t *testing.T) {
+	withClock(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	payload := createDOCXWithProps("Срок действия до 15.03.2027", "2024-03-01T10:00:00Z", "", "", "")
+
+	res := ruleResult(t, metadataDatesProfile(config.CalendarSpan{}), "metadata_dates", Request{Payload: payload})
+	require.Equal(t, CodeMetadataDatesMismatch, res.Code)
+	require.Equal(t, []map[string]interface{}{{"text": "15.03.2027", "date": "2027-03-15", "offset": 17}}, res.Details["late_dates"])
+
+	res = ruleResult(t, metadataDatesProfile(config.CalendarSpan{Years: 5}), "metadata_dates", Request{Payload: payload})
+	require.Equal(t, StatusPassed, res.Status)
+}
+
+func TestMetadataDates_BoundaryIsInclusive(t *testing.T) {
+	withClock(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	payload := createDOCXWithProps("Срок действия до 01.03.2027", "2024-03-01T23:00:00+03:00", "", "", "")
+	res := ruleResult(t, metadataDatesProfile(config.CalendarSpan{}), "metadata_dates", Request{Payload: payload})
+	require.Equal(t, StatusPassed, res.Status)
+}
+
+func TestMetadataDates_SkippedWithoutDates(t *testing.T) {
+	res := ruleResult(t, metadataDatesProfile(config.CalendarSpan{}), "metadata_dates", Request{Payload: createDOCXWithText("Договор от 01.02.2024")})
+	require.Equal(t, StatusSkipped, res.Status)
+}
+
+func TestMetadataDates_NegativeSpanRejected(t *testing.T) {
+	_, err := profileEngine(metadataDatesProfile(config.CalendarSpan{Years: -1}))
+	require.Error(t, err)
+}
+
+func TestMetadataAuthor_AllowList(t *testing.T) {
+	profile := config.ProfileConfig{Metadata: config.MetadataRuleConfig{
+		Authors:   []string{"иванов и.и.", "Сидоров С.С."},
+		Companies: []string{"ООО «Ромашка»"},
+	}}
+
+	res := ruleResult(t, profile, "metadata_author", Request{Payload: createDOCXWithProps("Договор", "", "", " Иванов И.И. ", "ООО «Ромашка»")})
+	require.Equal(t, StatusPassed, res.Status)
+
+	res = ruleResult(t, profile, "metadata_author", Request{Payload: createDOCXWithProps("Договор", "", "", "Петров П.П.", "ООО «Ромашка»")})
+	require.Equal(t, CodeAuthorNotAllowed, res.Code)
+	require.Contains(t, res.Message, "Петров П.П.")
+
+	res = ruleResult(t, profile, "metadata_author", Request{Payload: createDOCXWithProps("Договор", "", "", "Иванов И.И.", "")})
+	require.Equal(t, CodeAuthorNotAllowed, res.Code)
+	require.Contains(t, res.Message, "организация")
+}
+
+func TestMetadataAuthor_DisabledWithoutLists(t *testing.T) {
+	engine, err := profileEngine(config.ProfileConfig{Rules: []string{"metadata_author"}})
+	require.NoError(t, err)
+	require.Empty(t, engine.RuleNames(config.DefaultProfileName))
+}
+
+func TestMetadataDates_OptIn(t *testing.T) {
+	engine, err := profileEngine(config.ProfileConfig{Rules: []string{"metadata_dates"}})
+	require.NoError(t, err)
+	require.Empty(t, engine.RuleNames(config.DefaultProfileName))
+}
@@ -0,0 +1,93 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+const rulePackageConsistency = "package_consistency"
+
+// Виды нарушений в details.findings правила package_consistency.
+const (
+	issueUnreadablePart     = "unreadable_part"
+	issueMissingContentType = "missing_content_type"
+	issueBrokenRelationship = "broken_relationship"
+	issueMissingMainPart    = "missing_main_part"
+)
+
+// ooxmlFormats — форматы с [Content_Types].xml и связями OPC.
+var ooxmlFormats = map[string]bool{FormatDOCX: true, FormatXLSX: true, FormatPPTX: true}
+
+func init() {
+	RegisterRule(rulePackageConsistency, func(config.ProfileConfig) (Rule, error) { return packageConsistencyRule{}, nil })
+}
+
+type packageConsistencyRule struct{}
+
+func (packageConsistencyRule) Name() string { return rulePackageConsistency }
+
+// Check разбирает [Content_Types].xml и все файлы связей: у каждой записи должен
+// быть тип, каждая внутренняя связь должна вести к существующей записи, а _rels/.rels —
+// к основной части. Отсутствие самих файлов оценивает required_parts.
+func (packageConsistencyRule) Check(doc *Document) RuleResult {
+	if doc.Archive == nil || doc.parts == nil || !ooxmlFormats[doc.Format] {
+		return notApplicable(rulePackageConsistency, doc)
+	}
+
+	var findings []finding
+	types, err := readContentTypes(doc.parts)
+	switch {
+	case err == nil:
+		for _, file := range doc.Archive.File {
+			if file.Name == contentTypesPart || strings.HasSuffix(file.Name, "/") {
+				continue
+			}
+			if _, ok := types.lookup(file.Name); !ok {
+				findings = append(findings, finding{path: file.Name, issue: issueMissingContentType, detail: "не указан тип содержимого"})
+			}
+		}
+	case doc.HasEntry(contentTypesPart):
+		findings = append(findings, finding{path: contentTypesPart, issue: issueUnreadablePart, detail: err.Error()})
+	}
+
+	for _, file := range doc.Archive.File {
+		source, ok := relsSource(file.Name)
+		if !ok {
+			continue
+		}
+		rels, err := readRelationships(doc.parts, file.Name)
+		if err != nil {
+			findings = append(findings, finding{path: file.Name, issue: issueUnreadablePart, detail: err.Error()})
+			continue
+		}
+		for _, rel := range rels {
+			if rel.External() {
+				continue
+			}
+			target := relationshipEntry(source, rel.Target)
+			if !doc.HasEntry(target) {
+				findings = append(findings, finding{path: file.Name + "#" + rel.ID, issue: issueBrokenRelationship,
+					detail: fmt.Sprintf("цель %s отсутствует", target)})
+			}
+		}
+	}
+
+	switch main := officeDocumentPart(doc.parts); {
+	case !doc.HasEntry(packageRelsPart):
+	case main == "":
+		findings = append(findings, finding{path: packageRelsPart, issue: issueMissingMainPart, detail: "нет связи officeDocument"})
+	case !doc.HasEntry(main):
+		findings = append(findings, finding{path: packageRelsPart, issue: issueMissingMainPart,
+			detail: fmt.Sprintf("основная часть %s отсутствует", main)})
+	}
+
+	details := map[string]interface{}{"main_part": doc.MainPart}
+	if len(findings) > 0 {
+		details["findings"] = findingDetails(findings)
+		return failed(rulePackageConsistency, CodeInvalidPackage,
+			fmt.Sprintf("пакет несогласован: %s", describeFindings(findings)), details)
+	}
+	return passed(rulePackageConsistency, details)
+}
@@ -0,0 +1,90 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+func packageFindings(t *testing.T, payload []byte) []map[string]interface{} {
+	t.Helper()
+	engine, err := profileEngine(configWithRules(rulePackageConsistency))
+	require.NoError(t, err)
+	res, ok := engine.Run(Request{Payload: payload}).Result(rulePackageConsistency)
+	require.True(t, ok)
+	if res.Status == StatusPassed {
+		return nil
+	}
+	require.Equal(t, CodeInvalidPackage, res.Code)
+	return res.Details["findings"].([]map[string]interface{})
+}
+
+func TestPackageConsistency_MainPartFromPackageRels(t *testing.T) {
+	// Сторонние генераторы кладут основную часть не в word/document.xml.
+	payload := buildZip(map[string]string{
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         packageRels("/content/main%20part.xml"),
+		"content/main part.xml": `<?xml version="1.0" encoding="UTF-8"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+			`<w:body><w:p><w:r><w:t>Договор поставки от 01.02.2024</w:t></w:r></w:p></w:body></w:document>`,
+	})
+	doc, err := parseDocument(payload, testBudget())
+	require.NoError(t, err)
+	require.Equal(t, "content/main part.xml", doc.MainPart)
+
+	engine, err := NewEngine(config.ValidationConfig{})
+	require.NoError(t, err)
+	report := engine.Run(Request{Payload: payload})
+	require.True(t, report.Valid(), report.Failures())
+}
+
+func TestPackageConsistency_ReportsFindings(t *testing.T) {
+	payload := buildZip(map[string]string{
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         testPackageRels,
+		"word/document.xml":   wordPart("document", `<w:body><w:p><w:r><w:t>Текст</w:t></w:r></w:p></w:body>`),
+		"word/_rels/document.xml.rels": `<Relationships ` + relsNS + `>` +
+			`<Relationship Id="rId1" Type="` + relType + `image" Target="media/image1.png"/>` +
+			`<Relationship Id="rId2" Type="` + relType + `styles" Target="styles.xml"/>` +
+			`<Relationship Id="rId3" Type="` + relType + `hyperlink" Target="https://example.org" TargetMode="External"/></Relationships>`,
+		"word/media/image1.png": "PNG",
+	})
+
+	findings := packageFindings(t, payload)
+	require.Len(t, findings, 2)
+	require.Equal(t, "word/media/image1.png", findings[0]["path"])
+	require.Equal(t, issueMissingContentType, findings[0]["issue"])
+	require.Equal(t, "word/_rels/document.xml.rels#rId2", findings[1]["path"])
+	require.Equal(t, issueBrokenRelationship, findings[1]["issue"])
+}
+
+func TestPackageConsistency_MainPartRelationship(t *testing.T) {
+	body := wordPart("document", `<w:body/>`)
+
+	findings := packageFindings(t, buildZip(map[string]string{
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         `<Relationships ` + relsNS + `/>`,
+		"word/document.xml":   body,
+	}))
+	require.Len(t, findings, 1)
+	require.Equal(t, issueMissingMainPart, findings[0]["issue"])
+
+	findings = packageFindings(t, buildZip(map[string]string{
+		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?>`,
+		"_rels/.rels":         packageRels("word/document2.xml"),
+		"word/document.xml":   body,
+	}))
+	var issues []interface{}
+	for _, f := range findings {
+		issues = append(issues, f["issue"])
+	}
+	require.Equal(t, []interface{}{issueUnreadablePart, issueBrokenRelationship, issueMissingMainPart}, issues)
+}
+
+func TestPackageConsistency_NotApplicableToODT(t *testing.T) {
+	engine, err := profileEngine(configWithRules(rulePackageConsistency))
+	require.NoError(t, err)
+	res, _ := engine.Run(Request{Payload: createODTWithText("Текст")}).Result(rulePackageConsistency)
+	require.Equal(t, StatusSkipped, res.Status)
+}
@@ -0,0 +1,187 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/qnhqn1/file-validator/config"
+	"github.com/qnhqn1/file-validator/internal/services/validator/stem"
+)
+
+const rulePhrases = "phrases"
+
+// maxReportedPhrases ограничивает список найденных запрещённых фраз в details.
+const maxReportedPhrases = 20
+
+func init() {
+	RegisterRule(rulePhrases, newPhrasesRule)
+}
+
+// phrase — фраза из конфигурации и её слова после нормализации.
+type phrase struct {
+	text  string
+	words []string
+}
+
+// phraseWord — слово абзаца после нормализации и его позиция в символах.
+type phraseWord struct {
+	norm   string
+	offset int
+}
+
+type phraseHit struct {
+	Phrase    string `json:"phrase"`
+	Part      string `json:"part"`
+	Paragraph int    `json:"paragraph"`
+	Offset    int    `json:"offset"`
+}
+
+type phrasesRule struct {
+	required  []phrase
+	forbidden []phrase
+	stemming  bool
+	parts     []string
+}
+
+func newPhrasesRule(cfg config.ProfileConfig) (Rule, error) {
+	pc := cfg.Phrases
+	if !ruleEnabled(pc.Enabled) || len(pc.Required)+len(pc.Forbidden) == 0 {
+		return nil, nil
+	}
+	if err := checkSources(pc.Parts); err != nil {
+		return nil, err
+	}
+	r := phrasesRule{stemming: flagSet(pc.Stemming), parts: pc.Parts}
+	var err error
+	if r.required, err = r.compile(pc.Required); err != nil {
+		return nil, err
+	}
+	if r.forbidden, err = r.compile(pc.Forbidden); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r phrasesRule) compile(texts []string) ([]phrase, error) {
+	out := make([]phrase, 0, len(texts))
+	for _, text := range texts {
+		var words []string
+		for _, w := range r.words(text) {
+			words = append(words, w.norm)
+		}
+		if len(words) == 0 {
+			return nil, fmt.Errorf("фраза %q не содержит слов", text)
+		}
+		out = append(out, phrase{text: text, words: words})
+	}
+	return out, nil
+}
+
+func (phrasesRule) Name() string { return rulePhrases }
+
+// words разбивает текст на слова из букв и цифр, приводит их к нижнему
+// регистру, заменяет «ё» на «е» и, если включено, оставляет только основу.
+func (r phrasesRule) words(text string) []phraseWord {
+	var out []phraseWord
+	var word strings.Builder
+	start, pos := 0, 0
+	flush := func() {
+		if word.Len() == 0 {
+			return
+		}
+		norm := strings.ReplaceAll(strings.ToLower(word.String()), "ё", "е")
+		if r.stemming {
+			norm = stem.Russian(norm)
+		}
+		out = append(out, phraseWord{norm: norm, offset: start})
+		word.Reset()
+	}
+	for _, c := range text {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			if word.Len() == 0 {
+				start = pos
+			}
+			word.WriteRune(c)
+		} else {
+			flush()
+		}
+		pos++
+	}
+	flush()
+	return out
+}
+
+// find возвращает позиции слов абзаца, с которых начинается фраза.
+func (p phrase) find(words []phraseWord) []int {
+	var at []int
+	for i := 0; i+len(p.words) <= len(words); i++ {
+		matched := true
+		for j, w := range p.words {
+			if words[i+j].norm != w {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			at = append(at, words[i].offset)
+		}
+	}
+	return at
+}
+
+// Check ищет фразы по абзацам: фраза, разорванная между абзацами, не находится.
+func (r phrasesRule) Check(doc *Document) RuleResult {
+	found := make(map[string]int, len(r.required))
+	var hits []phraseHit
+	forbiddenCount := make(map[string]int)
+	for i, para := range doc.ParagraphsFrom(r.parts) {
+		words := r.words(para.Text)
+		if len(words) == 0 {
+			continue
+		}
+		for _, p := range r.required {
+			found[p.text] += len(p.find(words))
+		}
+		for _, p := range r.forbidden {
+			for _, offset := range p.find(words) {
+				forbiddenCount[p.text]++
+				if len(hits) < maxReportedPhrases {
+					hits = append(hits, phraseHit{Phrase: p.text, Part: para.Part, Paragraph: i, Offset: offset})
+				}
+			}
+		}
+	}
+
+	var missing, forbidden []string
+	for _, p := range r.required {
+		if found[p.text] == 0 {
+			missing = append(missing, p.text)
+		}
+	}
+	for _, p := range r.forbidden {
+		if forbiddenCount[p.text] > 0 {
+			forbidden = append(forbidden, fmt.Sprintf("«%s» (%d)", p.text, forbiddenCount[p.text]))
+		}
+	}
+
+	details := map[string]interface{}{"stemming": r.stemming}
+	if len(r.required) > 0 {
+		details["required"] = found
+	}
+	if len(missing) > 0 {
+		details["missing"] = missing
+	}
+	if len(hits) > 0 {
+		details["forbidden"] = hits
+	}
+	if len(forbidden) > 0 {
+		return failed(rulePhrases, CodeForbiddenPhrase,
+			fmt.Sprintf("найдены запрещённые фразы: %s", strings.Join(forbidden, ", ")), details)
+	}
+	if len(missing) > 0 {
+		return failed(rulePhrases, CodeRequiredPhraseMissing,
+			fmt.Sprintf("не найдены обязательные фразы: «%s»", strings.Join(missing, "», «")), details)
+	}
+	return passed(rulePhrases, details)
+}
@@ -0,0 +1,50 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+func TestPhrases_ForbiddenPointsToParagraph(t *testing.T) {
+	payload := createDOCXWithParagraphs("Договор поставки", "Цена договора", "ОБРАЗЕЦ — не для подписания", "Ещё один образец")
+
+	res := ruleResult(t, config.ProfileConfig{Phrases: config.PhrasesConfig{Forbidden: []string{"образец", "черновик"}}}, "phrases", Request{Payload: payload})
+	require.Equal(t, CodeForbiddenPhrase, res.Code)
+	require.Equal(t, "найдены запрещённые фразы: «образец» (2)", res.Message)
+	require.Equal(t, []phraseHit{
+		{Phrase: "образец", Part: "word/document.xml", Paragraph: 2, Offset: 0},
+		{Phrase: "образец", Part: "word/document.xml", Paragraph: 3, Offset: 9},
+	}, res.Details["forbidden"])
+}
+
+func TestPhrases_RequiredWithYoAndStemming(t *testing.T) {
+	payload := createDOCXWithParagraphs("ДОГОВОР № 15", "Счёт оплачивается в течение 5 дней", "Подписями сторон подтверждено")
+	cfg := config.PhrasesConfig{Required: []string{"Договор", "счет оплачивается", "Подписи сторон"}}
+
+	res := ruleResult(t, config.ProfileConfig{Phrases: cfg}, "phrases", Request{Payload: payload})
+	require.Equal(t, CodeRequiredPhraseMissing, res.Code)
+	require.Equal(t, []string{"Подписи сторон"}, res.Details["missing"])
+
+	enabled := true
+	cfg.Stemming = &enabled
+	res = ruleResult(t, config.ProfileConfig{Phrases: cfg}, "phrases", Request{Payload: payload})
+	require.Equal(t, StatusPassed, res.Status, res.Message)
+	require.Equal(t, map[string]int{"Договор": 1, "счет оплачивается": 1, "Подписи сторон": 1}, res.Details["required"])
+}
+
+func TestPhrases_WholeWordsOnly(t *testing.T) {
+	res := ruleResult(t, config.ProfileConfig{Phrases: config.PhrasesConfig{Forbidden: []string{"проект"}}}, "phrases", Request{Payload: createDOCXWithText("Проектирование и проектная документация")})
+	require.Equal(t, StatusPassed, res.Status)
+}
+
+func TestPhrases_Config(t *testing.T) {
+	engine, err := profileEngine(config.ProfileConfig{Rules: []string{"phrases"}})
+	require.NoError(t, err)
+	require.Empty(t, engine.RuleNames(config.DefaultProfileName))
+
+	_, err = profileEngine(config.ProfileConfig{Phrases: config.PhrasesConfig{Forbidden: []string{" — "}}})
+	require.Error(t, err)
+}
@@ -0,0 +1,232 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+const rulePII = "pii"
+
+// Виды персональных данных; они же ключи pii.policies в конфигурации.
+const (
+	piiPassport = "passport"
+	piiSNILS    = "snils"
+	piiINN      = "inn"
+	piiOGRN     = "ogrn"
+	piiCard     = "card"
+	piiPhone    = "phone"
+	piiEmail    = "email"
+)
+
+var piiTitles = map[string]string{
+	piiPassport: "паспорт",
+	piiSNILS:    "СНИЛС",
+	piiINN:      "ИНН",
+	piiOGRN:     "ОГРН",
+	piiCard:     "номер карты",
+	piiPhone:    "телефон",
+	piiEmail:    "e-mail",
+}
+
+// maxReportedPII ограничивает список находок в details; счётчики полные.
+const maxReportedPII = 50
+
+// passportContextRunes — насколько далеко перед номером ищется слово «паспорт» или «серия».
+const passportContextRunes = 60
+
+// piiPattern находит кандидатов одного вида; valid отсеивает совпадения без
+// контрольной суммы или нужного контекста. Порядок шаблонов — приоритет:
+// пересекающееся с уже найденным совпадение отбрасывается. Номера с
+// контрольной суммой идут раньше телефонов: иначе 11 цифр, начинающиеся с 8,
+// никогда не проверялись бы как СНИЛС.
+type piiPattern struct {
+	kind  string
+	re    *regexp.Regexp
+	valid func(text string, start, end int) bool
+}
+
+var piiPatterns = []piiPattern{
+	{kind: piiEmail, re: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)},
+	{kind: piiPassport, re: regexp.MustCompile(`\b\d{2} ?\d{2}(?:\s+|\s*(?:№|N|номер)\s*)\d{6}\b`), valid: passportContext},
+	{kind: piiSNILS, re: regexp.MustCompile(`\b\d{3}-\d{3}-\d{3}[ -]\d{2}\b|\b\d{11}\b`), valid: digitsValid(validSNILS)},
+	{kind: piiINN, re: regexp.MustCompile(`\b(?:\d{10}|\d{12})\b`), valid: digitsValid(validINN)},
+	{kind: piiOGRN, re: regexp.MustCompile(`\b(?:\d{13}|\d{15})\b`), valid: digitsValid(validOGRN)},
+	{kind: piiCard, re: regexp.MustCompile(`\b[2-6]\d{3}(?:[ -]?\d{4}){2}[ -]?\d{1,7}\b`), valid: digitsValid(validLuhn)},
+	{kind: piiPhone, re: regexp.MustCompile(`(?:\+7|\b8)[ \-]?\(?\d{3}\)?[ \-]?\d{3}[ \-]?\d{2}[ \-]?\d{2}\b`)},
+	{kind: piiPhone, re: regexp.MustCompile(`\(\d{3,5}\) ?\d{1,3}-\d{2}-\d{2}\b`)},
+}
+
+func digitsValid(check func(string) bool) func(text string, start, end int) bool {
+	return func(text string, start, end int) bool {
+		return check(onlyDigits(text[start:end]))
+	}
+}
+
+// passportContext требует слово «паспорт» или «серия» незадолго до номера:
+// без него десять цифр неотличимы от любого другого номера.
+func passportContext(text string, start, _ int) bool {
+	before := text[:start]
+	for i := 0; i < passportContextRunes && len(before) > 0; i++ {
+		_, size := utf8.DecodeLastRuneInString(before)
+		before = before[:len(before)-size]
+	}
+	window := strings.ToLower(text[len(before):start])
+	return strings.Contains(window, "паспорт") || strings.Contains(window, "серия")
+}
+
+// piiFinding — найденное значение без самого значения: вид и место.
+// Paragraph — номер абзаца среди проверяемых источников, Offset — позиция в абзаце в символах.
+type piiFinding struct {
+	Kind      string `json:"kind"`
+	Part      string `json:"part"`
+	Paragraph int    `json:"paragraph"`
+	Offset    int    `json:"offset"`
+}
+
+// piiSpan — вид и границы совпадения в байтах.
+type piiSpan struct {
+	kind       string
+	start, end int
+}
+
+// findPII возвращает виды и байтовые границы находок в порядке следования.
+func findPII(text string) []piiSpan {
+	var spans []piiSpan
+	// covered отмечает байты принятых совпадений: совпадения одного шаблона не
+	// пересекаются, поэтому проверка занимает O(len(text)) на шаблон.
+	var covered []bool
+	overlaps := func(start, end int) bool {
+		if covered == nil {
+			return false
+		}
+		for i := start; i < end; i++ {
+			if covered[i] {
+				return true
+			}
+		}
+		return false
+	}
+	for _, p := range piiPatterns {
+		for _, idx := range p.re.FindAllStringIndex(text, -1) {
+			if overlaps(idx[0], idx[1]) || (p.valid != nil && !p.valid(text, idx[0], idx[1])) {
+				continue
+			}
+			if covered == nil {
+				covered = make([]bool, len(text))
+			}
+			for i := idx[0]; i < idx[1]; i++ {
+				covered[i] = true
+			}
+			spans = append(spans, piiSpan{kind: p.kind, start: idx[0], end: idx[1]})
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	return spans
+}
+
+func init() {
+	RegisterRule(rulePII, newPIIRule)
+}
+
+type piiRule struct {
+	policies map[string]string
+	parts    []string
+}
+
+func newPIIRule(cfg config.ProfileConfig) (Rule, error) {
+	if !ruleEnabled(cfg.PII.Enabled) {
+		return nil, nil
+	}
+	policies := make(map[string]string, len(piiTitles))
+	for kind := range piiTitles {
+		policies[kind] = config.PolicyAllow
+	}
+	for kind, policy := range cfg.PII.Policies {
+		if _, ok := piiTitles[kind]; !ok {
+			return nil, fmt.Errorf("неизвестный вид персональных данных %s, допустимы: %s", kind, strings.Join(piiKinds(), ", "))
+		}
+		if policy == config.PolicyForbid {
+			policy = config.PolicyReject
+		}
+		if policy != config.PolicyRequire {
+			if err := checkPolicy(policy); err != nil {
+				return nil, fmt.Errorf("%w, forbid или require", err)
+			}
+		}
+		policies[kind] = policy
+	}
+	if err := checkSources(cfg.PII.Parts); err != nil {
+		return nil, err
+	}
+	return piiRule{policies: policies, parts: cfg.PII.Parts}, nil
+}
+
+func piiKinds() []string {
+	kinds := make([]string, 0, len(piiTitles))
+	for kind := range piiTitles {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+func (piiRule) Name() string { return rulePII }
+
+// Check ищет персональные данные по абзацам. В отчёт попадают только виды,
+// количество и места находок — значения в нём не сохраняются.
+func (r piiRule) Check(doc *Document) RuleResult {
+	counts := make(map[string]int)
+	var findings []piiFinding
+	for i, para := range doc.ParagraphsFrom(r.parts) {
+		byteOffset, runeOffset := 0, 0
+		for _, s := range findPII(para.Text) {
+			counts[s.kind]++
+			if len(findings) >= maxReportedPII {
+				continue
+			}
+			runeOffset += utf8.RuneCountInString(para.Text[byteOffset:s.start])
+			byteOffset = s.start
+			findings = append(findings, piiFinding{Kind: s.kind, Part: para.Part, Paragraph: i, Offset: runeOffset})
+		}
+	}
+
+	details := map[string]interface{}{"counts": counts}
+	if len(findings) > 0 {
+		details["findings"] = findings
+	}
+
+	var rejected, missing, warned []string
+	for _, kind := range piiKinds() {
+		switch r.policies[kind] {
+		case config.PolicyReject:
+			if counts[kind] > 0 {
+				rejected = append(rejected, fmt.Sprintf("%s (%d)", piiTitles[kind], counts[kind]))
+			}
+		case config.PolicyWarn:
+			if counts[kind] > 0 {
+				warned = append(warned, fmt.Sprintf("%s (%d)", piiTitles[kind], counts[kind]))
+			}
+		case config.PolicyRequire:
+			if counts[kind] == 0 {
+				missing = append(missing, piiTitles[kind])
+			}
+		}
+	}
+	switch {
+	case len(rejected) > 0:
+		return failed(rulePII, CodePersonalData,
+			fmt.Sprintf("документ содержит персональные данные: %s", strings.Join(rejected, ", ")), details)
+	case len(missing) > 0:
+		return failed(rulePII, CodePersonalDataMissing,
+			fmt.Sprintf("в документе не найдены обязательные данные: %s", strings.Join(missing, ", ")), details)
+	case len(warned) > 0:
+		return warning(rulePII, CodePersonalData,
+			fmt.Sprintf("документ содержит персональные данные: %s", strings.Join(warned, ", ")), details)
+	}
+	return passed(rulePII, details)
+}
@@ -0,0 +1,80 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+func TestFindPII(t *testing.T) {
+	kinds := func(text string) []string {
+		var out []string
+		for _, s := range findPII(text) {
+			out = append(out, s.kind)
+		}
+		return out
+	}
+
+	require.Equal(t, []string{piiPassport}, kinds("Паспорт: серия 45 09 № 123456, выдан ОВД"))
+	require.Equal(t, []string{piiSNILS, piiSNILS}, kinds("СНИЛС 112-233-445 95, повторно 11223344595"))
+	require.Equal(t, []string{piiINN, piiINN}, kinds("ИНН 7707083893 и ИНН 500100732259"))
+	require.Equal(t, []string{piiOGRN}, kinds("ОГРН 1027700132195"))
+	require.Equal(t, []string{piiCard, piiCard}, kinds("карта 4111 1111 1111 1111 или 4111111111111111"))
+	require.Equal(t, []string{piiPhone, piiPhone, piiPhone}, kinds("тел. +7 (916) 123-45-67, 8-916-123-45-67, (495) 123-45-67"))
+	require.Equal(t, []string{piiEmail}, kinds("пишите на ivanov.ii@example.ru"))
+
+	// 11 цифр с 8 в начале — СНИЛС, если сходится контрольное число, иначе телефон.
+	require.Equal(t, []string{piiSNILS, piiPhone}, kinds("СНИЛС 80000000072, тел. 89161234567"))
+
+	// Длинный абзац из одних находок разбирается за линейное время.
+	many := strings.Repeat("a@example.ru 7707083893 ", 20000)
+	require.Len(t, findPII(many), 40000)
+
+	// Без контрольной суммы или контекста цифры персональными данными не считаются.
+	require.Empty(t, kinds("номер 7707083894, договор 4509 123456, сумма 4111111111111112"))
+}
+
+func TestPII_ReportsCountsAndLocationsWithoutValues(t *testing.T) {
+	payload := createDOCXWithParagraphs("Договор поставки", "Покупатель: ИНН 7707083893, тел. +7 916 123-45-67")
+
+	res := ruleResult(t, config.ProfileConfig{}, "pii", Request{Payload: payload})
+	require.Equal(t, StatusPassed, res.Status)
+	require.Equal(t, map[string]int{"inn": 1, "phone": 1}, res.Details["counts"])
+	require.Equal(t, []piiFinding{
+		{Kind: "inn", Part: "word/document.xml", Paragraph: 1, Offset: 16},
+		{Kind: "phone", Part: "word/document.xml", Paragraph: 1, Offset: 33},
+	}, res.Details["findings"])
+	require.NotContains(t, fmt.Sprint(res.Details), "7707083893")
+}
+
+func TestPII_Policies(t *testing.T) {
+	payload := createDOCXWithText("Паспорт серии 4509 123456, e-mail ivanov@example.ru")
+
+	res := ruleResult(t, config.ProfileConfig{PII: config.PIIConfig{Policies: map[string]string{"passport": "reject", "email": "warn"}}}, "pii", Request{Payload: payload})
+	require.Equal(t, StatusFailed, res.Status)
+	require.Equal(t, CodePersonalData, res.Code)
+	require.Contains(t, res.Message, "паспорт (1)")
+	require.NotContains(t, res.Message, "4509")
+
+	res = ruleResult(t, config.ProfileConfig{PII: config.PIIConfig{Policies: map[string]string{"email": "warn"}}}, "pii", Request{Payload: payload})
+	require.Equal(t, StatusWarning, res.Status)
+
+	// forbid — синоним reject.
+	res = ruleResult(t, config.ProfileConfig{PII: config.PIIConfig{Policies: map[string]string{"email": "forbid"}}}, "pii", Request{Payload: payload})
+	require.Equal(t, CodePersonalData, res.Code)
+
+	res = ruleResult(t, config.ProfileConfig{PII: config.PIIConfig{Policies: map[string]string{"inn": "require", "ogrn": "require"}}}, "pii", Request{Payload: payload})
+	require.Equal(t, CodePersonalDataMissing, res.Code)
+	require.Contains(t, res.Message, "ИНН, ОГРН")
+}
+
+func TestPII_Config(t *testing.T) {
+	_, err := profileEngine(config.ProfileConfig{PII: config.PIIConfig{Policies: map[string]string{"dna": "reject"}}})
+	require.Error(t, err)
+	_, err = profileEngine(config.ProfileConfig{PII: config.PIIConfig{Policies: map[string]string{"inn": "block"}}})
+	require.ErrorContains(t, err, "forbid или require")
+}
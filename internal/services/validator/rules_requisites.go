@@ -0,0 +1,340 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+const ruleRequisites = "requisites"
+
+// Виды реквизитов в details.
+const (
+	requisiteINN         = "inn"
+	requisiteKPP         = "kpp"
+	requisiteOGRN        = "ogrn"
+	requisiteBIK         = "bik"
+	requisiteAccount     = "account"
+	requisiteCorrAccount = "corr_account"
+)
+
+var requisiteTitles = map[string]string{
+	requisiteINN:         "ИНН",
+	requisiteKPP:         "КПП",
+	requisiteOGRN:        "ОГРН",
+	requisiteBIK:         "БИК",
+	requisiteAccount:     "р/с",
+	requisiteCorrAccount: "к/с",
+}
+
+// Состояние реквизита: счёт без БИК поблизости проверить нельзя.
+const (
+	requisiteValid      = "valid"
+	requisiteInvalid    = "invalid"
+	requisiteUnverified = "unverified"
+)
+
+// maxReportedRequisites ограничивает список реквизитов в details.
+const maxReportedRequisites = 50
+
+// requisitePattern находит подпись реквизита и его значение; значения — группы
+// с номерами из kinds. «ИНН/КПП 7707083893/773601001» даёт сразу два реквизита.
+// hints — подстроки в нижнем регистре, без одной из которых шаблон не ищется:
+// регулярные выражения с (?i) на длинном тексте заметно медленнее.
+type requisitePattern struct {
+	re    *regexp.Regexp
+	kinds []string
+	hints []string
+}
+
+const (
+	labelStart = `(?i)`
+	labelSep   = `[\s:№.]*`
+	// accountDigits допускает пробелы внутри номера: счета часто пишут группами.
+	accountDigits = `(\d(?:[\d ]{0,28}\d)?)`
+)
+
+var requisitePatterns = []requisitePattern{
+	{regexp.MustCompile(labelStart + `ИНН\s*/\s*КПП` + labelSep + `(\d+)\s*/\s*([0-9A-Z]+)`), []string{requisiteINN, requisiteKPP}, []string{"инн"}},
+	{regexp.MustCompile(labelStart + `ИНН` + labelSep + `(\d+)`), []string{requisiteINN}, []string{"инн"}},
+	{regexp.MustCompile(labelStart + `КПП` + labelSep + `([0-9A-Z]+)`), []string{requisiteKPP}, []string{"кпп"}},
+	{regexp.MustCompile(labelStart + `ОГРН(?:ИП)?` + labelSep + `(\d+)`), []string{requisiteOGRN}, []string{"огрн"}},
+	{regexp.MustCompile(labelStart + `БИК` + labelSep + `(\d+)`), []string{requisiteBIK}, []string{"бик"}},
+	{regexp.MustCompile(labelStart + `(?:р/сч?|р\. ?с\.|расч[её]тный\s+сч[её]т)` + labelSep + accountDigits), []string{requisiteAccount}, []string{"р/с", "р.с.", "р. с.", "расч"}},
+	{regexp.MustCompile(labelStart + `(?:к/сч?|к\. ?с\.|корр?\.?\s*сч[её]т|корреспондентский\s+сч[её]т)` + labelSep + accountDigits), []string{requisiteCorrAccount}, []string{"к/с", "к.с.", "к. с.", "кор"}},
+}
+
+// requisite — найденный реквизит. Значение в отчёт не попадает: ИНН
+// физического лица — персональные данные.
+type requisite struct {
+	kind       string
+	value      string
+	paragraph  int
+	part       string
+	start, end int
+	offset     int
+}
+
+type requisiteFinding struct {
+	Kind      string `json:"kind"`
+	Part      string `json:"part"`
+	Paragraph int    `json:"paragraph"`
+	Offset    int    `json:"offset"`
+	Status    string `json:"status"`
+	Problem   string `json:"problem,omitempty"`
+}
+
+// findRequisites возвращает реквизиты абзаца в порядке следования.
+func findRequisites(text string) []requisite {
+	var found []requisite
+	lower := strings.ToLower(text)
+	for _, p := range requisitePatterns {
+		if !containsAny(lower, p.hints) {
+			continue
+		}
+		for _, m := range p.re.FindAllStringSubmatchIndex(text, -1) {
+			// Подпись — отдельное слово: «Инновации 7707083893» не ИНН.
+			if r, _ := utf8.DecodeLastRuneInString(text[:m[0]]); unicode.IsLetter(r) {
+				continue
+			}
+			for i, kind := range p.kinds {
+				start, end := m[2*(i+1)], m[2*(i+1)+1]
+				overlap := false
+				for _, f := range found {
+					if start < f.end && f.start < end {
+						overlap = true
+						break
+					}
+				}
+				if !overlap {
+					found = append(found, requisite{kind: kind, value: strings.ReplaceAll(text[start:end], " ", ""), start: start, end: end})
+				}
+			}
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].start < found[j].start })
+	return found
+}
+
+func containsAny(s string, subs []string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// requisiteBlock — реквизиты одной стороны: подряд идущие абзацы одной ячейки
+// таблицы или текста вне таблиц. Блок заканчивается пустым абзацем или
+// повтором уже встреченного вида реквизита.
+type requisiteBlock struct {
+	items []requisite
+}
+
+func (b *requisiteBlock) first(kind string) (requisite, bool) {
+	for _, r := range b.items {
+		if r.kind == kind {
+			return r, true
+		}
+	}
+	return requisite{}, false
+}
+
+func requisiteBlocks(paragraphs []Paragraph) []*requisiteBlock {
+	var blocks []*requisiteBlock
+	current := &requisiteBlock{}
+	currentKey := ""
+	flush := func() {
+		if len(current.items) > 0 {
+			blocks = append(blocks, current)
+		}
+		current = &requisiteBlock{}
+	}
+	for i, para := range paragraphs {
+		key := fmt.Sprintf("%s/%d/%d/%d", para.Part, para.Table, para.Row, para.Cell)
+		if key != currentKey || strings.TrimSpace(para.Text) == "" {
+			flush()
+			currentKey = key
+		}
+		found := findRequisites(para.Text)
+		for _, r := range found {
+			if _, ok := current.first(r.kind); ok {
+				flush()
+				break
+			}
+		}
+		byteOffset, runeOffset := 0, 0
+		for _, r := range found {
+			runeOffset += utf8.RuneCountInString(para.Text[byteOffset:r.start])
+			byteOffset = r.start
+			r.paragraph, r.part, r.offset = i, para.Part, runeOffset
+			current.items = append(current.items, r)
+		}
+	}
+	flush()
+	return blocks
+}
+
+func init() {
+	RegisterRule(ruleRequisites, newRequisitesRule)
+}
+
+type requisitesRule struct {
+	requireParty bool
+	parts        []string
+}
+
+func newRequisitesRule(cfg config.ProfileConfig) (Rule, error) {
+	if !flagSet(cfg.Requisites.Enabled) {
+		return nil, nil
+	}
+	if err := checkSources(cfg.Requisites.Parts); err != nil {
+		return nil, err
+	}
+	return requisitesRule{requireParty: flagSet(cfg.Requisites.RequireParty), parts: cfg.Requisites.Parts}, nil
+}
+
+func (requisitesRule) Name() string { return ruleRequisites }
+
+// Check проверяет контрольные числа найденных реквизитов. Счета сверяются с
+// БИК своего блока, а если его там нет — с единственным БИК документа.
+func (r requisitesRule) Check(doc *Document) RuleResult {
+	blocks := requisiteBlocks(doc.ParagraphsFrom(r.parts))
+
+	documentBIK := ""
+	biks := make(map[string]bool)
+	for _, b := range blocks {
+		for _, item := range b.items {
+			if item.kind == requisiteBIK && validBIK(item.value) {
+				biks[item.value] = true
+				documentBIK = item.value
+			}
+		}
+	}
+	if len(biks) != 1 {
+		documentBIK = ""
+	}
+
+	var findings []requisiteFinding
+	var invalid []string
+	counts := make(map[string]int)
+	parties := 0
+	var best []string
+	for _, b := range blocks {
+		bik := documentBIK
+		if item, ok := b.first(requisiteBIK); ok && validBIK(item.value) {
+			bik = item.value
+		}
+		valid := make(map[string]requisite)
+		for _, item := range b.items {
+			counts[item.kind]++
+			status, problem := checkRequisite(item, bik)
+			if status == requisiteInvalid {
+				invalid = append(invalid, fmt.Sprintf("%s (%s)", requisiteTitles[item.kind], problem))
+			}
+			if status == requisiteValid {
+				if _, ok := valid[item.kind]; !ok {
+					valid[item.kind] = item
+				}
+			}
+			if len(findings) < maxReportedRequisites {
+				findings = append(findings, requisiteFinding{
+					Kind: item.kind, Part: item.part, Paragraph: item.paragraph, Offset: item.offset,
+					Status: status, Problem: problem,
+				})
+			}
+		}
+		missing := missingPartyRequisites(valid)
+		if len(missing) == 0 {
+			parties++
+		} else if best == nil || len(missing) < len(best) {
+			best = missing
+		}
+	}
+
+	details := map[string]interface{}{"counts": counts, "complete_parties": parties}
+	if len(findings) > 0 {
+		details["requisites"] = findings
+	}
+	if len(invalid) > 0 {
+		return failed(ruleRequisites, CodeRequisiteInvalid,
+			fmt.Sprintf("неверные реквизиты: %s", strings.Join(invalid, ", ")), details)
+	}
+	if r.requireParty && parties == 0 {
+		if best == nil {
+			best = missingPartyRequisites(nil)
+		}
+		return failed(ruleRequisites, CodeIncompleteRequisites,
+			fmt.Sprintf("не найден полный блок реквизитов стороны, не хватает: %s", strings.Join(best, ", ")), details)
+	}
+	return passed(ruleRequisites, details)
+}
+
+// checkRequisite возвращает состояние реквизита и описание ошибки.
+func checkRequisite(item requisite, bik string) (string, string) {
+	switch item.kind {
+	case requisiteINN:
+		if len(item.value) != 10 && len(item.value) != 12 {
+			return requisiteInvalid, "должно быть 10 или 12 цифр"
+		}
+		if !validINN(item.value) {
+			return requisiteInvalid, "неверное контрольное число"
+		}
+	case requisiteKPP:
+		if !validKPP(item.value) {
+			return requisiteInvalid, "неверный формат"
+		}
+	case requisiteOGRN:
+		if len(item.value) != 13 && len(item.value) != 15 {
+			return requisiteInvalid, "должно быть 13 или 15 цифр"
+		}
+		if !validOGRN(item.value) {
+			return requisiteInvalid, "неверное контрольное число"
+		}
+	case requisiteBIK:
+		if !validBIK(item.value) {
+			return requisiteInvalid, "должно быть 9 цифр, начиная с 04"
+		}
+	case requisiteAccount, requisiteCorrAccount:
+		if len(item.value) != 20 {
+			return requisiteInvalid, "должно быть 20 цифр"
+		}
+		if item.kind == requisiteCorrAccount && !strings.HasPrefix(item.value, "301") {
+			return requisiteInvalid, "корреспондентский счёт начинается с 301"
+		}
+		if bik == "" {
+			return requisiteUnverified, "рядом нет БИК"
+		}
+		check := validAccount
+		if item.kind == requisiteCorrAccount {
+			check = validCorrAccount
+		}
+		if !check(item.value, bik) {
+			return requisiteInvalid, "контрольный ключ не сходится с БИК"
+		}
+	}
+	return requisiteValid, ""
+}
+
+// missingPartyRequisites перечисляет, чего не хватает до полного блока
+// реквизитов. КПП требуется только у организации — с ИНН из 10 цифр.
+func missingPartyRequisites(valid map[string]requisite) []string {
+	kinds := []string{requisiteINN, requisiteKPP, requisiteBIK, requisiteAccount, requisiteCorrAccount}
+	var missing []string
+	for _, kind := range kinds {
+		if _, ok := valid[kind]; ok {
+			continue
+		}
+		if inn, ok := valid[requisiteINN]; kind == requisiteKPP && ok && len(inn.value) == 12 {
+			continue
+		}
+		missing = append(missing, requisiteTitles[kind])
+	}
+	return missing
+}
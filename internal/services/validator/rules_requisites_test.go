@@ -0,0 +1,96 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+var supplierRequisites = []string{
+	"ООО «Ромашка»",
+	"ИНН/КПП 7707083893/773601001",
+	"ОГРН 1027700132195",
+	"р/с 40702 810 2 0000 0000001",
+	"БИК 044525225",
+	"к/с 30101810400000000225",
+}
+
+func requisitesProfile(requireParty bool) config.ProfileConfig {
+	enabled := true
+	return config.ProfileConfig{Requisites: config.RequisitesConfig{Enabled: &enabled, RequireParty: &requireParty}}
+}
+
+func TestFindRequisites(t *testing.T) {
+	var kinds []string
+	for _, r := range findRequisites("ИНН/КПП 7707083893/773601001, ОГРН: 1027700132195, р/сч № 40702 810 2 0000 0000001") {
+		kinds = append(kinds, r.kind+"="+r.value)
+	}
+	require.Equal(t, []string{"inn=7707083893", "kpp=773601001", "ogrn=1027700132195", "account=40702810200000000001"}, kinds)
+
+	// Подпись нужна: номер без неё и слово, начинающееся с «ИНН», реквизитами не считаются.
+	require.Empty(t, findRequisites("Инновации 7707083893, счёт 40702810200000000001"))
+	// Сокращение «р.» без «с.» — не подпись счёта.
+	require.Empty(t, findRequisites("см. р. 40702810200000000001"))
+	require.Len(t, findRequisites("р. с. 40702810200000000001"), 1)
+}
+
+func TestRequisites_CompleteParty(t *testing.T) {
+	res := ruleResult(t, requisitesProfile(true), "requisites", Request{Payload: createDOCXWithParagraphs(supplierRequisites...)})
+	require.Equal(t, StatusPassed, res.Status, res.Message)
+	require.Equal(t, 1, res.Details["complete_parties"])
+	for _, f := range res.Details["requisites"].([]requisiteFinding) {
+		require.Equal(t, requisiteValid, f.Status, f.Kind)
+	}
+}
+
+func TestRequisites_InvalidControlDigits(t *testing.T) {
+	paragraphs := append([]string{}, supplierRequisites...)
+	paragraphs[1] = "ИНН/КПП 7707083894/773601001"
+	paragraphs[3] = "р/с 40702810300000000001"
+	res := ruleResult(t, requisitesProfile(false), "requisites", Request{Payload: createDOCXWithParagraphs(paragraphs...)})
+	require.Equal(t, CodeRequisiteInvalid, res.Code)
+	require.Contains(t, res.Message, "ИНН (неверное контрольное число)")
+	require.Contains(t, res.Message, "р/с (контрольный ключ не сходится с БИК)")
+	require.NotContains(t, res.Message, "7707083894")
+}
+
+func TestRequisites_PartiesInTableCells(t *testing.T) {
+	cell := func(lines ...string) string {
+		var b strings.Builder
+		b.WriteString("<w:tc>")
+		for _, l := range lines {
+			b.WriteString("<w:p><w:r><w:t>" + l + "</w:t></w:r></w:p>")
+		}
+		b.WriteString("</w:tc>")
+		return b.String()
+	}
+	// У покупателя свой банк: его счёт сверяется с его БИК, а не с БИК поставщика.
+	buyer := cell("ИП Иванов", "ИНН 500100732259", "р/с 40817810738000000002", "БИК 044525225", "к/с 30101810400000000225")
+	payload := createDOCX("<w:tbl><w:tr>"+cell(supplierRequisites...)+buyer+"</w:tr></w:tbl>", nil)
+
+	res := ruleResult(t, requisitesProfile(true), "requisites", Request{Payload: payload})
+	require.Equal(t, StatusPassed, res.Status, res.Message)
+	require.Equal(t, 2, res.Details["complete_parties"])
+}
+
+func TestRequisites_IncompleteParty(t *testing.T) {
+	payload := createDOCXWithParagraphs(supplierRequisites[:5]...)
+
+	res := ruleResult(t, requisitesProfile(false), "requisites", Request{Payload: payload})
+	require.Equal(t, StatusPassed, res.Status)
+
+	res = ruleResult(t, requisitesProfile(true), "requisites", Request{Payload: payload})
+	require.Equal(t, CodeIncompleteRequisites, res.Code)
+	require.Contains(t, res.Message, "не хватает: к/с")
+}
+
+func TestRequisites_AccountWithoutBIKIsUnverified(t *testing.T) {
+	res := ruleResult(t, requisitesProfile(false), "requisites", Request{Payload: createDOCXWithText("Оплата на р/с 40702810300000000001")})
+	require.Equal(t, StatusPassed, res.Status)
+	require.Equal(t, []requisiteFinding{
+		{Kind: "account", Part: "word/document.xml", Paragraph: 0, Offset: 14, Status: requisiteUnverified, Problem: "рядом нет БИК"},
+	}, res.Details["requisites"])
+}
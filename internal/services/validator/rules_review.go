@@ -0,0 +1,135 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+const ruleReview = "review"
+
+// maxReportedComments ограничивает список неразрешённых примечаний в details.
+const maxReportedComments = 20
+
+func init() {
+	RegisterRule(ruleReview, newReviewRule)
+}
+
+type reviewRule struct {
+	revisions string
+	comments  string
+}
+
+func newReviewRule(cfg config.ProfileConfig) (Rule, error) {
+	if !ruleEnabled(cfg.Review.Enabled) {
+		return nil, nil
+	}
+	r := reviewRule{revisions: cfg.Review.Revisions, comments: cfg.Review.Comments}
+	for _, policy := range []*string{&r.revisions, &r.comments} {
+		if *policy == "" {
+			*policy = config.PolicyWarn
+		}
+		if err := checkPolicy(*policy); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (reviewRule) Name() string { return ruleReview }
+
+// reviewIssue — нарушение с политикой, по которой оно оценивается.
+type reviewIssue struct {
+	code, message, policy string
+}
+
+// Check ищет не принятые правки и неразрешённые примечания: документ в работе,
+// а не окончательная редакция.
+func (r reviewRule) Check(doc *Document) RuleResult {
+	if doc.Format != FormatDOCX {
+		return notApplicable(ruleReview, doc)
+	}
+
+	details := map[string]interface{}{}
+	var issues []reviewIssue
+
+	if len(doc.Revisions) > 0 {
+		counts := make(map[string]int)
+		var authors []string
+		for _, rev := range doc.Revisions {
+			counts[rev.Kind]++
+			authors = appendUnique(authors, rev.Author)
+		}
+		details["revisions"] = counts
+		if len(authors) > 0 {
+			details["revision_authors"] = authors
+		}
+		issues = append(issues, reviewIssue{
+			code:    CodePendingRevisions,
+			message: fmt.Sprintf("в документе есть не принятые правки: %d", len(doc.Revisions)),
+			policy:  r.revisions,
+		})
+	}
+
+	var unresolved []map[string]interface{}
+	var authors []string
+	open := 0
+	for _, c := range doc.Comments {
+		if c.Resolved {
+			continue
+		}
+		open++
+		authors = appendUnique(authors, c.Author)
+		if len(unresolved) < maxReportedComments {
+			unresolved = append(unresolved, map[string]interface{}{"id": c.ID, "part": c.Part})
+		}
+	}
+	details["comments"] = len(doc.Comments)
+	if open > 0 {
+		details["unresolved_comments"] = unresolved
+		if len(authors) > 0 {
+			details["comment_authors"] = authors
+		}
+		issues = append(issues, reviewIssue{
+			code:    CodeUnresolvedComments,
+			message: fmt.Sprintf("в документе есть неразрешённые примечания: %d", open),
+			policy:  r.comments,
+		})
+	}
+
+	for _, policy := range []string{config.PolicyReject, config.PolicyWarn} {
+		var messages []string
+		code := ""
+		for _, issue := range issues {
+			if issue.policy != policy {
+				continue
+			}
+			if code == "" {
+				code = issue.code
+			}
+			messages = append(messages, issue.message)
+		}
+		if code == "" {
+			continue
+		}
+		if policy == config.PolicyReject {
+			return failed(ruleReview, code, strings.Join(messages, "; "), details)
+		}
+		return warning(ruleReview, code, strings.Join(messages, "; "), details)
+	}
+	return passed(ruleReview, details)
+}
+
+// appendUnique добавляет непустое значение, сохраняя список отсортированным.
+func appendUnique(list []string, value string) []string {
+	if value == "" {
+		return list
+	}
+	i := sort.SearchStrings(list, value)
+	if i < len(list) && list[i] == value {
+		return list
+	}
+	return append(list[:i], append([]string{value}, list[i:]...)...)
+}
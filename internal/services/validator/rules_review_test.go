@@ -0,0 +1,47 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+func TestReview_PendingRevisions(t *testing.T) {
+	payload := createDOCX(`<w:p><w:r><w:t>Цена </w:t></w:r><w:del w:id="1" w:author="Петров"><w:r><w:delText>100</w:delText></w:r></w:del><w:ins w:id="2" w:author="Иванов"><w:r><w:t>120</w:t></w:r></w:ins></w:p>`, nil)
+
+	res := ruleResult(t, config.ProfileConfig{}, "review", Request{Payload: payload})
+	require.Equal(t, StatusWarning, res.Status)
+	require.Equal(t, CodePendingRevisions, res.Code)
+	require.Equal(t, map[string]int{"insertion": 1, "deletion": 1}, res.Details["revisions"])
+	require.Equal(t, []string{"Иванов", "Петров"}, res.Details["revision_authors"])
+
+	res = ruleResult(t, config.ProfileConfig{Review: config.ReviewConfig{Revisions: "reject"}}, "review", Request{Payload: payload})
+	require.Equal(t, StatusFailed, res.Status)
+
+	// Остальные правила видят текст с принятыми правками.
+	engine, err := profileEngine(config.ProfileConfig{Rules: []string{"phrases"}, Phrases: config.PhrasesConfig{Forbidden: []string{"100"}}})
+	require.NoError(t, err)
+	require.True(t, engine.Run(Request{Payload: payload}).Valid())
+}
+
+func TestReview_UnresolvedComments(t *testing.T) {
+	payload := createDOCXWithHeaderAndComments()
+
+	res := ruleResult(t, config.ProfileConfig{Review: config.ReviewConfig{Comments: "reject"}}, "review", Request{Payload: payload})
+	require.Equal(t, CodeUnresolvedComments, res.Code)
+	require.Equal(t, "в документе есть неразрешённые примечания: 1", res.Message)
+	require.Equal(t, []map[string]interface{}{{"id": "0", "part": "word/comments.xml"}}, res.Details["unresolved_comments"])
+
+	res = ruleResult(t, config.ProfileConfig{Review: config.ReviewConfig{Comments: "allow"}}, "review", Request{Payload: payload})
+	require.Equal(t, StatusPassed, res.Status)
+}
+
+func TestReview_CleanDocumentAndConfig(t *testing.T) {
+	res := ruleResult(t, config.ProfileConfig{Review: config.ReviewConfig{Revisions: "reject", Comments: "reject"}}, "review", Request{Payload: createDOCXWithText("Окончательная редакция")})
+	require.Equal(t, StatusPassed, res.Status)
+
+	_, err := profileEngine(config.ProfileConfig{Review: config.ReviewConfig{Revisions: "forbid"}})
+	require.Error(t, err)
+}
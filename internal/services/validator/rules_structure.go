@@ -0,0 +1,300 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+const (
+	ruleDocumentSize  = "document_size"
+	ruleArchive       = "archive"
+	ruleRequiredParts = "required_parts"
+	ruleSafePaths     = "safe_paths"
+	ruleZipIntegrity  = "zip_integrity"
+	ruleMainPartXML   = "main_part_xml"
+)
+
+var defaultRequiredParts = []string{
+	"[Content_Types].xml",
+	"_rels/.rels",
+	mainPartName,
+}
+
+// standardMainParts — стандартные имена основных частей OOXML; в списке
+// обязательных записей они заменяются фактической основной частью документа.
+var standardMainParts = map[string]string{
+	FormatDOCX: mainPartName,
+	FormatXLSX: workbookPartName,
+	FormatPPTX: presentationPartName,
+}
+
+// formatRequiredParts — обязательные записи остальных ZIP-форматов; настройка
+// requiredParts профиля относится только к DOCX.
+var formatRequiredParts = map[string][]string{
+	FormatODT:  {odfMimetypePart, odfManifestPart, odfContentPart},
+	FormatXLSX: {contentTypesPart, packageRelsPart, workbookPartName},
+	FormatPPTX: {contentTypesPart, packageRelsPart, presentationPartName},
+}
+
+func init() {
+	RegisterRule(ruleDocumentSize, newDocumentSizeRule)
+	RegisterRule(ruleRequiredParts, newRequiredPartsRule)
+	RegisterRule(ruleSafePaths, func(config.ProfileConfig) (Rule, error) { return safePathsRule{}, nil })
+	RegisterRule(ruleZipIntegrity, func(config.ProfileConfig) (Rule, error) { return zipIntegrityRule{}, nil })
+	RegisterRule(ruleMainPartXML, func(config.ProfileConfig) (Rule, error) { return mainPartXMLRule{}, nil })
+}
+
+type documentSizeRule struct {
+	maxBytes int64
+}
+
+func newDocumentSizeRule(cfg config.ProfileConfig) (Rule, error) {
+	if cfg.MaxDocumentBytes <= 0 {
+		return nil, nil
+	}
+	return documentSizeRule{maxBytes: cfg.MaxDocumentBytes}, nil
+}
+
+func (documentSizeRule) Name() string { return ruleDocumentSize }
+
+func (r documentSizeRule) Check(doc *Document) RuleResult {
+	details := map[string]interface{}{"size": doc.Size, "max_size": r.maxBytes}
+	if doc.Size > r.maxBytes {
+		return failed(ruleDocumentSize, CodeDocumentTooLarge,
+			fmt.Sprintf("размер документа %d байт превышает допустимые %d байт", doc.Size, r.maxBytes), details)
+	}
+	return passed(ruleDocumentSize, details)
+}
+
+type requiredPartsRule struct {
+	parts []string
+}
+
+func newRequiredPartsRule(cfg config.ProfileConfig) (Rule, error) {
+	parts := cfg.RequiredParts
+	if len(parts) == 0 {
+		parts = defaultRequiredParts
+	}
+	return requiredPartsRule{parts: parts}, nil
+}
+
+func (r requiredPartsRule) Name() string { return ruleRequiredParts }
+
+func (r requiredPartsRule) Check(doc *Document) RuleResult {
+	if doc.Archive == nil {
+		return notApplicable(ruleRequiredParts, doc)
+	}
+	parts := r.parts
+	if formatParts, ok := formatRequiredParts[doc.Format]; ok {
+		parts = formatParts
+	}
+	var missing []string
+	for _, name := range parts {
+		// Основная часть указывается в _rels/.rels и может лежать не на стандартном месте.
+		if name == standardMainParts[doc.Format] && doc.MainPart != "" {
+			name = doc.MainPart
+		}
+		if !doc.HasEntry(name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return failed(ruleRequiredParts, CodeMissingPart,
+			fmt.Sprintf("отсутствует обязательный файл: %s", strings.Join(missing, ", ")),
+			map[string]interface{}{"missing": missing})
+	}
+	return passed(ruleRequiredParts, nil)
+}
+
+// Виды нарушений в details.findings правил safe_paths и zip_integrity.
+const (
+	issueAbsolutePath   = "absolute_path"
+	issueBackslash      = "backslash"
+	issueDotSegment     = "dot_segment"
+	issueNulByte        = "nul_byte"
+	issueDuplicate      = "duplicate_entry"
+	issueCaseCollision  = "case_collision"
+	issueEncrypted      = "encrypted_entry"
+	issueHeaderMismatch = "header_mismatch"
+	issueOverlap        = "overlapping_entries"
+	issueCentralDir     = "central_directory"
+)
+
+type finding struct {
+	path, issue, detail string
+}
+
+func findingDetails(findings []finding) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(findings))
+	for _, f := range findings {
+		item := map[string]interface{}{"path": f.path, "issue": f.issue}
+		if f.detail != "" {
+			item["detail"] = f.detail
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+func describeFindings(findings []finding) string {
+	msg := findings[0].path
+	if findings[0].detail != "" {
+		msg += " (" + findings[0].detail + ")"
+	}
+	if len(findings) > 1 {
+		msg += fmt.Sprintf(" и ещё %d", len(findings)-1)
+	}
+	return msg
+}
+
+type safePathsRule struct{}
+
+func (safePathsRule) Name() string { return ruleSafePaths }
+
+// Check проверяет имена всех записей: распаковщики по-разному трактуют
+// абсолютные пути, обратные слэши, «..» и совпадающие имена, и это позволяет
+// записать файл за пределы каталога или подменить проверенную часть.
+func (safePathsRule) Check(doc *Document) RuleResult {
+	if doc.Archive == nil {
+		return notApplicable(ruleSafePaths, doc)
+	}
+	var findings []finding
+	seen := make(map[string]bool, len(doc.Archive.File))
+	folded := make(map[string]string, len(doc.Archive.File))
+	for _, file := range doc.Archive.File {
+		name := file.Name
+		if issue, detail := unsafePath(name); issue != "" {
+			findings = append(findings, finding{path: name, issue: issue, detail: detail})
+		}
+		if seen[name] {
+			findings = append(findings, finding{path: name, issue: issueDuplicate, detail: "повторяющаяся запись"})
+			continue
+		}
+		seen[name] = true
+		lower := strings.ToLower(name)
+		if other, ok := folded[lower]; ok {
+			findings = append(findings, finding{path: name, issue: issueCaseCollision, detail: "совпадает с " + other + " без учёта регистра"})
+			continue
+		}
+		folded[lower] = name
+	}
+	if len(findings) > 0 {
+		return failed(ruleSafePaths, CodeSuspiciousPath,
+			fmt.Sprintf("подозрительный путь в ZIP: %s", describeFindings(findings)),
+			map[string]interface{}{"path": findings[0].path, "findings": findingDetails(findings)})
+	}
+	return passed(ruleSafePaths, nil)
+}
+
+func unsafePath(name string) (issue, detail string) {
+	switch {
+	case strings.ContainsRune(name, 0):
+		return issueNulByte, "нулевой байт в имени"
+	case strings.HasPrefix(name, "/") || (len(name) >= 2 && name[1] == ':'):
+		return issueAbsolutePath, "абсолютный путь"
+	case strings.Contains(name, "\\"):
+		return issueBackslash, "обратный слэш в пути"
+	}
+	for _, segment := range strings.Split(name, "/") {
+		if segment == ".." {
+			return issueDotSegment, "выход за пределы каталога"
+		}
+	}
+	return "", ""
+}
+
+type zipIntegrityRule struct{}
+
+func (zipIntegrityRule) Name() string { return ruleZipIntegrity }
+
+// Check сверяет центральный каталог с локальными заголовками, ищет
+// перекрывающиеся записи (приём «нерекурсивной» ZIP-бомбы) и зашифрованные записи,
+// содержимое которых проверить нельзя.
+func (zipIntegrityRule) Check(doc *Document) RuleResult {
+	if doc.Archive == nil || doc.raw == nil {
+		return notApplicable(ruleZipIntegrity, doc)
+	}
+	entries, err := readCentralDirectory(doc.raw)
+	if err != nil {
+		return failed(ruleZipIntegrity, CodeInconsistentArchive, fmt.Sprintf("несогласованный ZIP: %v", err),
+			map[string]interface{}{"findings": findingDetails([]finding{{issue: issueCentralDir, detail: err.Error()}})})
+	}
+
+	var encrypted, findings []finding
+	type span struct {
+		name       string
+		start, end uint64
+	}
+	var spans []span
+	for _, e := range entries {
+		name := string(e.Name)
+		if e.Flags&flagEncrypted != 0 {
+			encrypted = append(encrypted, finding{path: name, issue: issueEncrypted, detail: "запись зашифрована"})
+		}
+		local, err := readLocalHeader(doc.raw, e.HeaderOffset)
+		if err != nil {
+			findings = append(findings, finding{path: name, issue: issueHeaderMismatch, detail: err.Error()})
+			continue
+		}
+		if detail := headerMismatch(e, local); detail != "" {
+			findings = append(findings, finding{path: name, issue: issueHeaderMismatch, detail: detail})
+		}
+		if !within(doc.raw, local.DataOffset, e.CompressedSize) {
+			findings = append(findings, finding{path: name, issue: issueHeaderMismatch, detail: "данные записи выходят за пределы файла"})
+			continue
+		}
+		spans = append(spans, span{name: name, start: e.HeaderOffset, end: local.DataOffset + e.CompressedSize})
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	// Сравнение идёт с записью, заканчивающейся дальше всех: длинная запись
+	// может накрывать несколько коротких подряд.
+	var furthest span
+	for i, s := range spans {
+		if i > 0 && s.start < furthest.end {
+			findings = append(findings, finding{path: s.name, issue: issueOverlap, detail: "данные пересекаются с " + furthest.name})
+		}
+		if s.end > furthest.end {
+			furthest = s
+		}
+	}
+
+	switch {
+	case len(encrypted) > 0:
+		return failed(ruleZipIntegrity, CodeEncryptedEntry,
+			fmt.Sprintf("зашифрованная запись в ZIP: %s", describeFindings(encrypted)),
+			map[string]interface{}{"findings": findingDetails(append(encrypted, findings...))})
+	case len(findings) > 0:
+		return failed(ruleZipIntegrity, CodeInconsistentArchive,
+			fmt.Sprintf("несогласованный ZIP: %s", describeFindings(findings)),
+			map[string]interface{}{"findings": findingDetails(findings)})
+	}
+	return passed(ruleZipIntegrity, map[string]interface{}{"entries": len(entries)})
+}
+
+type mainPartXMLRule struct{}
+
+func (mainPartXMLRule) Name() string { return ruleMainPartXML }
+
+func (mainPartXMLRule) Check(doc *Document) RuleResult {
+	switch {
+	case doc.Archive == nil:
+		return notApplicable(ruleMainPartXML, doc)
+	case errors.Is(doc.MainPartErr, errPartMissing):
+		return skipped(ruleMainPartXML, fmt.Sprintf("%s отсутствует", doc.MainPart))
+	case doc.MainPartErr != nil:
+		return failed(ruleMainPartXML, CodeInvalidXML, doc.MainPartErr.Error(), map[string]interface{}{"part": doc.MainPart})
+	}
+	if len(doc.PartErrors) > 0 {
+		return passed(ruleMainPartXML, map[string]interface{}{"unreadable_parts": partErrorNames(doc.PartErrors)})
+	}
+	return passed(ruleMainPartXML, nil)
+}
+
+// notApplicable — результат правил, проверяющих ZIP-пакет, для документов без архива.
+func notApplicable(rule string, doc *Document) RuleResult {
+	return skipped(rule, fmt.Sprintf("неприменимо к формату %s", doc.Format))
+}
@@ -0,0 +1,266 @@
+package validator
+
+import (
+	"archive/zip"
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+func ruleNames(report *ValidationReport) []string {
+	var names []string
+	for _, res := range report.Results {
+		names = append(names, res.Rule)
+	}
+	return names
+}
+
+func profileEngine(profile config.ProfileConfig) (*Engine, error) {
+	return NewEngine(config.ValidationConfig{ProfileConfig: profile})
+}
+
+func TestNewEngine_DefaultRules(t *testing.T) {
+	engine, err := NewEngine(config.ValidationConfig{})
+	require.NoError(t, err)
+	// document_size включается только при заданном maxDocumentBytes, language — при
+	// заданном основном языке, date_constraints — при заданных ограничениях,
+	// metadata_dates — при metadata.enabled, requisites — при requisites.enabled,
+	// metadata_author — при списках авторов или организаций.
+	optIn := map[string]bool{"document_size": true, "language": true, "date_constraints": true, "metadata_dates": true, "metadata_author": true, "phrases": true, "requisites": true}
+	var want []string
+	for _, name := range DefaultRules {
+		if !optIn[name] {
+			want = append(want, name)
+		}
+	}
+	require.Equal(t, want, engine.RuleNames(config.DefaultProfileName))
+}
+
+func TestNewEngine_UnknownRule(t *testing.T) {
+	_, err := profileEngine(config.ProfileConfig{Rules: []string{"cyrillic_ratio", "no_such_rule"}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no_such_rule")
+}
+
+func TestNewEngine_DuplicateRule(t *testing.T) {
+	_, err := profileEngine(config.ProfileConfig{Rules: []string{"date_span", "date_span"}})
+	require.Error(t, err)
+}
+
+func TestEngine_SubsetAndOrder(t *testing.T) {
+	engine, err := profileEngine(config.ProfileConfig{Rules: []string{"date_span", "cyrillic_ratio"}})
+	require.NoError(t, err)
+
+	report := engine.Run(Request{Payload: createDOCXWithoutDates()})
+	require.Equal(t, []string{"archive", "date_span", "cyrillic_ratio"}, ruleNames(report))
+	require.Equal(t, CodeNoDates, report.FirstFailureCode())
+
+	engine, err = profileEngine(config.ProfileConfig{Rules: []string{"cyrillic_ratio"}})
+	require.NoError(t, err)
+	require.True(t, engine.Run(Request{Payload: createDOCXWithoutDates()}).Valid())
+}
+
+func TestEngine_ConfiguredRequiredParts(t *testing.T) {
+	engine, err := profileEngine(config.ProfileConfig{
+		Rules:         []string{"required_parts"},
+		RequiredParts: []string{"word/document.xml", "docProps/core.xml"},
+	})
+	require.NoError(t, err)
+
+	report := engine.Run(Request{Payload: createValidDOCXPayload()})
+	res, ok := report.Result("required_parts")
+	require.True(t, ok)
+	require.Equal(t, CodeMissingPart, res.Code)
+	require.Equal(t, []string{"docProps/core.xml"}, res.Details["missing"])
+}
+
+type markerRule struct{}
+
+func (markerRule) Name() string { return "test_marker" }
+
+func (markerRule) Check(doc *Document) RuleResult {
+	if doc.HasEntry("word/../evil.txt") {
+		return RuleResult{Rule: "test_marker", Status: StatusFailed, Code: "marker", Message: "найден маркер"}
+	}
+	return RuleResult{Rule: "test_marker", Status: StatusPassed}
+}
+
+func TestRegisterRule_CustomRule(t *testing.T) {
+	RegisterRule("test_marker", func(config.ProfileConfig) (Rule, error) { return markerRule{}, nil })
+	defer delete(registry, "test_marker")
+
+	engine, err := profileEngine(config.ProfileConfig{Rules: []string{"test_marker"}})
+	require.NoError(t, err)
+
+	require.True(t, engine.Run(Request{Payload: createValidDOCXPayload()}).Valid())
+	require.Equal(t, "marker", engine.Run(Request{Payload: createDOCXWithSuspiciousPath()}).FirstFailureCode())
+}
+
+// testContentTypes и testPackageRels — минимальные [Content_Types].xml и _rels/.rels тестовых пакетов.
+const testContentTypes = `<?xml version="1.0" encoding="UTF-8"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/></Types>`
+
+var testPackageRels = packageRels("word/document.xml")
+
+func createDOCXWithText(text string) []byte {
+	return createDOCX(`<w:p><w:r><w:t>`+text+`</w:t></w:r></w:p>`, nil)
+}
+
+// createDOCX собирает DOCX с содержимым body внутри w:body; записи extra
+// добавляются к стандартным частям или заменяют их.
+func createDOCX(body string, extra map[string]string) []byte {
+	files := map[string]string{
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         testPackageRels,
+		"word/document.xml":   `<?xml version="1.0" encoding="UTF-8"?><w:document ` + wordNS + `><w:body>` + body + `</w:body></w:document>`,
+	}
+	for name, content := range extra {
+		files[name] = content
+	}
+	return buildZip(files)
+}
+
+// buildZip записывает части в порядке имён, чтобы порядок записей в архиве,
+// а с ним и порядок находок, не зависел от обхода map.
+func buildZip(files map[string]string) []byte {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range names {
+		w, _ := zw.Create(name)
+		w.Write([]byte(files[name]))
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+// ruleResult выполняет одно правило rule с настройками профиля profile.
+func ruleResult(t *testing.T, profile config.ProfileConfig, rule string, req Request) RuleResult {
+	t.Helper()
+	profile.Rules = []string{rule}
+	engine, err := profileEngine(profile)
+	require.NoError(t, err)
+	res, ok := engine.Run(req).Result(rule)
+	require.True(t, ok, "правило %s не выполнялось", rule)
+	return res
+}
+
+func configWithRules(rules ...string) config.ProfileConfig {
+	return config.ProfileConfig{Rules: rules}
+}
+
+func TestCyrillicRatio_ConfiguredThreshold(t *testing.T) {
+	payload := createDOCXWithText("Договор поставки Supply agreement от 01.02.2024")
+
+	strict, err := profileEngine(config.ProfileConfig{Rules: []string{"cyrillic_ratio"}})
+	require.NoError(t, err)
+	require.Equal(t, CodeCyrillicRatioLow, strict.Run(Request{Payload: payload}).FirstFailureCode())
+
+	relaxed, err := profileEngine(config.ProfileConfig{
+		Rules:    []string{"cyrillic_ratio"},
+		Cyrillic: config.CyrillicRuleConfig{MinPercent: 50},
+	})
+	require.NoError(t, err)
+	report := relaxed.Run(Request{Payload: payload})
+	require.True(t, report.Valid())
+	res, _ := report.Result("cyrillic_ratio")
+	require.Equal(t, 50.0, res.Details["required_percent"])
+}
+
+func TestCyrillicRatio_InvalidThreshold(t *testing.T) {
+	_, err := profileEngine(config.ProfileConfig{Cyrillic: config.CyrillicRuleConfig{MinPercent: 120}})
+	require.Error(t, err)
+}
+
+func TestDisabledRulesAreNotRun(t *testing.T) {
+	disabled := false
+	engine, err := profileEngine(config.ProfileConfig{
+		Cyrillic:   config.CyrillicRuleConfig{Enabled: &disabled},
+		Dates:      config.DateRuleConfig{Enabled: &disabled},
+		Metadata:   config.MetadataRuleConfig{Enabled: &disabled},
+		PII:        config.PIIConfig{Enabled: &disabled},
+		Requisites: config.RequisitesConfig{Enabled: &disabled},
+		Review:     config.ReviewConfig{Enabled: &disabled},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"required_parts", "safe_paths", "zip_integrity", "package_consistency", "active_content", "xml_wellformed", "main_part_xml"}, engine.RuleNames(config.DefaultProfileName))
+	require.True(t, engine.Run(Request{Payload: createDOCXWithText("English only")}).Valid())
+}
+
+func TestDateSpan_CalendarAccurate(t *testing.T) {
+	engine, err := profileEngine(config.ProfileConfig{Rules: []string{"date_span"}})
+	require.NoError(t, err)
+
+	// Ровно три календарных года с учётом високосного 2020 — 1096 дней.
+	require.True(t, engine.Run(Request{Payload: createDOCXWithText("Срок с 01.01.2020 по 01.01.2023")}).Valid())
+
+	report := engine.Run(Request{Payload: createDOCXWithText("Срок с 01.01.2020 по 02.01.2023")})
+	res, _ := report.Result("date_span")
+	require.Equal(t, CodeDateSpanExceeded, res.Code)
+	require.Contains(t, res.Message, "3 года")
+}
+
+func TestDateSpan_ConfiguredMonths(t *testing.T) {
+	engine, err := profileEngine(config.ProfileConfig{
+		Rules: []string{"date_span"},
+		Dates: config.DateRuleConfig{MaxSpan: config.CalendarSpan{Months: 6}},
+	})
+	require.NoError(t, err)
+
+	require.True(t, engine.Run(Request{Payload: createDOCXWithText("с 31.01.2024 по 31.07.2024")}).Valid())
+
+	res, _ := engine.Run(Request{Payload: createDOCXWithText("с 31.01.2024 по 01.08.2024")}).Result("date_span")
+	require.Equal(t, CodeDateSpanExceeded, res.Code)
+	require.Equal(t, "6m", res.Details["max_span"])
+	require.Contains(t, res.Message, "6 месяцев")
+}
+
+func TestEngine_Profiles(t *testing.T) {
+	engine, err := NewEngine(config.ValidationConfig{
+		Profiles: map[string]config.ProfileConfig{
+			"bilingual": {Cyrillic: config.CyrillicRuleConfig{MinPercent: 50}},
+			"small":     {MaxDocumentBytes: 16},
+		},
+		Tenants: map[string]string{"tenant-42": "bilingual"},
+	})
+	require.NoError(t, err)
+
+	payload := createDOCXWithText("Договор поставки Supply agreement от 01.02.2024")
+
+	report := engine.Run(Request{Payload: payload})
+	require.Equal(t, config.DefaultProfileName, report.Profile)
+	require.Equal(t, CodeCyrillicRatioLow, report.FirstFailureCode())
+
+	report = engine.Run(Request{Payload: payload, Profile: "bilingual"})
+	require.Equal(t, "bilingual", report.Profile)
+	require.True(t, report.Valid())
+
+	report = engine.Run(Request{Payload: payload, TenantID: "tenant-42"})
+	require.Equal(t, "bilingual", report.Profile)
+	require.True(t, report.Valid())
+
+	report = engine.Run(Request{Payload: payload, TenantID: "tenant-without-profile"})
+	require.Equal(t, config.DefaultProfileName, report.Profile)
+
+	report = engine.Run(Request{Payload: createValidDOCXPayload(), Profile: "small"})
+	require.Equal(t, CodeDocumentTooLarge, report.FirstFailureCode())
+
+	report = engine.Run(Request{Payload: payload, Profile: "missing"})
+	require.Equal(t, CodeUnknownProfile, report.FirstFailureCode())
+	require.Equal(t, []string{"profile"}, ruleNames(report))
+}
+
+func TestNewEngine_TenantWithUnknownProfile(t *testing.T) {
+	_, err := NewEngine(config.ValidationConfig{Tenants: map[string]string{"tenant-1": "nope"}})
+	require.Error(t, err)
+}
@@ -0,0 +1,221 @@
+package validator
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+const (
+	ruleCyrillicRatio = "cyrillic_ratio"
+	ruleDateSpan      = "date_span"
+)
+
+const defaultCyrillicMinPercent = 90
+
+var defaultMaxDateSpan = config.CalendarSpan{Years: 3}
+
+func init() {
+	RegisterRule(ruleCyrillicRatio, newCyrillicRatioRule)
+	RegisterRule(ruleDateSpan, newDateSpanRule)
+}
+
+func ruleEnabled(flag *bool) bool {
+	return flag == nil || *flag
+}
+
+func checkSources(parts []string) error {
+	for _, p := range parts {
+		if !isKnownSource(p) {
+			return fmt.Errorf("неизвестный источник текста: %s", p)
+		}
+	}
+	return nil
+}
+
+type cyrillicRatioRule struct {
+	minPercent float64
+	parts      []string
+}
+
+func newCyrillicRatioRule(cfg config.ProfileConfig) (Rule, error) {
+	if !ruleEnabled(cfg.Cyrillic.Enabled) {
+		return nil, nil
+	}
+	minPercent := cfg.Cyrillic.MinPercent
+	if minPercent == 0 {
+		minPercent = defaultCyrillicMinPercent
+	}
+	if minPercent < 0 || minPercent > 100 {
+		return nil, fmt.Errorf("minPercent должен быть в диапазоне (0, 100], получено %g", minPercent)
+	}
+	if err := checkSources(cfg.Cyrillic.Parts); err != nil {
+		return nil, err
+	}
+	return cyrillicRatioRule{minPercent: minPercent, parts: cfg.Cyrillic.Parts}, nil
+}
+
+func (cyrillicRatioRule) Name() string { return ruleCyrillicRatio }
+
+func (r cyrillicRatioRule) Check(doc *Document) RuleResult {
+	text := doc.TextFrom(r.parts)
+	if len(text) == 0 {
+		return failed(ruleCyrillicRatio, CodeNoText, "в документе не найден текст", nil)
+	}
+	cyrillicCount := 0
+	totalChars := 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			totalChars++
+			if unicode.Is(unicode.Cyrillic, r) {
+				cyrillicCount++
+			}
+		}
+	}
+	if totalChars == 0 {
+		return failed(ruleCyrillicRatio, CodeNoLetters, "в документе не найдены буквы", nil)
+	}
+	percentage := float64(cyrillicCount) / float64(totalChars) * 100
+	details := map[string]interface{}{
+		"cyrillic_percent": math.Round(percentage*100) / 100,
+		"required_percent": r.minPercent,
+		"letters":          totalChars,
+	}
+	if percentage < r.minPercent {
+		return failed(ruleCyrillicRatio, CodeCyrillicRatioLow,
+			fmt.Sprintf("документ содержит только %.2f%% кириллицы, требуется %g%%", percentage, r.minPercent), details)
+	}
+	return passed(ruleCyrillicRatio, details)
+}
+
+type dateSpanRule struct {
+	maxSpan   config.CalendarSpan
+	parts     []string
+	dates     dateExtractor
+	ambiguous string
+}
+
+func newDateSpanRule(cfg config.ProfileConfig) (Rule, error) {
+	if !ruleEnabled(cfg.Dates.Enabled) {
+		return nil, nil
+	}
+	maxSpan := cfg.Dates.MaxSpan
+	if maxSpan.IsZero() {
+		maxSpan = defaultMaxDateSpan
+	}
+	if maxSpan.Years < 0 || maxSpan.Months < 0 || maxSpan.Days < 0 {
+		return nil, fmt.Errorf("maxSpan не может быть отрицательным: %s", maxSpan)
+	}
+	if err := checkSources(cfg.Dates.Parts); err != nil {
+		return nil, err
+	}
+	dates, err := newDateExtractor(cfg.Dates)
+	if err != nil {
+		return nil, err
+	}
+	ambiguous := cfg.Dates.Ambiguous
+	switch ambiguous {
+	case "":
+		ambiguous = config.AmbiguousDatesLocale
+	case config.AmbiguousDatesLocale, config.AmbiguousDatesIgnore, config.AmbiguousDatesBoth, config.AmbiguousDatesReject:
+	default:
+		return nil, fmt.Errorf("неизвестный режим ambiguous %q, допустимы locale, ignore, both, reject", ambiguous)
+	}
+	return dateSpanRule{maxSpan: maxSpan, parts: cfg.Dates.Parts, dates: dates, ambiguous: ambiguous}, nil
+}
+
+func (dateSpanRule) Name() string { return ruleDateSpan }
+
+func (r dateSpanRule) Check(doc *Document) RuleResult {
+	matches := r.dates.find(doc.TextFrom(r.parts))
+
+	var dates []time.Time
+	var ambiguous []dateMatch
+	for _, m := range matches {
+		if m.Ambiguous {
+			ambiguous = append(ambiguous, m)
+			switch r.ambiguous {
+			case config.AmbiguousDatesIgnore:
+				continue
+			case config.AmbiguousDatesBoth:
+				dates = append(dates, m.Alternative)
+			}
+		}
+		dates = append(dates, m.Date)
+	}
+
+	if len(ambiguous) > 0 && r.ambiguous == config.AmbiguousDatesReject {
+		first := ambiguous[0]
+		return failed(ruleDateSpan, CodeAmbiguousDate,
+			fmt.Sprintf("дата «%s» неоднозначна: %s или %s", first.Text, first.Date.Format("02.01.2006"), first.Alternative.Format("02.01.2006")),
+			map[string]interface{}{"ambiguous_dates": dateDetails(ambiguous)})
+	}
+	if len(dates) == 0 {
+		return failed(ruleDateSpan, CodeNoDates, "в документе не найдены допустимые даты", nil)
+	}
+
+	minDate := dates[0]
+	maxDate := dates[0]
+	for _, d := range dates {
+		if d.Before(minDate) {
+			minDate = d
+		}
+		if d.After(maxDate) {
+			maxDate = d
+		}
+	}
+
+	details := map[string]interface{}{
+		"dates_found": len(matches),
+		"min_date":    minDate.Format("2006-01-02"),
+		"max_date":    maxDate.Format("2006-01-02"),
+		"max_span":    r.maxSpan.String(),
+		"dates":       dateDetails(matches),
+	}
+	if len(ambiguous) > 0 {
+		details["ambiguous_dates"] = dateDetails(ambiguous)
+	}
+
+	if maxDate.After(r.maxSpan.AddTo(minDate)) {
+		return failed(ruleDateSpan, CodeDateSpanExceeded,
+			fmt.Sprintf("даты в документе отличаются более чем на %s (min: %s, max: %s)", formatSpanRu(r.maxSpan), minDate.Format("02.01.2006"), maxDate.Format("02.01.2006")),
+			details)
+	}
+
+	return passed(ruleDateSpan, details)
+}
+
+func formatSpanRu(span config.CalendarSpan) string {
+	var parts []string
+	if span.Years != 0 {
+		parts = append(parts, fmt.Sprintf("%d %s", span.Years, pluralRu(span.Years, "год", "года", "лет")))
+	}
+	if span.Months != 0 {
+		parts = append(parts, fmt.Sprintf("%d %s", span.Months, pluralRu(span.Months, "месяц", "месяца", "месяцев")))
+	}
+	if span.Days != 0 || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%d %s", span.Days, pluralRu(span.Days, "день", "дня", "дней")))
+	}
+	return strings.Join(parts, " ")
+}
+
+func pluralRu(n int, one, few, many string) string {
+	n %= 100
+	if n < 0 {
+		n = -n
+	}
+	if n >= 11 && n <= 14 {
+		return many
+	}
+	switch n % 10 {
+	case 1:
+		return one
+	case 2, 3, 4:
+		return few
+	}
+	return many
+}
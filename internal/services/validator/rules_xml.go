@@ -0,0 +1,320 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+const (
+	ruleXMLWellFormed   = "xml_wellformed"
+	ruleWordMLStructure = "wordml_structure"
+)
+
+// Виды нарушений в details.findings правил xml_wellformed и wordml_structure.
+const (
+	issueMalformedXML      = "malformed_xml"
+	issueDTD               = "dtd"
+	issueUnexpectedRoot    = "unexpected_root"
+	issueMissingBody       = "missing_body"
+	issueMultipleBodies    = "multiple_bodies"
+	issueMisplacedSectPr   = "misplaced_sectpr"
+	issueInvalidPageSize   = "invalid_page_size"
+	issueInvalidPageMargin = "invalid_page_margin"
+)
+
+var errDTD = errors.New("объявление DTD запрещено")
+
+// maxPageTwips — наибольшая сторона страницы по ECMA-376 (22 дюйма).
+const maxPageTwips = 31680
+
+func init() {
+	RegisterRule(ruleXMLWellFormed, func(config.ProfileConfig) (Rule, error) { return xmlWellFormedRule{}, nil })
+	RegisterRule(ruleWordMLStructure, func(config.ProfileConfig) (Rule, error) { return wordMLStructureRule{}, nil })
+}
+
+// checkXML читает часть целиком и возвращает корневой элемент. Кроме ошибок
+// разборщика отклоняются DOCTYPE (внешние сущности, «миллиард смехов»), второй
+// корневой элемент и текст вне корня — encoding/xml их пропускает.
+func checkXML(r io.Reader) (xml.Name, error) {
+	var root xml.Name
+	depth, closed := 0, false
+	dec := newXMLDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return root, err
+		}
+		switch t := tok.(type) {
+		case xml.Directive:
+			if directive := bytes.TrimSpace(t); bytes.HasPrefix(directive, []byte("DOCTYPE")) || bytes.HasPrefix(directive, []byte("ENTITY")) {
+				return root, errDTD
+			}
+		case xml.StartElement:
+			if closed {
+				return root, errors.New("второй корневой элемент " + t.Name.Local)
+			}
+			if depth == 0 {
+				root = t.Name
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+			closed = depth == 0
+		case xml.CharData:
+			if depth == 0 && len(bytes.TrimSpace(t)) > 0 {
+				return root, errors.New("текст вне корневого элемента")
+			}
+		}
+	}
+	if root.Local == "" {
+		return root, errNoRootElement
+	}
+	return root, nil
+}
+
+func isXMLPart(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasSuffix(lower, ".xml") || strings.HasSuffix(lower, ".rels")
+}
+
+// expectedRoot возвращает локальное имя корня для частей, чей вид известен по имени.
+// Пространства имён не сравниваются: они различаются в переходной и строгой схемах.
+func expectedRoot(doc *Document, name string) string {
+	switch {
+	case name == contentTypesPart:
+		return "Types"
+	case strings.HasSuffix(name, ".rels"):
+		return "Relationships"
+	case name == odfManifestPart:
+		return "manifest"
+	case name != doc.MainPart:
+		return ""
+	}
+	switch doc.Format {
+	case FormatDOCX:
+		return "document"
+	case FormatXLSX:
+		return "workbook"
+	case FormatPPTX:
+		return "presentation"
+	case FormatODT:
+		return "document-content"
+	}
+	return ""
+}
+
+type xmlWellFormedRule struct{}
+
+func (xmlWellFormedRule) Name() string { return ruleXMLWellFormed }
+
+// Check проверяет каждую XML-часть пакета, а не только те, что нужны для текста:
+// повреждённая часть иначе всплывает позже, в обработке у потребителей.
+func (xmlWellFormedRule) Check(doc *Document) RuleResult {
+	if doc.Archive == nil || doc.parts == nil {
+		return notApplicable(ruleXMLWellFormed, doc)
+	}
+	var findings []finding
+	var limited error
+	checked, dtd := 0, false
+	for _, file := range doc.Archive.File {
+		if !isXMLPart(file.Name) {
+			continue
+		}
+		root, err := checkXMLPart(doc.parts, file.Name)
+		if errors.Is(err, errResourceLimit) {
+			// Дальше читать нельзя, но уже найденное теряться не должно.
+			limited = err
+			break
+		}
+		switch {
+		case errors.Is(err, errDTD):
+			dtd = true
+			findings = append(findings, finding{path: file.Name, issue: issueDTD, detail: err.Error()})
+		case err != nil:
+			findings = append(findings, finding{path: file.Name, issue: issueMalformedXML, detail: err.Error()})
+		default:
+			if want := expectedRoot(doc, file.Name); want != "" && root.Local != want {
+				findings = append(findings, finding{path: file.Name, issue: issueUnexpectedRoot,
+					detail: fmt.Sprintf("корневой элемент %s, ожидался %s", root.Local, want)})
+			}
+		}
+		checked++
+	}
+
+	details := map[string]interface{}{"parts": checked}
+	if limited != nil {
+		details["stopped"] = limited.Error()
+	}
+	if len(findings) == 0 {
+		if limited != nil {
+			return failed(ruleXMLWellFormed, CodeResourceLimitExceeded,
+				fmt.Sprintf("проверка XML-частей остановлена: %v", limited), details)
+		}
+		return passed(ruleXMLWellFormed, details)
+	}
+	details["findings"] = findingDetails(findings)
+	if dtd {
+		return failed(ruleXMLWellFormed, CodeForbiddenDTD,
+			fmt.Sprintf("XML-часть содержит DTD: %s", describeFindings(findings)), details)
+	}
+	return failed(ruleXMLWellFormed, CodeInvalidXML,
+		fmt.Sprintf("некорректная XML-часть: %s", describeFindings(findings)), details)
+}
+
+func checkXMLPart(reader fs.FS, name string) (xml.Name, error) {
+	f, err := reader.Open(name)
+	if err != nil {
+		return xml.Name{}, err
+	}
+	defer f.Close()
+	return checkXML(f)
+}
+
+type wordMLStructureRule struct{}
+
+func (wordMLStructureRule) Name() string { return ruleWordMLStructure }
+
+// Check проверяет каркас основной части DOCX: ровно один w:body, w:sectPr
+// уровня тела — последним дочерним элементом, размеры и поля страницы — целые
+// числа в допустимых пределах. Правило не входит в набор по умолчанию.
+func (wordMLStructureRule) Check(doc *Document) RuleResult {
+	switch {
+	case doc.Format != FormatDOCX || doc.parts == nil:
+		return notApplicable(ruleWordMLStructure, doc)
+	case doc.MainPartErr != nil:
+		return skipped(ruleWordMLStructure, fmt.Sprintf("%s не разобран", doc.MainPart))
+	}
+	f, err := doc.parts.Open(doc.MainPart)
+	if err != nil {
+		return skipped(ruleWordMLStructure, fmt.Sprintf("%s не разобран", doc.MainPart))
+	}
+	defer f.Close()
+
+	findings, err := checkWordMLStructure(f)
+	if err != nil {
+		return skipped(ruleWordMLStructure, fmt.Sprintf("%s не разобран", doc.MainPart))
+	}
+	for i := range findings {
+		findings[i].path = doc.MainPart
+	}
+	if len(findings) > 0 {
+		return failed(ruleWordMLStructure, CodeInvalidStructure,
+			fmt.Sprintf("нарушена структура документа: %s", describeFindings(findings)),
+			map[string]interface{}{"findings": findingDetails(findings)})
+	}
+	return passed(ruleWordMLStructure, nil)
+}
+
+func checkWordMLStructure(r io.Reader) ([]finding, error) {
+	var findings []finding
+	add := func(issue, detail string) {
+		findings = append(findings, finding{issue: issue, detail: detail})
+	}
+
+	bodies, depth, bodyDepth := 0, 0, 0
+	sectPrLast := false
+	dec := newXMLDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			switch {
+			case depth == 2 && isWordElement(t.Name, "body"):
+				bodies++
+				bodyDepth = depth
+			case bodyDepth > 0 && depth == bodyDepth+1:
+				if sectPrLast {
+					add(issueMisplacedSectPr, "w:sectPr не последний элемент w:body")
+				}
+				sectPrLast = isWordElement(t.Name, "sectPr")
+			}
+			switch {
+			case isWordElement(t.Name, "pgSz"):
+				checkPageSize(t.Attr, add)
+			case isWordElement(t.Name, "pgMar"):
+				checkPageMargins(t.Attr, add)
+			}
+		case xml.EndElement:
+			if depth == bodyDepth {
+				bodyDepth = 0
+			}
+			depth--
+		}
+	}
+	switch {
+	case bodies == 0:
+		add(issueMissingBody, "нет элемента w:body")
+	case bodies > 1:
+		add(issueMultipleBodies, fmt.Sprintf("элементов w:body: %d", bodies))
+	}
+	return findings, nil
+}
+
+// twipsPerUnit — двадцатые доли пункта в единице универсальной меры ECMA-376.
+var twipsPerUnit = map[string]float64{
+	"mm": 1440 / 25.4,
+	"cm": 1440 / 2.54,
+	"in": 1440,
+	"pt": 20,
+	"pc": 240,
+	"pi": 240,
+}
+
+// universalMeasure — шаблон ST_UniversalMeasure из ECMA-376.
+var universalMeasure = regexp.MustCompile(`^(-?[0-9]+(?:\.[0-9]+)?)(mm|cm|in|pt|pc|pi)$`)
+
+// parseTwips разбирает размер в двадцатых долях пункта: целое число или
+// универсальную меру вида «21cm», «1in», «-0.5pt».
+func parseTwips(raw string) (float64, error) {
+	if m := universalMeasure.FindStringSubmatch(raw); m != nil {
+		v, err := strconv.ParseFloat(m[1], 64)
+		return v * twipsPerUnit[m[2]], err
+	}
+	v, err := strconv.Atoi(raw)
+	return float64(v), err
+}
+
+func checkPageSize(attrs []xml.Attr, add func(issue, detail string)) {
+	for _, side := range []string{"w", "h"} {
+		raw := wordAttr(attrs, side)
+		if raw == "" {
+			continue
+		}
+		if v, err := parseTwips(raw); err != nil || v <= 0 || v > maxPageTwips {
+			add(issueInvalidPageSize, fmt.Sprintf("w:pgSz/@w:%s = %q", side, raw))
+		}
+	}
+}
+
+func checkPageMargins(attrs []xml.Attr, add func(issue, detail string)) {
+	for _, side := range []string{"top", "bottom", "left", "right", "header", "footer", "gutter"} {
+		raw := wordAttr(attrs, side)
+		if raw == "" {
+			continue
+		}
+		v, err := parseTwips(raw)
+		// Верхнее и нижнее поля могут быть отрицательными: текст тогда не сдвигается колонтитулом.
+		if err != nil || v > maxPageTwips || (v < 0 && side != "top" && side != "bottom") {
+			add(issueInvalidPageMargin, fmt.Sprintf("w:pgMar/@w:%s = %q", side, raw))
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qnhqn1/file-validator/config"
+)
+
+func TestCheckXML(t *testing.T) {
+	cases := map[string]string{
+		"dtd":         `<?xml version="1.0"?><!DOCTYPE r [<!ENTITY x SYSTEM "file:///etc/passwd">]><r>&x;</r>`,
+		"second root": `<a/><b/>`,
+		"text":        `<a/>tail`,
+		"mismatch":    `<a><b></a>`,
+		"empty":       ``,
+	}
+	for name, input := range cases {
+		_, err := checkXML(strings.NewReader(input))
+		require.Error(t, err, name)
+	}
+	_, err := checkXML(strings.NewReader(cases["dtd"]))
+	require.ErrorIs(t, err, errDTD)
+
+	root, err := checkXML(strings.NewReader("<?xml version=\"1.0\"?>\n<!-- c --><a><b/></a>\n"))
+	require.NoError(t, err)
+	require.Equal(t, "a", root.Local)
+}
+
+func TestXMLWellFormed_ChecksEveryPart(t *testing.T) {
+	payload := createDOCX("", map[string]string{
+		"word/styles.xml":    `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:style></w:styles>`,
+		"customXml/item.xml": `<data/>`,
+	})
+
+	res := ruleResult(t, config.ProfileConfig{}, ruleXMLWellFormed, Request{Payload: payload})
+	require.Equal(t, CodeInvalidXML, res.Code)
+	require.Contains(t, res.Message, "word/styles.xml")
+	require.Equal(t, 5, res.Details["parts"])
+}
+
+func TestXMLWellFormed_RejectsDTD(t *testing.T) {
+	payload := createDOCX("", map[string]string{
+		"word/settings.xml": `<?xml version="1.0"?><!DOCTYPE lolz [<!ENTITY lol "lol">]><w:settings xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"/>`,
+	})
+
+	res := ruleResult(t, config.ProfileConfig{}, ruleXMLWellFormed, Request{Payload: payload})
+	require.Equal(t, CodeForbiddenDTD, res.Code)
+	require.Contains(t, res.Message, "word/settings.xml")
+}
+
+func TestXMLWellFormed_UnexpectedRoot(t *testing.T) {
+	payload := buildZip(map[string]string{
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         `<Types/>`,
+		"word/document.xml":   `<html/>`,
+	})
+
+	res := ruleResult(t, config.ProfileConfig{}, ruleXMLWellFormed, Request{Payload: payload})
+	require.Equal(t, CodeInvalidXML, res.Code)
+	var issues []interface{}
+	for _, f := range res.Details["findings"].([]map[string]interface{}) {
+		issues = append(issues, f["issue"])
+	}
+	require.Equal(t, []interface{}{issueUnexpectedRoot, issueUnexpectedRoot}, issues)
+}
+
+func TestWordMLStructure(t *testing.T) {
+	sectPr := `<w:sectPr><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="-1134" w:right="850" w:bottom="1134" w:left="1701" w:header="708" w:footer="708" w:gutter="0"/></w:sectPr>`
+	para := `<w:p><w:r><w:t>Текст</w:t></w:r></w:p>`
+
+	structure := func(payload []byte) RuleResult {
+		return ruleResult(t, config.ProfileConfig{}, ruleWordMLStructure, Request{Payload: payload})
+	}
+
+	res := structure(createDOCX(para+sectPr, nil))
+	require.Equal(t, StatusPassed, res.Status)
+
+	res = structure(createDOCX(sectPr+para+`</w:body><w:body>`, nil))
+	require.Equal(t, CodeInvalidStructure, res.Code)
+	var issues []interface{}
+	for _, f := range res.Details["findings"].([]map[string]interface{}) {
+		issues = append(issues, f["issue"])
+	}
+	require.Equal(t, []interface{}{issueMisplacedSectPr, issueMultipleBodies}, issues)
+
+	res = structure(createDOCX(`<w:sectPr><w:pgSz w:w="0" w:h="abc"/><w:pgMar w:left="-5"/></w:sectPr>`, nil))
+	require.Contains(t, res.Message, `w:pgSz/@w:w = "0"`)
+	require.Len(t, res.Details["findings"], 3)
+
+	// Размеры в универсальных мерах переводятся в двадцатые доли пункта.
+	res = structure(createDOCX(para+`<w:sectPr><w:pgSz w:w="21cm" w:h="297mm"/><w:pgMar w:top="1in" w:left="-0.5pt" w:right="2pc"/></w:sectPr>`, nil))
+	require.Equal(t, StatusFailed, res.Status)
+	require.Contains(t, res.Message, `w:pgMar/@w:left = "-0.5pt"`)
+	require.Len(t, res.Details["findings"], 1)
+
+	res = structure(createDOCX(para+`<w:sectPr><w:pgSz w:w="23in" w:h="1e3pt"/></w:sectPr>`, nil))
+	require.Len(t, res.Details["findings"], 2)
+
+	res = structure(createDOCX("", map[string]string{"word/document.xml": wordPart("document", "")}))
+	require.Equal(t, issueMissingBody, res.Details["findings"].([]map[string]interface{})[0]["issue"])
+}
+
+func TestXMLWellFormed_KeepsFindingsOnResourceLimit(t *testing.T) {
+	deep := strings.Repeat("<a>", 40) + strings.Repeat("</a>", 40)
+	engine, err := NewEngine(config.ValidationConfig{
+		ProfileConfig: configWithRules(ruleXMLWellFormed),
+		Limits:        config.LimitsConfig{MaxXMLDepth: 32},
+	})
+	require.NoError(t, err)
+
+	// DTD в ранней части, затем часть, на которой кончается бюджет.
+	payload := createDOCX("", map[string]string{
+		"customXml/a.xml": `<?xml version="1.0"?><!DOCTYPE a [<!ENTITY x "x">]><a/>`,
+		"customXml/b.xml": deep,
+	})
+	res, ok := engine.Run(Request{Payload: payload}).Result(ruleXMLWellFormed)
+	require.True(t, ok)
+	require.Equal(t, CodeForbiddenDTD, res.Code)
+	require.Contains(t, res.Message, "customXml/a.xml")
+	require.NotEmpty(t, res.Details["stopped"])
+
+	// Без других находок остановка по лимиту не выдаётся за успешную проверку.
+	payload = createDOCX("", map[string]string{"customXml/b.xml": deep})
+	res, ok = engine.Run(Request{Payload: payload}).Result(ruleXMLWellFormed)
+	require.True(t, ok)
+	require.Equal(t, StatusFailed, res.Status)
+	require.Equal(t, CodeResourceLimitExceeded, res.Code)
+}
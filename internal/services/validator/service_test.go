@@ -4,13 +4,18 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/qnhqn1/file-validator/config"
+	"github.com/qnhqn1/file-validator/internal/domain"
 	"github.com/qnhqn1/file-validator/internal/services/validator/mocks"
 )
 
@@ -19,8 +24,8 @@ func createValidDOCXPayload() []byte {
 	zw := zip.NewWriter(&buf)
 
 	files := map[string]string{
-		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?>`,
-		"_rels/.rels":         `<?xml version="1.0" encoding="UTF-8"?>`,
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         testPackageRels,
 		"word/document.xml":   `<?xml version="1.0" encoding="UTF-8"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>Пример текста на кириллице с датой 27.12.2025</w:t></w:r></w:p></w:body></w:document>`,
 	}
 
@@ -37,8 +42,8 @@ func createInvalidCyrillicDOCXPayload() []byte {
 	zw := zip.NewWriter(&buf)
 
 	files := map[string]string{
-		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?>`,
-		"_rels/.rels":         `<?xml version="1.0" encoding="UTF-8"?>`,
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         testPackageRels,
 		"word/document.xml":   `<?xml version="1.0" encoding="UTF-8"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>This is English text without Cyrillic and no date</w:t></w:r></w:p></w:body></w:document>`,
 	}
 
@@ -55,8 +60,8 @@ func createDOCXWithoutDates() []byte {
 	zw := zip.NewWriter(&buf)
 
 	files := map[string]string{
-		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?>`,
-		"_rels/.rels":         `<?xml version="1.0" encoding="UTF-8"?>`,
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         testPackageRels,
 		"word/document.xml":   `<?xml version="1.0" encoding="UTF-8"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>Пример текста на кириллице без даты</w:t></w:r></w:p></w:body></w:document>`,
 	}
 
@@ -73,8 +78,8 @@ func createDOCXWithLowCyrillic() []byte {
 	zw := zip.NewWriter(&buf)
 
 	files := map[string]string{
-		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?>`,
-		"_rels/.rels":         `<?xml version="1.0" encoding="UTF-8"?>`,
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         testPackageRels,
 		"word/document.xml":   `<?xml version="1.0" encoding="UTF-8"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>Text with some Cyrillic буквы but mostly English words and date 27.12.2025</w:t></w:r></w:p></w:body></w:document>`,
 	}
 
@@ -91,8 +96,8 @@ func createDOCXWithInvalidDate() []byte {
 	zw := zip.NewWriter(&buf)
 
 	files := map[string]string{
-		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?>`,
-		"_rels/.rels":         `<?xml version="1.0" encoding="UTF-8"?>`,
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         testPackageRels,
 		"word/document.xml":   `<?xml version="1.0" encoding="UTF-8"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>Текст на кириллице с невалидной датой 32.13.2025</w:t></w:r></w:p></w:body></w:document>`,
 	}
 
@@ -109,8 +114,8 @@ func createEmptyDOCX() []byte {
 	zw := zip.NewWriter(&buf)
 
 	files := map[string]string{
-		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?>`,
-		"_rels/.rels":         `<?xml version="1.0" encoding="UTF-8"?>`,
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         testPackageRels,
 		"word/document.xml":   `<?xml version="1.0" encoding="UTF-8"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body></w:body></w:document>`,
 	}
 
@@ -127,8 +132,8 @@ func createDOCXWithNumbersOnly() []byte {
 	zw := zip.NewWriter(&buf)
 
 	files := map[string]string{
-		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?>`,
-		"_rels/.rels":         `<?xml version="1.0" encoding="UTF-8"?>`,
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         testPackageRels,
 		"word/document.xml":   `<?xml version="1.0" encoding="UTF-8"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>1234567890</w:t></w:r></w:p></w:body></w:document>`,
 	}
 
@@ -146,7 +151,7 @@ func createDOCXMissingRequiredFile() []byte {
 
 	files := map[string]string{
 
-		"_rels/.rels":       `<?xml version="1.0" encoding="UTF-8"?>`,
+		"_rels/.rels":       testPackageRels,
 		"word/document.xml": `<?xml version="1.0" encoding="UTF-8"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>Текст</w:t></w:r></w:p></w:body></w:document>`,
 	}
 
@@ -163,8 +168,8 @@ func createDOCXWithSuspiciousPath() []byte {
 	zw := zip.NewWriter(&buf)
 
 	files := map[string]string{
-		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?>`,
-		"_rels/.rels":         `<?xml version="1.0" encoding="UTF-8"?>`,
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         testPackageRels,
 		"word/document.xml":   `<?xml version="1.0" encoding="UTF-8"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>Текст</w:t></w:r></w:p></w:body></w:document>`,
 		"word/../evil.txt":    `malicious content`,
 	}
@@ -182,8 +187,8 @@ func createDOCXWithInvalidXML() []byte {
 	zw := zip.NewWriter(&buf)
 
 	files := map[string]string{
-		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?>`,
-		"_rels/.rels":         `<?xml version="1.0" encoding="UTF-8"?>`,
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         testPackageRels,
 		"word/document.xml":   `This is not XML at all, just plain text`,
 	}
 
@@ -200,8 +205,8 @@ func createDOCXWithXMLButNoDocument() []byte {
 	zw := zip.NewWriter(&buf)
 
 	files := map[string]string{
-		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?>`,
-		"_rels/.rels":         `<?xml version="1.0" encoding="UTF-8"?>`,
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         testPackageRels,
 		"word/document.xml":   `<?xml version="1.0" encoding="UTF-8"?><root>Not a document</root>`,
 	}
 
@@ -218,8 +223,8 @@ func createDOCXWithEmptyDocumentXML() []byte {
 	zw := zip.NewWriter(&buf)
 
 	files := map[string]string{
-		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?>`,
-		"_rels/.rels":         `<?xml version="1.0" encoding="UTF-8"?>`,
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         testPackageRels,
 		"word/document.xml":   ``,
 	}
 
@@ -236,8 +241,8 @@ func createDOCXWithMultipleDates() []byte {
 	zw := zip.NewWriter(&buf)
 
 	files := map[string]string{
-		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?>`,
-		"_rels/.rels":         `<?xml version="1.0" encoding="UTF-8"?>`,
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         testPackageRels,
 		"word/document.xml":   `<?xml version="1.0" encoding="UTF-8"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>Текст с датами 27.12.2025 и 2025-12-27</w:t></w:r></w:p></w:body></w:document>`,
 	}
 
@@ -254,8 +259,8 @@ func createDOCXWithDatesTooFarApart() []byte {
 	zw := zip.NewWriter(&buf)
 
 	files := map[string]string{
-		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?>`,
-		"_rels/.rels":         `<?xml version="1.0" encoding="UTF-8"?>`,
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         testPackageRels,
 		"word/document.xml":   `<?xml version="1.0" encoding="UTF-8"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>Текст с датами 01.01.2020 и 01.01.2025</w:t></w:r></w:p></w:body></w:document>`,
 	}
 
@@ -272,8 +277,8 @@ func createDOCXWithDateMMDDYYYY() []byte {
 	zw := zip.NewWriter(&buf)
 
 	files := map[string]string{
-		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?>`,
-		"_rels/.rels":         `<?xml version="1.0" encoding="UTF-8"?>`,
+		"[Content_Types].xml": testContentTypes,
+		"_rels/.rels":         testPackageRels,
 		"word/document.xml":   `<?xml version="1.0" encoding="UTF-8"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>Текст с датой 12/27/2025</w:t></w:r></w:p></w:body></w:document>`,
 	}
 
@@ -297,7 +302,21 @@ func (s *ValidatorServiceSuite) SetupTest() {
 	s.ctx = context.Background()
 	s.cache = &mocks.MockCache{}
 	s.storage = &mocks.MockStorageInterface{}
-	s.svc = New(s.storage, s.cache)
+	engine, err := NewEngine(config.ValidationConfig{})
+	s.Require().NoError(err)
+	s.svc = New(s.storage, s.cache, engine)
+}
+
+func (s *ValidatorServiceSuite) requireFailure(report *ValidationReport, code string) RuleResult {
+	s.Require().NotNil(report)
+	s.Equal(VerdictInvalid, report.Verdict)
+	for _, res := range report.Failures() {
+		if res.Code == code {
+			return res
+		}
+	}
+	s.Failf("код не найден", "в отчёте нет провала с кодом %s: %+v", code, report.Results)
+	return RuleResult{}
 }
 
 func (s *ValidatorServiceSuite) TestValidateAndStore_Success() {
@@ -305,17 +324,34 @@ func (s *ValidatorServiceSuite) TestValidateAndStore_Success() {
 	payload := createValidDOCXPayload()
 
 	s.cache.On("Set", s.ctx, "validated:"+key, []byte("1"), 5*time.Minute).Return(nil)
-	s.storage.On("InsertEvent", s.ctx, key, payload).Return(nil)
+	s.storage.On("InsertEvent", s.ctx, key, payload, []byte(nil)).Return(nil)
+
+	_, err := s.svc.ValidateAndStore(s.ctx, Request{Key: key, Payload: payload})
+	s.Require().NoError(err)
+}
+
+func (s *ValidatorServiceSuite) TestValidateAndStore_StoresMetadata() {
+	key := "test-key"
+	payload := createDOCXWithMetadata()
+
+	s.cache.On("Set", s.ctx, "validated:"+key, []byte("1"), 5*time.Minute).Return(nil)
+	s.storage.On("InsertEvent", s.ctx, key, payload, mock.MatchedBy(func(metadata []byte) bool {
+		var stored map[string]interface{}
+		return json.Unmarshal(metadata, &stored) == nil &&
+			stored["title"] == "Договор поставки" && stored["pages"] == 3.0 && stored["created"] == "2024-02-01T09:30:00Z"
+	})).Return(nil)
 
-	err := s.svc.ValidateAndStore(s.ctx, key, payload)
+	report, err := s.svc.ValidateAndStore(s.ctx, Request{Key: key, Payload: payload})
 	s.Require().NoError(err)
+	s.Equal("Петров П.П.", report.Metadata.LastModifiedBy)
+	s.storage.AssertExpectations(s.T())
 }
 
 func (s *ValidatorServiceSuite) TestValidateAndStore_InvalidDOCX() {
 	key := "test-key"
 	payload := []byte("invalid")
 
-	err := s.svc.ValidateAndStore(s.ctx, key, payload)
+	_, err := s.svc.ValidateAndStore(s.ctx, Request{Key: key, Payload: payload})
 	s.Require().Error(err)
 	assert.Contains(s.T(), err.Error(), "Валидация DOCX не удалась")
 }
@@ -324,43 +360,43 @@ func (s *ValidatorServiceSuite) TestValidateAndStore_InvalidCyrillic() {
 	key := "test-key"
 	payload := createInvalidCyrillicDOCXPayload()
 
-	err := s.svc.ValidateAndStore(s.ctx, key, payload)
+	report, err := s.svc.ValidateAndStore(s.ctx, Request{Key: key, Payload: payload})
 	s.Require().Error(err)
-	assert.Contains(s.T(), err.Error(), "Валидация кириллицы не удалась")
+	s.requireFailure(report, CodeCyrillicRatioLow)
 }
 
 func (s *ValidatorServiceSuite) TestValidateAndStore_NoDates() {
 	key := "test-key"
 	payload := createDOCXWithoutDates()
 
-	err := s.svc.ValidateAndStore(s.ctx, key, payload)
+	report, err := s.svc.ValidateAndStore(s.ctx, Request{Key: key, Payload: payload})
 	s.Require().Error(err)
-	assert.Contains(s.T(), err.Error(), "валидация даты не удалась")
+	s.requireFailure(report, CodeNoDates)
 }
 
 func (s *ValidatorServiceSuite) TestValidateAndStore_LowCyrillic() {
 	key := "test-key"
 	payload := createDOCXWithLowCyrillic()
 
-	err := s.svc.ValidateAndStore(s.ctx, key, payload)
+	report, err := s.svc.ValidateAndStore(s.ctx, Request{Key: key, Payload: payload})
 	s.Require().Error(err)
-	assert.Contains(s.T(), err.Error(), "Валидация кириллицы не удалась")
+	s.requireFailure(report, CodeCyrillicRatioLow)
 }
 
 func (s *ValidatorServiceSuite) TestValidateAndStore_InvalidDate() {
 	key := "test-key"
 	payload := createDOCXWithInvalidDate()
 
-	err := s.svc.ValidateAndStore(s.ctx, key, payload)
+	report, err := s.svc.ValidateAndStore(s.ctx, Request{Key: key, Payload: payload})
 	s.Require().Error(err)
-	assert.Contains(s.T(), err.Error(), "валидация даты не удалась")
+	s.requireFailure(report, CodeNoDates)
 }
 
 func (s *ValidatorServiceSuite) TestValidateAndStore_EmptyDocument() {
 	key := "test-key"
 	payload := createEmptyDOCX()
 
-	err := s.svc.ValidateAndStore(s.ctx, key, payload)
+	_, err := s.svc.ValidateAndStore(s.ctx, Request{Key: key, Payload: payload})
 	s.Require().Error(err)
 	assert.Contains(s.T(), err.Error(), "в документе не найден текст")
 }
@@ -369,7 +405,7 @@ func (s *ValidatorServiceSuite) TestValidateAndStore_NumbersOnly() {
 	key := "test-key"
 	payload := createDOCXWithNumbersOnly()
 
-	err := s.svc.ValidateAndStore(s.ctx, key, payload)
+	_, err := s.svc.ValidateAndStore(s.ctx, Request{Key: key, Payload: payload})
 	s.Require().Error(err)
 	assert.Contains(s.T(), err.Error(), "в документе не найдены буквы")
 }
@@ -378,7 +414,7 @@ func (s *ValidatorServiceSuite) TestValidateAndStore_MissingRequiredFile() {
 	key := "test-key"
 	payload := createDOCXMissingRequiredFile()
 
-	err := s.svc.ValidateAndStore(s.ctx, key, payload)
+	_, err := s.svc.ValidateAndStore(s.ctx, Request{Key: key, Payload: payload})
 	s.Require().Error(err)
 	assert.Contains(s.T(), err.Error(), "отсутствует обязательный файл")
 }
@@ -387,7 +423,7 @@ func (s *ValidatorServiceSuite) TestValidateAndStore_SuspiciousPath() {
 	key := "test-key"
 	payload := createDOCXWithSuspiciousPath()
 
-	err := s.svc.ValidateAndStore(s.ctx, key, payload)
+	_, err := s.svc.ValidateAndStore(s.ctx, Request{Key: key, Payload: payload})
 	s.Require().Error(err)
 	assert.Contains(s.T(), err.Error(), "подозрительный путь в ZIP")
 }
@@ -396,7 +432,7 @@ func (s *ValidatorServiceSuite) TestValidateAndStore_InvalidXML() {
 	key := "test-key"
 	payload := createDOCXWithInvalidXML()
 
-	err := s.svc.ValidateAndStore(s.ctx, key, payload)
+	_, err := s.svc.ValidateAndStore(s.ctx, Request{Key: key, Payload: payload})
 	s.Require().Error(err)
 	assert.Contains(s.T(), err.Error(), "не выглядит как допустимый XML")
 }
@@ -405,9 +441,10 @@ func (s *ValidatorServiceSuite) TestValidateAndStore_XMLButNoDocument() {
 	key := "test-key"
 	payload := createDOCXWithXMLButNoDocument()
 
-	err := s.svc.ValidateAndStore(s.ctx, key, payload)
+	_, err := s.svc.ValidateAndStore(s.ctx, Request{Key: key, Payload: payload})
 	s.Require().Error(err)
 	assert.Contains(s.T(), err.Error(), "в документе не найден текст")
+	assert.Contains(s.T(), err.Error(), "неожиданный корневой элемент root")
 }
 
 func (s *ValidatorServiceSuite) TestValidateAndStore_DateMMDDYYYY() {
@@ -415,9 +452,9 @@ func (s *ValidatorServiceSuite) TestValidateAndStore_DateMMDDYYYY() {
 	payload := createDOCXWithDateMMDDYYYY()
 
 	s.cache.On("Set", s.ctx, "validated:"+key, []byte("1"), 5*time.Minute).Return(nil)
-	s.storage.On("InsertEvent", s.ctx, key, payload).Return(nil)
+	s.storage.On("InsertEvent", s.ctx, key, payload, []byte(nil)).Return(nil)
 
-	err := s.svc.ValidateAndStore(s.ctx, key, payload)
+	_, err := s.svc.ValidateAndStore(s.ctx, Request{Key: key, Payload: payload})
 	s.Require().NoError(err)
 }
 
@@ -425,7 +462,7 @@ func (s *ValidatorServiceSuite) TestValidateAndStore_EmptyDocumentXML() {
 	key := "test-key"
 	payload := createDOCXWithEmptyDocumentXML()
 
-	err := s.svc.ValidateAndStore(s.ctx, key, payload)
+	_, err := s.svc.ValidateAndStore(s.ctx, Request{Key: key, Payload: payload})
 	s.Require().Error(err)
 	assert.Contains(s.T(), err.Error(), "не выглядит как допустимый XML")
 }
@@ -435,9 +472,9 @@ func (s *ValidatorServiceSuite) TestValidateAndStore_StorageError() {
 	payload := createValidDOCXPayload()
 
 	s.cache.On("Set", s.ctx, "validated:"+key, []byte("1"), 5*time.Minute).Return(nil)
-	s.storage.On("InsertEvent", s.ctx, key, payload).Return(fmt.Errorf("storage error"))
+	s.storage.On("InsertEvent", s.ctx, key, payload, []byte(nil)).Return(fmt.Errorf("storage error"))
 
-	err := s.svc.ValidateAndStore(s.ctx, key, payload)
+	_, err := s.svc.ValidateAndStore(s.ctx, Request{Key: key, Payload: payload})
 	s.Require().Error(err)
 	assert.Contains(s.T(), err.Error(), "сохранить событие")
 }
@@ -447,9 +484,9 @@ func (s *ValidatorServiceSuite) TestValidateAndStore_MultipleDates() {
 	payload := createDOCXWithMultipleDates()
 
 	s.cache.On("Set", s.ctx, "validated:"+key, []byte("1"), 5*time.Minute).Return(nil)
-	s.storage.On("InsertEvent", s.ctx, key, payload).Return(nil)
+	s.storage.On("InsertEvent", s.ctx, key, payload, []byte(nil)).Return(nil)
 
-	err := s.svc.ValidateAndStore(s.ctx, key, payload)
+	_, err := s.svc.ValidateAndStore(s.ctx, Request{Key: key, Payload: payload})
 	s.Require().NoError(err)
 }
 
@@ -457,11 +494,75 @@ func (s *ValidatorServiceSuite) TestValidateAndStore_DatesTooFarApart() {
 	key := "test-key"
 	payload := createDOCXWithDatesTooFarApart()
 
-	err := s.svc.ValidateAndStore(s.ctx, key, payload)
+	_, err := s.svc.ValidateAndStore(s.ctx, Request{Key: key, Payload: payload})
 	s.Require().Error(err)
 	assert.Contains(s.T(), err.Error(), "даты в документе отличаются более чем на 3 года")
 }
 
+func (s *ValidatorServiceSuite) TestValidate_DryRunSkipsStorage() {
+	payload := createValidDOCXPayload()
+
+	_, err := s.svc.Validate(s.ctx, Request{Payload: payload})
+	s.Require().NoError(err)
+	s.storage.AssertNotCalled(s.T(), "InsertEvent")
+	s.cache.AssertNotCalled(s.T(), "Set")
+}
+
+func (s *ValidatorServiceSuite) TestValidate_ErrorIsValidationFailed() {
+	payload := createDOCXWithDatesTooFarApart()
+
+	_, err := s.svc.Validate(s.ctx, Request{Payload: payload})
+	s.Require().Error(err)
+	s.True(errors.Is(err, domain.ErrValidationFailed))
+	assert.Contains(s.T(), err.Error(), "Валидация DOCX не удалась")
+}
+
+func (s *ValidatorServiceSuite) TestValidateAndStore_StorageErrorIsNotValidationFailure() {
+	key := "test-key"
+	payload := createValidDOCXPayload()
+
+	s.cache.On("Set", s.ctx, "validated:"+key, []byte("1"), 5*time.Minute).Return(nil)
+	s.storage.On("InsertEvent", s.ctx, key, payload, []byte(nil)).Return(fmt.Errorf("storage error"))
+
+	_, err := s.svc.ValidateAndStore(s.ctx, Request{Key: key, Payload: payload})
+	s.Require().Error(err)
+	s.False(errors.Is(err, domain.ErrValidationFailed))
+}
+
+func (s *ValidatorServiceSuite) TestValidate_ReportListsEveryRule() {
+	payload := createDOCXWithDatesTooFarApart()
+
+	report, err := s.svc.Validate(s.ctx, Request{Payload: payload})
+	s.Require().Error(err)
+
+	var rules []string
+	for _, res := range report.Results {
+		rules = append(rules, res.Rule)
+	}
+	s.Equal([]string{"archive", "required_parts", "safe_paths", "zip_integrity", "package_consistency", "active_content", "xml_wellformed", "main_part_xml", "cyrillic_ratio", "date_span", "pii", "review"}, rules)
+
+	cyrillic, ok := report.Result("cyrillic_ratio")
+	s.Require().True(ok)
+	s.Equal(StatusPassed, cyrillic.Status)
+	s.Equal(100.0, cyrillic.Details["cyrillic_percent"])
+
+	dates := s.requireFailure(report, CodeDateSpanExceeded)
+	s.Equal("2020-01-01", dates.Details["min_date"])
+	s.Equal("2025-01-01", dates.Details["max_date"])
+}
+
+func (s *ValidatorServiceSuite) TestValidate_ReportCollectsAllFailures() {
+	report, err := s.svc.Validate(s.ctx, Request{Payload: createInvalidCyrillicDOCXPayload()})
+	s.Require().Error(err)
+	s.requireFailure(report, CodeCyrillicRatioLow)
+	s.requireFailure(report, CodeNoDates)
+
+	report, err = s.svc.Validate(s.ctx, Request{Payload: createDOCXWithLowCyrillic()})
+	s.Require().Error(err)
+	res := s.requireFailure(report, CodeCyrillicRatioLow)
+	s.Less(res.Details["cyrillic_percent"].(float64), 90.0)
+}
+
 func TestValidatorServiceSuite(t *testing.T) {
 	suite.Run(t, new(ValidatorServiceSuite))
 }
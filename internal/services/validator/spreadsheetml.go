@@ -0,0 +1,349 @@
+package validator
+
+import (
+	"encoding/xml"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	nsSheetMain   = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	nsSheetStrict = "http://purl.oclc.org/ooxml/spreadsheetml/main"
+
+	// maxExcelSerial — 31.12.9999, последняя дата, которую хранит Excel.
+	maxExcelSerial = 2958465
+)
+
+func isSheetElement(name xml.Name, local string) bool {
+	return name.Local == local && (name.Space == nsSheetMain || name.Space == nsSheetStrict)
+}
+
+type workbookSheet struct {
+	Name string
+	RID  string
+}
+
+type workbookInfo struct {
+	Sheets   []workbookSheet
+	Date1904 bool
+}
+
+func parseWorkbook(r io.Reader) (*workbookInfo, error) {
+	info := &workbookInfo{}
+	root := false
+	dec := newXMLDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		root = true
+		switch {
+		case isSheetElement(start.Name, "workbookPr"):
+			for _, attr := range start.Attr {
+				if attr.Name.Local == "date1904" {
+					info.Date1904 = attr.Value == "1" || attr.Value == "true"
+				}
+			}
+		case isSheetElement(start.Name, "sheet"):
+			sheet := workbookSheet{RID: relationshipID(start.Attr)}
+			for _, attr := range start.Attr {
+				if attr.Name.Local == "name" && attr.Name.Space == "" {
+					sheet.Name = attr.Value
+				}
+			}
+			info.Sheets = append(info.Sheets, sheet)
+		}
+	}
+	if !root {
+		return nil, errNoRootElement
+	}
+	return info, nil
+}
+
+// parseSharedStrings читает таблицу строк; фонетические подсказки (rPh) пропускаются.
+func parseSharedStrings(r io.Reader) ([]string, error) {
+	var (
+		out     []string
+		current *strings.Builder
+		inText  bool
+	)
+	dec := newXMLDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case isSheetElement(t.Name, "si"):
+				current = &strings.Builder{}
+			case isSheetElement(t.Name, "rPh"):
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+			case isSheetElement(t.Name, "t"):
+				inText = true
+			}
+		case xml.EndElement:
+			switch {
+			case isSheetElement(t.Name, "si"):
+				if current != nil {
+					out = append(out, current.String())
+				}
+				current = nil
+			case isSheetElement(t.Name, "t"):
+				inText = false
+			}
+		case xml.CharData:
+			if inText && current != nil {
+				current.Write(t)
+			}
+		}
+	}
+	return out, nil
+}
+
+// builtinDateFormats — встроенные форматы чисел, отображающие дату.
+var builtinDateFormats = map[int]bool{
+	14: true, 15: true, 16: true, 17: true, 22: true,
+	27: true, 28: true, 29: true, 30: true, 31: true, 32: true, 33: true, 34: true, 35: true, 36: true,
+	50: true, 51: true, 52: true, 53: true, 54: true, 55: true, 56: true, 57: true, 58: true,
+}
+
+// parseDateStyles возвращает индексы cellXfs, чей формат отображает дату.
+// Excel хранит даты числами, и без этого правила дат их не увидят.
+func parseDateStyles(r io.Reader) (map[int]bool, error) {
+	customDates := map[int]bool{}
+	styles := map[int]bool{}
+	inCellXfs := false
+	index := 0
+	dec := newXMLDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case isSheetElement(t.Name, "numFmt"):
+				var id int
+				var code string
+				for _, attr := range t.Attr {
+					switch attr.Name.Local {
+					case "numFmtId":
+						id, _ = strconv.Atoi(attr.Value)
+					case "formatCode":
+						code = attr.Value
+					}
+				}
+				if isDateFormatCode(code) {
+					customDates[id] = true
+				}
+			case isSheetElement(t.Name, "cellXfs"):
+				inCellXfs = true
+			case inCellXfs && isSheetElement(t.Name, "xf"):
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "numFmtId" {
+						id, _ := strconv.Atoi(attr.Value)
+						if builtinDateFormats[id] || customDates[id] {
+							styles[index] = true
+						}
+					}
+				}
+				index++
+			}
+		case xml.EndElement:
+			if isSheetElement(t.Name, "cellXfs") {
+				inCellXfs = false
+			}
+		}
+	}
+	return styles, nil
+}
+
+// isDateFormatCode распознаёт пользовательский формат даты: после удаления
+// литералов в кавычках и секций в квадратных скобках в нём остаются d или y.
+func isDateFormatCode(code string) bool {
+	var b strings.Builder
+	inQuote, inBracket := false, false
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		switch {
+		case c == '\\':
+			i++
+		case c == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case c == '[':
+			inBracket = true
+		case c == ']':
+			inBracket = false
+		case inBracket:
+		default:
+			b.WriteByte(c)
+		}
+	}
+	stripped := strings.ToLower(b.String())
+	return strings.ContainsAny(stripped, "dy")
+}
+
+// excelSerialDate переводит порядковый номер дня в дату. Для системы 1900
+// учитывается несуществующее 29.02.1900, унаследованное от Lotus 1-2-3.
+func excelSerialDate(serial float64, date1904 bool) (time.Time, bool) {
+	if serial < 1 || serial > maxExcelSerial || math.IsNaN(serial) {
+		return time.Time{}, false
+	}
+	days := int(math.Floor(serial))
+	if date1904 {
+		return time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, days), true
+	}
+	if days < 60 {
+		return time.Date(1899, 12, 31, 0, 0, 0, 0, time.UTC).AddDate(0, 0, days), true
+	}
+	return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, days), true
+}
+
+type sheetParser struct {
+	shared     []string
+	dateStyles map[int]bool
+	date1904   bool
+
+	table    Table
+	cellType string
+	style    int
+	value    strings.Builder
+	inline   strings.Builder
+	inValue  bool
+	inInline bool
+	inCell   bool
+}
+
+// parseWorksheet собирает значения ячеек листа в таблицу: строки и ячейки идут
+// в порядке следования в sheetData, пустые строки листа не материализуются.
+func parseWorksheet(r io.Reader, shared []string, dateStyles map[int]bool, date1904 bool) (*Table, error) {
+	p := &sheetParser{shared: shared, dateStyles: dateStyles, date1904: date1904}
+	root := false
+	dec := newXMLDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			root = true
+			switch {
+			case isSheetElement(t.Name, "row"):
+				p.table.Rows = append(p.table.Rows, nil)
+			case isSheetElement(t.Name, "c"):
+				p.startCell(t.Attr)
+			case p.inCell && isSheetElement(t.Name, "v"):
+				p.inValue = true
+			case p.inCell && isSheetElement(t.Name, "rPh"):
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+			case p.inCell && isSheetElement(t.Name, "t"):
+				p.inInline = true
+			}
+		case xml.EndElement:
+			switch {
+			case isSheetElement(t.Name, "c"):
+				p.endCell()
+			case isSheetElement(t.Name, "v"):
+				p.inValue = false
+			case isSheetElement(t.Name, "t"):
+				p.inInline = false
+			}
+		case xml.CharData:
+			switch {
+			case p.inValue:
+				p.value.Write(t)
+			case p.inInline:
+				p.inline.Write(t)
+			}
+		}
+	}
+	if !root {
+		return nil, errNoRootElement
+	}
+	return &p.table, nil
+}
+
+func (p *sheetParser) startCell(attrs []xml.Attr) {
+	p.inCell = true
+	p.cellType, p.style = "n", 0
+	p.value.Reset()
+	p.inline.Reset()
+	for _, attr := range attrs {
+		switch attr.Name.Local {
+		case "t":
+			p.cellType = attr.Value
+		case "s":
+			p.style, _ = strconv.Atoi(attr.Value)
+		}
+	}
+}
+
+func (p *sheetParser) endCell() {
+	p.inCell = false
+	if len(p.table.Rows) == 0 {
+		p.table.Rows = append(p.table.Rows, nil)
+	}
+	row := len(p.table.Rows) - 1
+	p.table.Rows[row] = append(p.table.Rows[row], p.cellText())
+}
+
+func (p *sheetParser) cellText() string {
+	raw := strings.TrimSpace(p.value.String())
+	switch p.cellType {
+	case "s":
+		idx, err := strconv.Atoi(raw)
+		if err != nil || idx < 0 || idx >= len(p.shared) {
+			return ""
+		}
+		return p.shared[idx]
+	case "inlineStr":
+		return p.inline.String()
+	case "d":
+		for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04:05Z07:00", "2006-01-02"} {
+			if t, err := time.Parse(layout, raw); err == nil {
+				return t.Format("02.01.2006")
+			}
+		}
+		return raw
+	case "n":
+		if p.dateStyles[p.style] {
+			if serial, err := strconv.ParseFloat(raw, 64); err == nil {
+				if t, ok := excelSerialDate(serial, p.date1904); ok {
+					return t.Format("02.01.2006")
+				}
+			}
+		}
+		return raw
+	}
+	// str (результат формулы), b и e показываются как есть.
+	return p.value.String()
+}
@@ -0,0 +1,161 @@
+// Package stem реализует алгоритм Snowball для русского языка: окончания
+// отсекаются по правилам из https://snowballstem.org/algorithms/russian/stemmer.html.
+package stem
+
+import "strings"
+
+// Группы окончаний. В группах с флагом afterAYa окончание отсекается, только
+// если перед ним стоит «а» или «я» (сама буква остаётся).
+type endings struct {
+	list     []string
+	afterAYa bool
+}
+
+var (
+	perfectiveGerund = []endings{
+		{list: []string{"в", "вши", "вшись"}, afterAYa: true},
+		{list: []string{"ив", "ивши", "ившись", "ыв", "ывши", "ывшись"}},
+	}
+	adjective = []endings{{list: []string{
+		"ее", "ие", "ые", "ое", "ими", "ыми", "ей", "ий", "ый", "ой", "ем", "им", "ым", "ом",
+		"его", "ого", "ему", "ому", "их", "ых", "ую", "юю", "ая", "яя", "ою", "ею",
+	}}}
+	participle = []endings{
+		{list: []string{"ем", "нн", "вш", "ющ", "щ"}, afterAYa: true},
+		{list: []string{"ивш", "ывш", "ующ"}},
+	}
+	reflexive = []endings{{list: []string{"ся", "сь"}}}
+	verb      = []endings{
+		{list: []string{"ла", "на", "ете", "йте", "ли", "й", "л", "ем", "н", "ло", "но", "ет", "ют", "ны", "ть", "ешь", "нно"}, afterAYa: true},
+		{list: []string{
+			"ила", "ыла", "ена", "ейте", "уйте", "ите", "или", "ыли", "ей", "уй", "ил", "ыл", "им", "ым", "ен",
+			"ило", "ыло", "ено", "ят", "ует", "уют", "ит", "ыт", "ены", "ить", "ыть", "ишь", "ую", "ю",
+		}},
+	}
+	noun = []endings{{list: []string{
+		"а", "ев", "ов", "ие", "ье", "е", "иями", "ями", "ами", "еи", "ии", "и", "ией", "ей", "ой", "ий", "й",
+		"иям", "ям", "ием", "ем", "ам", "ом", "о", "у", "ах", "иях", "ях", "ы", "ь", "ию", "ью", "ю", "ия", "ья", "я",
+	}}}
+	derivational = []endings{{list: []string{"ост", "ость"}}}
+	superlative  = []endings{{list: []string{"ейш", "ейше"}}}
+)
+
+func isVowel(r rune) bool {
+	return strings.ContainsRune("аеиоуыэюя", r)
+}
+
+// Russian возвращает основу слова. Слово приводится к нижнему регистру, «ё» — к «е».
+func Russian(word string) string {
+	w := []rune(strings.ReplaceAll(strings.ToLower(word), "ё", "е"))
+	rv, r2 := regions(w)
+	if rv >= len(w) {
+		return string(w)
+	}
+	head, tail := w[:rv], w[rv:]
+
+	// Шаг 1.
+	if n := match(tail, perfectiveGerund); n > 0 {
+		tail = tail[:len(tail)-n]
+	} else {
+		if n := match(tail, reflexive); n > 0 {
+			tail = tail[:len(tail)-n]
+		}
+		if n := match(tail, adjective); n > 0 {
+			tail = tail[:len(tail)-n]
+			if n := match(tail, participle); n > 0 {
+				tail = tail[:len(tail)-n]
+			}
+		} else if n := match(tail, verb); n > 0 {
+			tail = tail[:len(tail)-n]
+		} else if n := match(tail, noun); n > 0 {
+			tail = tail[:len(tail)-n]
+		}
+	}
+
+	// Шаг 2.
+	if len(tail) > 0 && tail[len(tail)-1] == 'и' {
+		tail = tail[:len(tail)-1]
+	}
+
+	// Шаг 3: словообразовательное окончание только в R2.
+	if n := match(tail, derivational); n > 0 && rv+len(tail)-n >= r2 {
+		tail = tail[:len(tail)-n]
+	}
+
+	// Шаг 4.
+	switch {
+	case match(tail, superlative) > 0:
+		tail = tail[:len(tail)-match(tail, superlative)]
+		tail = undoubleN(tail)
+	case hasSuffix(tail, "нн"):
+		tail = tail[:len(tail)-1]
+	case hasSuffix(tail, "ь"):
+		tail = tail[:len(tail)-1]
+	}
+	return string(head) + string(tail)
+}
+
+// regions возвращает начало RV (после первой гласной) и R2 (R1 от R1, где R1 —
+// после первой согласной, следующей за гласной).
+func regions(w []rune) (rv, r2 int) {
+	rv = len(w)
+	for i, r := range w {
+		if isVowel(r) {
+			rv = i + 1
+			break
+		}
+	}
+	r1 := afterVowelConsonant(w, 0)
+	return rv, afterVowelConsonant(w, r1)
+}
+
+func afterVowelConsonant(w []rune, from int) int {
+	for i := from + 1; i < len(w); i++ {
+		if !isVowel(w[i]) && isVowel(w[i-1]) {
+			return i + 1
+		}
+	}
+	return len(w)
+}
+
+// match возвращает длину самого длинного подходящего окончания или 0. Как и
+// в Snowball, если условие «после а/я» для него не выполнено, более короткие не пробуются.
+func match(w []rune, groups []endings) int {
+	best, bestAYa := 0, false
+	for _, g := range groups {
+		for _, e := range g.list {
+			if n := len([]rune(e)); n > best && hasSuffix(w, e) {
+				best, bestAYa = n, g.afterAYa
+			}
+		}
+	}
+	if best == 0 || !bestAYa {
+		return best
+	}
+	if len(w) > best {
+		if prev := w[len(w)-best-1]; prev == 'а' || prev == 'я' {
+			return best
+		}
+	}
+	return 0
+}
+
+func hasSuffix(w []rune, suffix string) bool {
+	s := []rune(suffix)
+	if len(s) > len(w) {
+		return false
+	}
+	for i := range s {
+		if w[len(w)-len(s)+i] != s[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func undoubleN(w []rune) []rune {
+	if hasSuffix(w, "нн") {
+		return w[:len(w)-1]
+	}
+	return w
+}
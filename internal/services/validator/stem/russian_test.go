@@ -0,0 +1,30 @@
+package stem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRussian(t *testing.T) {
+	// Ожидаемые основы — из эталонного словаря Snowball.
+	for word, want := range map[string]string{
+		"договор":         "договор",
+		"договора":        "договор",
+		"договорами":      "договор",
+		"подписи":         "подпис",
+		"подписями":       "подпис",
+		"сторон":          "сторон",
+		"сторонами":       "сторон",
+		"черновик":        "черновик",
+		"черновика":       "черновик",
+		"важнейшими":      "важн",
+		"вагоне":          "вагон",
+		"бежавшие":        "бежа",
+		"ответственность": "ответствен",
+		"Ёлки":            "елк",
+		"и":               "и",
+	} {
+		require.Equal(t, want, Russian(word), word)
+	}
+}
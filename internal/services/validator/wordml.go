@@ -0,0 +1,323 @@
+package validator
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	nsWordMain      = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+	nsWordStrict    = "http://purl.oclc.org/ooxml/wordprocessingml/main"
+	nsMarkupCompat  = "http://schemas.openxmlformats.org/markup-compatibility/2006"
+	outsideOfTables = -1
+)
+
+var errNoRootElement = errors.New("нет корневого элемента")
+
+type Run struct {
+	Text string
+}
+
+// Paragraph — абзац в порядке чтения. Абзацы из ячеек таблиц тоже попадают
+// в общий список и помечаются координатами ячейки.
+type Paragraph struct {
+	Text string
+	Runs []Run
+	// Source — вид части (SourceBody, SourceHeader, ...), Part — имя записи в архиве.
+	Source string
+	Part   string
+	Table  int
+	Row    int
+	Cell   int
+}
+
+type Table struct {
+	Rows [][]string
+}
+
+// Виды отметок рецензирования.
+const (
+	RevisionInsertion = "insertion"
+	RevisionDeletion  = "deletion"
+	RevisionMove      = "move"
+	RevisionFormat    = "format"
+)
+
+// revisionElements сопоставляет элементы рецензирования с видом правки.
+// Перемещение размечено парой moveFrom/moveTo и считается один раз.
+var revisionElements = map[string]string{
+	"ins":             RevisionInsertion,
+	"cellIns":         RevisionInsertion,
+	"del":             RevisionDeletion,
+	"cellDel":         RevisionDeletion,
+	"moveFrom":        RevisionMove,
+	"rPrChange":       RevisionFormat,
+	"pPrChange":       RevisionFormat,
+	"sectPrChange":    RevisionFormat,
+	"tblPrChange":     RevisionFormat,
+	"trPrChange":      RevisionFormat,
+	"tcPrChange":      RevisionFormat,
+	"tblGridChange":   RevisionFormat,
+	"numberingChange": RevisionFormat,
+}
+
+// Revision — не принятая правка в режиме рецензирования.
+type Revision struct {
+	Kind   string
+	Author string
+	Part   string
+}
+
+// Comment — примечание из comments.xml. Resolved заполняется по commentsExtended.xml;
+// paraIDs — w14:paraId абзацев примечания, по последнему из них ищется отметка.
+type Comment struct {
+	ID       string
+	Author   string
+	Part     string
+	Resolved bool
+	paraIDs  []string
+}
+
+type wordBody struct {
+	Root       xml.Name
+	Paragraphs []Paragraph
+	Tables     []Table
+	Revisions  []Revision
+	Comments   []Comment
+}
+
+type paragraphState struct {
+	text strings.Builder
+	runs []Run
+}
+
+type tableState struct {
+	index int
+	row   int
+	cell  int
+}
+
+type wordParser struct {
+	body   wordBody
+	paras  []*paragraphState
+	tables []tableState
+	run    *strings.Builder
+	inText bool
+	// deleted — глубина вложенности в w:del и w:moveFrom: их текст не входит
+	// в документ с принятыми правками.
+	deleted int
+	comment *Comment
+}
+
+func isWordElement(name xml.Name, local string) bool {
+	return name.Local == local && (name.Space == nsWordMain || name.Space == nsWordStrict)
+}
+
+// markupElement переводит элемент разметки в имя элемента WordprocessingML,
+// который понимает wordParser; false — элемент не влияет на текст.
+type markupElement func(name xml.Name) (string, bool)
+
+func wordElement(name xml.Name) (string, bool) {
+	return name.Local, name.Space == nsWordMain || name.Space == nsWordStrict
+}
+
+// parseWordprocessingML потоково разбирает часть WordprocessingML и собирает абзацы,
+// таблицы и прогоны. Табуляции и переводы строк внутри прогонов сохраняются,
+// содержимое mc:Fallback пропускается, чтобы не дублировать текст надписей.
+// Текст собирается с принятыми правками: удалённое и перенесённое в другое
+// место не учитывается, отметки рецензирования сохраняются в Revisions.
+func parseWordprocessingML(r io.Reader) (*wordBody, error) {
+	return parseTextMarkup(r, wordElement)
+}
+
+func parseTextMarkup(r io.Reader, element markupElement) (*wordBody, error) {
+	p := &wordParser{}
+	dec := newXMLDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if p.body.Root.Local == "" {
+				p.body.Root = t.Name
+			}
+			if t.Name.Space == nsMarkupCompat && t.Name.Local == "Fallback" {
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if local, ok := element(t.Name); ok {
+				p.review(local, t.Attr)
+				p.start(local)
+			}
+		case xml.EndElement:
+			if local, ok := element(t.Name); ok {
+				p.end(local)
+			}
+		case xml.CharData:
+			if p.inText {
+				p.write(string(t))
+			}
+		}
+	}
+	if p.body.Root.Local == "" {
+		return nil, errNoRootElement
+	}
+	return &p.body, nil
+}
+
+// review учитывает отметки рецензирования и примечания — элементы, для
+// которых нужны атрибуты.
+func (p *wordParser) review(local string, attrs []xml.Attr) {
+	if kind, ok := revisionElements[local]; ok {
+		p.body.Revisions = append(p.body.Revisions, Revision{Kind: kind, Author: attrValue(attrs, "author")})
+	}
+	switch local {
+	case "comment":
+		p.comment = &Comment{ID: attrValue(attrs, "id"), Author: attrValue(attrs, "author")}
+	case "p":
+		if id := attrValue(attrs, "paraId"); id != "" && p.comment != nil {
+			p.comment.paraIDs = append(p.comment.paraIDs, id)
+		}
+	}
+}
+
+func (p *wordParser) start(local string) {
+	switch local {
+	case "del", "moveFrom":
+		p.deleted++
+	case "p":
+		p.paras = append(p.paras, &paragraphState{})
+	case "r":
+		p.run = &strings.Builder{}
+	case "t":
+		p.inText = true
+	case "tab", "ptab":
+		p.write("\t")
+	case "br", "cr":
+		p.write("\n")
+	case "noBreakHyphen":
+		p.write("-")
+	case "tbl":
+		p.tables = append(p.tables, tableState{index: len(p.body.Tables), row: -1, cell: -1})
+		p.body.Tables = append(p.body.Tables, Table{})
+	case "tr":
+		if ts := p.currentTable(); ts != nil {
+			tbl := &p.body.Tables[ts.index]
+			tbl.Rows = append(tbl.Rows, nil)
+			ts.row = len(tbl.Rows) - 1
+			ts.cell = -1
+		}
+	case "tc":
+		if ts := p.currentTable(); ts != nil && ts.row >= 0 {
+			tbl := &p.body.Tables[ts.index]
+			tbl.Rows[ts.row] = append(tbl.Rows[ts.row], "")
+			ts.cell = len(tbl.Rows[ts.row]) - 1
+		}
+	}
+}
+
+func (p *wordParser) end(local string) {
+	switch local {
+	case "del", "moveFrom":
+		if p.deleted > 0 {
+			p.deleted--
+		}
+	case "comment":
+		if p.comment != nil {
+			p.body.Comments = append(p.body.Comments, *p.comment)
+			p.comment = nil
+		}
+	case "t":
+		p.inText = false
+	case "r":
+		if p.run != nil && len(p.paras) > 0 {
+			para := p.paras[len(p.paras)-1]
+			para.runs = append(para.runs, Run{Text: p.run.String()})
+		}
+		p.run = nil
+	case "p":
+		p.closeParagraph()
+	case "tbl":
+		if len(p.tables) > 0 {
+			p.tables = p.tables[:len(p.tables)-1]
+		}
+	}
+}
+
+func (p *wordParser) write(s string) {
+	if len(p.paras) == 0 || p.deleted > 0 {
+		return
+	}
+	p.paras[len(p.paras)-1].text.WriteString(s)
+	if p.run != nil {
+		p.run.WriteString(s)
+	}
+}
+
+func (p *wordParser) currentTable() *tableState {
+	if len(p.tables) == 0 {
+		return nil
+	}
+	return &p.tables[len(p.tables)-1]
+}
+
+func (p *wordParser) closeParagraph() {
+	if len(p.paras) == 0 {
+		return
+	}
+	state := p.paras[len(p.paras)-1]
+	p.paras = p.paras[:len(p.paras)-1]
+
+	para := Paragraph{Text: state.text.String(), Runs: state.runs, Table: outsideOfTables}
+	if ts := p.currentTable(); ts != nil && ts.row >= 0 && ts.cell >= 0 {
+		para.Table, para.Row, para.Cell = ts.index, ts.row, ts.cell
+		cells := p.body.Tables[ts.index].Rows[ts.row]
+		if cells[ts.cell] == "" {
+			cells[ts.cell] = para.Text
+		} else {
+			cells[ts.cell] += "\n" + para.Text
+		}
+	}
+	p.body.Paragraphs = append(p.body.Paragraphs, para)
+}
+
+// attrValue возвращает значение атрибута по локальному имени: префиксы w: и w14:
+// в разных редакторах привязаны к разным пространствам имён.
+func attrValue(attrs []xml.Attr, local string) string {
+	for _, a := range attrs {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func joinParagraphs(paragraphs []Paragraph) string {
+	texts := make([]string, 0, len(paragraphs))
+	for _, para := range paragraphs {
+		texts = append(texts, para.Text)
+	}
+	return strings.Join(texts, "\n")
+}
+
+func describeXMLError(part string, err error) error {
+	switch {
+	case errors.Is(err, errNoRootElement):
+		return fmt.Errorf("%s не выглядит как допустимый XML", part)
+	case errors.Is(err, zip.ErrChecksum), errors.Is(err, zip.ErrFormat), errors.Is(err, zip.ErrAlgorithm):
+		return fmt.Errorf("невозможно прочитать %s: %w", part, err)
+	}
+	return fmt.Errorf("%s не является корректным XML: %w", part, err)
+}
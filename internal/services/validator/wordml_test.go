@@ -0,0 +1,88 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const wordNS = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006"`
+
+func parseBody(t *testing.T, body string) *wordBody {
+	t.Helper()
+	doc := `<?xml version="1.0" encoding="UTF-8"?><w:document ` + wordNS + `><w:body>` + body + `</w:body></w:document>`
+	parsed, err := parseWordprocessingML(strings.NewReader(doc))
+	require.NoError(t, err)
+	return parsed
+}
+
+func TestParseWordprocessingML_SplitRunsAndEntities(t *testing.T) {
+	body := parseBody(t, `<w:p><w:r><w:t>Иванов &amp; </w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">Пет</w:t></w:r><w:r><w:t>ров</w:t></w:r></w:p>`)
+
+	require.Len(t, body.Paragraphs, 1)
+	para := body.Paragraphs[0]
+	require.Equal(t, "Иванов & Петров", para.Text)
+	require.Len(t, para.Runs, 3)
+	require.Equal(t, "Пет", para.Runs[1].Text)
+	require.Equal(t, outsideOfTables, para.Table)
+}
+
+func TestParseWordprocessingML_TabsBreaksAndParagraphs(t *testing.T) {
+	body := parseBody(t, `<w:p><w:r><w:t>Дата:</w:t><w:tab/><w:t>01.02.2024</w:t><w:br/><w:t>строка</w:t></w:r></w:p><w:p><w:r><w:t>03.04.2024</w:t></w:r></w:p>`)
+
+	require.Equal(t, "Дата:\t01.02.2024\nстрока\n03.04.2024", joinParagraphs(body.Paragraphs))
+}
+
+func TestParseWordprocessingML_Tables(t *testing.T) {
+	body := parseBody(t, `<w:tbl><w:tr><w:tc><w:p><w:r><w:t>Поставщик</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>ООО «Ромашка»</w:t></w:r></w:p><w:p><w:r><w:t>ИНН</w:t></w:r></w:p></w:tc></w:tr></w:tbl><w:p><w:r><w:t>После таблицы</w:t></w:r></w:p>`)
+
+	require.Len(t, body.Tables, 1)
+	require.Equal(t, [][]string{{"Поставщик", "ООО «Ромашка»\nИНН"}}, body.Tables[0].Rows)
+	require.Len(t, body.Paragraphs, 4)
+	require.Equal(t, 0, body.Paragraphs[2].Table)
+	require.Equal(t, 1, body.Paragraphs[2].Cell)
+	require.Equal(t, outsideOfTables, body.Paragraphs[3].Table)
+}
+
+func TestParseWordprocessingML_SkipsFallback(t *testing.T) {
+	body := parseBody(t, `<w:p><w:r><mc:AlternateContent><mc:Choice Requires="wps"><w:txbxContent><w:p><w:r><w:t>Надпись</w:t></w:r></w:p></w:txbxContent></mc:Choice><mc:Fallback><w:pict><w:txbxContent><w:p><w:r><w:t>Надпись</w:t></w:r></w:p></w:txbxContent></w:pict></mc:Fallback></mc:AlternateContent></w:r></w:p>`)
+
+	require.Equal(t, 1, strings.Count(joinParagraphs(body.Paragraphs), "Надпись"))
+}
+
+func TestParseWordprocessingML_Errors(t *testing.T) {
+	_, err := parseWordprocessingML(strings.NewReader("просто текст"))
+	require.ErrorIs(t, err, errNoRootElement)
+
+	_, err = parseWordprocessingML(strings.NewReader(`<w:document ` + wordNS + `><w:body></w:document>`))
+	require.Error(t, err)
+}
+
+func TestDateSpan_DatesOnAdjacentParagraphs(t *testing.T) {
+	engine, err := profileEngine(configWithRules("date_span"))
+	require.NoError(t, err)
+
+	report := engine.Run(Request{Payload: createDOCX(`<w:p><w:r><w:t>Дата 01.02.2024</w:t></w:r></w:p><w:p><w:r><w:t>15.03.2024 подписан</w:t></w:r></w:p>`, nil)})
+	res, ok := report.Result("date_span")
+	require.True(t, ok)
+	require.Equal(t, StatusPassed, res.Status)
+	require.Equal(t, 2, res.Details["dates_found"])
+}
+
+func TestParseWordprocessingML_AcceptedRevisions(t *testing.T) {
+	body := parseBody(t, `<w:p><w:r><w:t>Срок оплаты </w:t></w:r>`+
+		`<w:del w:id="1" w:author="Петров"><w:r><w:delText>10</w:delText></w:r><w:r><w:t>15</w:t></w:r></w:del>`+
+		`<w:ins w:id="2" w:author="Иванов"><w:r><w:t>30</w:t></w:r></w:ins>`+
+		`<w:r><w:rPr><w:b/><w:rPrChange w:id="3" w:author="Иванов"><w:rPr/></w:rPrChange></w:rPr><w:t> дней</w:t></w:r></w:p>`+
+		`<w:p><w:moveFrom w:id="4" w:author="Петров"><w:r><w:t>Перенесено</w:t></w:r></w:moveFrom></w:p>`+
+		`<w:p><w:moveTo w:id="5" w:author="Петров"><w:r><w:t>Перенесено</w:t></w:r></w:moveTo></w:p>`)
+
+	require.Equal(t, "Срок оплаты 30 дней\n\nПеренесено", joinParagraphs(body.Paragraphs))
+	require.Equal(t, []Revision{
+		{Kind: RevisionDeletion, Author: "Петров"},
+		{Kind: RevisionInsertion, Author: "Иванов"},
+		{Kind: RevisionFormat, Author: "Иванов"},
+		{Kind: RevisionMove, Author: "Петров"},
+	}, body.Revisions)
+}
@@ -0,0 +1,186 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	sigLocalHeader   = 0x04034b50
+	sigCentralHeader = 0x02014b50
+	sigEndOfCentral  = 0x06054b50
+	sigZip64Locator  = 0x07064b50
+	sigZip64End      = 0x06064b50
+
+	localHeaderLen   = 30
+	centralHeaderLen = 46
+	endOfCentralLen  = 22
+	// maxCommentLen — EOCD ищется в последних 22+65535 байтах файла.
+	maxCommentLen = 0xFFFF
+
+	flagEncrypted      = 0x1
+	flagDataDescriptor = 0x8
+
+	zip64Sentinel32 = 0xFFFFFFFF
+	zip64ExtraID    = 0x0001
+)
+
+var errNoCentralDirectory = errors.New("не найден конец центрального каталога")
+
+// centralEntry — запись центрального каталога в том виде, в каком она
+// лежит в файле; archive/zip не даёт доступа к смещениям локальных заголовков.
+type centralEntry struct {
+	Name             []byte
+	Flags            uint16
+	Method           uint16
+	CRC32            uint32
+	CompressedSize   uint64
+	UncompressedSize uint64
+	HeaderOffset     uint64
+}
+
+type localHeader struct {
+	Name             []byte
+	Flags            uint16
+	Method           uint16
+	CRC32            uint32
+	CompressedSize   uint64
+	UncompressedSize uint64
+	DataOffset       uint64
+}
+
+// within сообщает, помещаются ли n байт со смещения offset в data. Смещения и
+// размеры берутся из файла, в том числе 64-битные из ZIP64, и сумма offset+n
+// может переполниться — поэтому сравнивается остаток.
+func within(data []byte, offset, n uint64) bool {
+	size := uint64(len(data))
+	return offset <= size && size-offset >= n
+}
+
+func readCentralDirectory(data []byte) ([]centralEntry, error) {
+	start := len(data) - endOfCentralLen - maxCommentLen
+	if start < 0 {
+		start = 0
+	}
+	eocd := bytes.LastIndex(data[start:], []byte{0x50, 0x4b, 0x05, 0x06})
+	if eocd < 0 || start+eocd+endOfCentralLen > len(data) {
+		return nil, errNoCentralDirectory
+	}
+	eocd += start
+	count := uint64(binary.LittleEndian.Uint16(data[eocd+10:]))
+	offset := uint64(binary.LittleEndian.Uint32(data[eocd+16:]))
+
+	if loc := eocd - 20; loc >= 0 && binary.LittleEndian.Uint32(data[loc:]) == sigZip64Locator {
+		end64 := binary.LittleEndian.Uint64(data[loc+8:])
+		if within(data, end64, 56) && binary.LittleEndian.Uint32(data[end64:]) == sigZip64End {
+			count = binary.LittleEndian.Uint64(data[end64+32:])
+			offset = binary.LittleEndian.Uint64(data[end64+48:])
+		}
+	}
+
+	var entries []centralEntry
+	pos := offset
+	for i := uint64(0); i < count; i++ {
+		if !within(data, pos, centralHeaderLen) || binary.LittleEndian.Uint32(data[pos:]) != sigCentralHeader {
+			return nil, fmt.Errorf("повреждена запись %d центрального каталога", i)
+		}
+		h := data[pos:]
+		nameLen := uint64(binary.LittleEndian.Uint16(h[28:]))
+		extraLen := uint64(binary.LittleEndian.Uint16(h[30:]))
+		commentLen := uint64(binary.LittleEndian.Uint16(h[32:]))
+		if !within(data, pos, centralHeaderLen+nameLen+extraLen+commentLen) {
+			return nil, fmt.Errorf("повреждена запись %d центрального каталога", i)
+		}
+		e := centralEntry{
+			Name:             h[centralHeaderLen : centralHeaderLen+nameLen],
+			Flags:            binary.LittleEndian.Uint16(h[8:]),
+			Method:           binary.LittleEndian.Uint16(h[10:]),
+			CRC32:            binary.LittleEndian.Uint32(h[16:]),
+			CompressedSize:   uint64(binary.LittleEndian.Uint32(h[20:])),
+			UncompressedSize: uint64(binary.LittleEndian.Uint32(h[24:])),
+			HeaderOffset:     uint64(binary.LittleEndian.Uint32(h[42:])),
+		}
+		applyZip64Extra(h[centralHeaderLen+nameLen:centralHeaderLen+nameLen+extraLen],
+			&e.UncompressedSize, &e.CompressedSize, &e.HeaderOffset)
+		entries = append(entries, e)
+		pos += centralHeaderLen + nameLen + extraLen + commentLen
+	}
+	return entries, nil
+}
+
+// applyZip64Extra подставляет 64-битные значения для полей, равных 0xFFFFFFFF,
+// в порядке, заданном спецификацией.
+func applyZip64Extra(extra []byte, fields ...*uint64) {
+	for len(extra) >= 4 {
+		id := binary.LittleEndian.Uint16(extra)
+		size := int(binary.LittleEndian.Uint16(extra[2:]))
+		if 4+size > len(extra) {
+			return
+		}
+		if id == zip64ExtraID {
+			body := extra[4 : 4+size]
+			for _, f := range fields {
+				if *f != zip64Sentinel32 {
+					continue
+				}
+				if len(body) < 8 {
+					return
+				}
+				*f = binary.LittleEndian.Uint64(body)
+				body = body[8:]
+			}
+			return
+		}
+		extra = extra[4+size:]
+	}
+}
+
+func readLocalHeader(data []byte, offset uint64) (*localHeader, error) {
+	if !within(data, offset, localHeaderLen) || binary.LittleEndian.Uint32(data[offset:]) != sigLocalHeader {
+		return nil, fmt.Errorf("по смещению %d нет локального заголовка", offset)
+	}
+	h := data[offset:]
+	nameLen := uint64(binary.LittleEndian.Uint16(h[26:]))
+	extraLen := uint64(binary.LittleEndian.Uint16(h[28:]))
+	if !within(data, offset, localHeaderLen+nameLen+extraLen) {
+		return nil, fmt.Errorf("локальный заголовок по смещению %d обрезан", offset)
+	}
+	l := &localHeader{
+		Name:             h[localHeaderLen : localHeaderLen+nameLen],
+		Flags:            binary.LittleEndian.Uint16(h[6:]),
+		Method:           binary.LittleEndian.Uint16(h[8:]),
+		CRC32:            binary.LittleEndian.Uint32(h[14:]),
+		CompressedSize:   uint64(binary.LittleEndian.Uint32(h[18:])),
+		UncompressedSize: uint64(binary.LittleEndian.Uint32(h[22:])),
+		DataOffset:       offset + localHeaderLen + nameLen + extraLen,
+	}
+	applyZip64Extra(h[localHeaderLen+nameLen:localHeaderLen+nameLen+extraLen], &l.UncompressedSize, &l.CompressedSize)
+	return l, nil
+}
+
+// headerMismatch сравнивает локальный заголовок с записью центрального
+// каталога. Распаковщики по-разному выбирают источник истины, и расхождение
+// позволяет показать проверке одно содержимое, а пользователю другое.
+func headerMismatch(c centralEntry, l *localHeader) string {
+	switch {
+	case !bytes.Equal(c.Name, l.Name):
+		return fmt.Sprintf("имя в локальном заголовке %q", l.Name)
+	case c.Method != l.Method:
+		return fmt.Sprintf("метод сжатия %d вместо %d", l.Method, c.Method)
+	case c.Flags&flagEncrypted != l.Flags&flagEncrypted:
+		return "различается признак шифрования"
+	}
+	if l.Flags&flagDataDescriptor != 0 {
+		// Размеры и CRC хранятся после данных, в локальном заголовке нули.
+		return ""
+	}
+	switch {
+	case c.CRC32 != l.CRC32:
+		return "различается CRC-32"
+	case c.CompressedSize != l.CompressedSize || c.UncompressedSize != l.UncompressedSize:
+		return "различаются размеры записи"
+	}
+	return ""
+}
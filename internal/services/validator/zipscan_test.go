@@ -0,0 +1,191 @@
+package validator
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// createDOCXWithEntries дописывает к минимальному DOCX записи с заданными
+// заголовками; порядок и повторы имён сохраняются.
+func createDOCXWithEntries(extra ...zip.FileHeader) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8"?>`},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8"?>`},
+		{"word/document.xml", `<?xml version="1.0" encoding="UTF-8"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>Текст от 01.02.2024</w:t></w:r></w:p></w:body></w:document>`},
+	}
+	for _, part := range parts {
+		w, _ := zw.Create(part.name)
+		w.Write([]byte(part.content))
+	}
+	for i := range extra {
+		w, _ := zw.CreateHeader(&extra[i])
+		w.Write([]byte("content"))
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+func zipRulesEngine(t *testing.T) *Engine {
+	engine, err := profileEngine(configWithRules(ruleSafePaths, ruleZipIntegrity))
+	require.NoError(t, err)
+	return engine
+}
+
+func findingIssues(res RuleResult) []string {
+	var issues []string
+	for _, f := range res.Details["findings"].([]map[string]interface{}) {
+		issues = append(issues, f["issue"].(string))
+	}
+	return issues
+}
+
+func TestSafePaths_ReportsEveryFinding(t *testing.T) {
+	payload := createDOCXWithEntries(
+		zip.FileHeader{Name: "/etc/passwd"},
+		zip.FileHeader{Name: `word\media\image.png`},
+		zip.FileHeader{Name: "word/media/../../evil.txt"},
+		zip.FileHeader{Name: "word/a\x00.xml"},
+		zip.FileHeader{Name: "word/document.xml"},
+		zip.FileHeader{Name: "Word/Document.XML"},
+		zip.FileHeader{Name: "C:/evil.txt"},
+	)
+
+	report := zipRulesEngine(t).Run(Request{Payload: payload})
+	res, ok := report.Result(ruleSafePaths)
+	require.True(t, ok)
+	require.Equal(t, CodeSuspiciousPath, res.Code)
+	require.Contains(t, res.Message, "подозрительный путь в ZIP: /etc/passwd")
+	require.Contains(t, res.Message, "и ещё 6")
+	require.Equal(t, "/etc/passwd", res.Details["path"])
+	require.Equal(t, []string{
+		issueAbsolutePath, issueBackslash, issueDotSegment, issueNulByte,
+		issueDuplicate, issueCaseCollision, issueAbsolutePath,
+	}, findingIssues(res))
+}
+
+func TestSafePaths_AllowsOrdinaryNames(t *testing.T) {
+	payload := createDOCXWithEntries(
+		zip.FileHeader{Name: "word/media/image..png"},
+		zip.FileHeader{Name: "customXml/item1.xml"},
+	)
+
+	report := zipRulesEngine(t).Run(Request{Payload: payload})
+	require.True(t, report.Valid(), report.Failures())
+	res, _ := report.Result(ruleZipIntegrity)
+	require.Equal(t, 5, res.Details["entries"])
+}
+
+func TestZipIntegrity_EncryptedEntry(t *testing.T) {
+	payload := createDOCXWithEntries(zip.FileHeader{Name: "word/secret.bin", Flags: flagEncrypted})
+
+	report := zipRulesEngine(t).Run(Request{Payload: payload})
+	res, ok := report.Result(ruleZipIntegrity)
+	require.True(t, ok)
+	require.Equal(t, CodeEncryptedEntry, res.Code)
+	require.Contains(t, res.Message, "word/secret.bin")
+}
+
+func TestZipIntegrity_LocalHeaderMismatch(t *testing.T) {
+	payload := createDOCXWithEntries(zip.FileHeader{Name: "word/extra.xml"})
+	// Первое вхождение имени — локальный заголовок; центральный каталог не меняется.
+	idx := bytes.Index(payload, []byte("word/extra.xml"))
+	require.True(t, idx > 0)
+	payload[idx] = 'W'
+
+	report := zipRulesEngine(t).Run(Request{Payload: payload})
+	res, ok := report.Result(ruleZipIntegrity)
+	require.True(t, ok)
+	require.Equal(t, CodeInconsistentArchive, res.Code)
+	require.Equal(t, []string{issueHeaderMismatch}, findingIssues(res))
+}
+
+func TestZipIntegrity_NotApplicableToPDF(t *testing.T) {
+	engine := zipRulesEngine(t)
+
+	res, ok := engine.Run(Request{Payload: createPDFWithText("Договор")}).Result(ruleZipIntegrity)
+	require.True(t, ok)
+	require.Equal(t, StatusSkipped, res.Status)
+}
+
+func TestZipIntegrity_Zip64OffsetOverflow(t *testing.T) {
+	// Смещение локального заголовка из ZIP64 близко к 2^64: сумма со
+	// смещением заголовка переполняется и не должна проходить проверку границ.
+	extra := make([]byte, 12)
+	binary.LittleEndian.PutUint16(extra, zip64ExtraID)
+	binary.LittleEndian.PutUint16(extra[2:], 8)
+	binary.LittleEndian.PutUint64(extra[4:], 0xFFFFFFFFFFFFFFF0)
+	payload := createDOCXWithEntries(zip.FileHeader{Name: "word/media/x.bin", Extra: extra})
+
+	name := []byte("word/media/x.bin")
+	central := bytes.LastIndex(payload, name) - centralHeaderLen
+	require.Equal(t, uint32(sigCentralHeader), binary.LittleEndian.Uint32(payload[central:]))
+	binary.LittleEndian.PutUint32(payload[central+42:], zip64Sentinel32)
+
+	_, err := readLocalHeader(payload, 0xFFFFFFFFFFFFFFF0)
+	require.Error(t, err)
+
+	report := zipRulesEngine(t).Run(Request{Payload: payload})
+	res, ok := report.Result(ruleZipIntegrity)
+	require.True(t, ok)
+	require.Equal(t, CodeInconsistentArchive, res.Code)
+	require.Contains(t, res.Message, "word/media/x.bin")
+}
+
+func TestZipIntegrity_EntryCoversSeveralEntries(t *testing.T) {
+	// Данные word/a.bin содержат записи b и c целиком; центральный каталог
+	// указывает на них внутрь a. Пересечение c с a видно только при сравнении
+	// с записью, заканчивающейся дальше всех, а не с предыдущей.
+	stored := func(zw *zip.Writer, name, content string) {
+		w, err := zw.CreateRaw(&zip.FileHeader{
+			Name: name, Method: zip.Store, CRC32: crc32.ChecksumIEEE([]byte(content)),
+			CompressedSize64: uint64(len(content)), UncompressedSize64: uint64(len(content)),
+		})
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	var inner bytes.Buffer
+	iw := zip.NewWriter(&inner)
+	stored(iw, "word/b.bin", "bbbb")
+	require.NoError(t, iw.Flush())
+	cOffset := inner.Len()
+	stored(iw, "word/c.bin", "cccc")
+	require.NoError(t, iw.Flush())
+	nested := inner.String() + "tail"
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	stored(zw, "[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8"?>`)
+	stored(zw, "word/document.xml", `<?xml version="1.0" encoding="UTF-8"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body/></w:document>`)
+	require.NoError(t, zw.Flush())
+	aData := buf.Len() + localHeaderLen + len("word/a.bin")
+	stored(zw, "word/a.bin", nested)
+	stored(zw, "word/b.bin", "bbbb")
+	stored(zw, "word/c.bin", "cccc")
+	require.NoError(t, zw.Close())
+	payload := buf.Bytes()
+
+	for name, offset := range map[string]int{"word/b.bin": aData, "word/c.bin": aData + cOffset} {
+		central := bytes.LastIndex(payload, []byte(name)) - centralHeaderLen
+		require.Equal(t, uint32(sigCentralHeader), binary.LittleEndian.Uint32(payload[central:]))
+		binary.LittleEndian.PutUint32(payload[central+42:], uint32(offset))
+	}
+
+	report := zipRulesEngine(t).Run(Request{Payload: payload})
+	res, ok := report.Result(ruleZipIntegrity)
+	require.True(t, ok)
+	require.Equal(t, CodeInconsistentArchive, res.Code)
+	require.Equal(t, []string{issueOverlap, issueOverlap}, findingIssues(res))
+	for _, f := range res.Details["findings"].([]map[string]interface{}) {
+		require.Contains(t, f["detail"], "word/a.bin")
+	}
+}
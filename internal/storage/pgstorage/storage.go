@@ -10,7 +10,8 @@ import (
 
 
 type StorageInterface interface {
-	InsertEvent(ctx context.Context, key string, payload []byte) error
+	// InsertEvent сохраняет документ; metadata — JSON свойств документа или nil.
+	InsertEvent(ctx context.Context, key string, payload, metadata []byte) error
 	PrimaryPool() *pgxpool.Pool
 	Close()
 }
@@ -36,12 +37,18 @@ func (s *Storage) Close() {
 }
 
 
-func (s *Storage) InsertEvent(ctx context.Context, key string, payload []byte) error {
+func (s *Storage) InsertEvent(ctx context.Context, key string, payload, metadata []byte) error {
 	pool := s.manager.ShardForKey(key)
 	if pool == nil {
 		return fmt.Errorf("нет шарда для ключа")
 	}
-	_, err := pool.Exec(ctx, "INSERT INTO validator_events (key, payload) VALUES ($1, $2)", key, payload)
+	// Колонку metadata добавляет migrations/001_validator_events_metadata.sql;
+	// события без свойств пишутся и в схему без неё.
+	if metadata == nil {
+		_, err := pool.Exec(ctx, "INSERT INTO validator_events (key, payload) VALUES ($1, $2)", key, payload)
+		return err
+	}
+	_, err := pool.Exec(ctx, "INSERT INTO validator_events (key, payload, metadata) VALUES ($1, $2, $3)", key, payload, metadata)
 	return err
 }
 
@@ -0,0 +1,3 @@
+-- Свойства документа (автор, даты, организация) для validator_events.
+-- Применяется к каждому шарду из DB_SHARDS до выкладки версии, которая пишет metadata.
+ALTER TABLE validator_events ADD COLUMN IF NOT EXISTS metadata jsonb;